@@ -0,0 +1,14 @@
+// Package api holds the OpenAPI specification of the HTTP API.
+package api
+
+import _ "embed"
+
+// Spec is the OpenAPI document in openapi.yaml
+//
+//go:embed openapi.yaml
+var Spec []byte
+
+// BackupSchema is the JSON Schema in backup.schema.json that backup imports must match
+//
+//go:embed backup.schema.json
+var BackupSchema []byte
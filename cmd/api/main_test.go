@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/para7/nanaket-cms/internal/middleware"
+)
+
+// captureLogs sends the default logger's records to a buffer as JSON until the test ends
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+	return &buf
+}
+
+func TestLoggingMiddlewareStatus(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /hello", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+	})
+	handler := loggingMiddleware(mux)
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/hello", http.StatusOK},
+		{"/missing", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			logs := captureLogs(t)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			var entry struct {
+				Path   string `json:"path"`
+				Status int    `json:"status"`
+				Bytes  int    `json:"bytes"`
+			}
+			if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+				t.Fatalf("decode log entry %q: %v", logs.String(), err)
+			}
+			if entry.Path != tt.path || entry.Status != tt.status {
+				t.Errorf("logged %s with status %d, want %s with %d", entry.Path, entry.Status, tt.path, tt.status)
+			}
+			if entry.Bytes != rec.Body.Len() {
+				t.Errorf("logged %d bytes, wrote %d", entry.Bytes, rec.Body.Len())
+			}
+		})
+	}
+}
+
+func TestLoggingMiddlewareFlusher(t *testing.T) {
+	handler := loggingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := w.(http.Flusher); !ok {
+			t.Error("response writer does not implement http.Flusher")
+		}
+	}))
+	captureLogs(t)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func TestMuxErrorMiddleware(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/articles", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	})
+	handler := muxErrorMiddleware(mux)
+
+	tests := []struct {
+		method, path string
+		status       int
+		code         string
+	}{
+		{http.MethodGet, "/api/v1/nonexistent", http.StatusNotFound, "not_found"},
+		{http.MethodPost, "/api/v1/articles", http.StatusMethodNotAllowed, "method_not_allowed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", ct)
+			}
+			var body struct {
+				Error string `json:"error"`
+				Code  string `json:"code"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if body.Code != tt.code || body.Error == "" {
+				t.Errorf("body = %+v, want code %q", body, tt.code)
+			}
+		})
+	}
+
+	// Registered routes are not shadowed
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/articles", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "[]" {
+		t.Errorf("GET /api/v1/articles = %d %q, want 200 []", rec.Code, rec.Body.String())
+	}
+}
+
+func TestRecoveryMiddleware(t *testing.T) {
+	handler := middleware.RequestID(recoveryMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})))
+	logs := captureLogs(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/articles", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-123")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	var body struct {
+		Error     string `json:"error"`
+		Code      string `json:"code"`
+		RequestID string `json:"request_id"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body.Code != "internal_error" || body.Error == "" || body.RequestID != "req-123" {
+		t.Errorf("body = %+v, want code internal_error and request ID req-123", body)
+	}
+	// The panic value goes to the log, not to the client
+	if !strings.Contains(logs.String(), "boom") {
+		t.Errorf("panic value missing from logs: %s", logs.String())
+	}
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/para7/nanaket-cms/internal/config"
+	"github.com/para7/nanaket-cms/internal/metrics"
+	"github.com/para7/nanaket-cms/internal/middleware"
+)
+
+// access is the authentication a route requires
+type access int
+
+const (
+	// accessPublic routes need no authentication
+	accessPublic access = iota
+	// accessOptional routes use a token when one is sent (e.g. to include drafts)
+	accessOptional
+	// accessAuth routes require a valid token
+	accessAuth
+	// accessAdmin routes require a valid token of an admin
+	accessAdmin
+)
+
+// String returns the name used for a in the manifest
+func (a access) String() string {
+	switch a {
+	case accessOptional:
+		return "optional"
+	case accessAuth:
+		return "required"
+	case accessAdmin:
+		return "admin"
+	default:
+		return "none"
+	}
+}
+
+// route is one entry of the route registry.
+// setupRoutes registers every route from the registry, and the manifest is built from it too.
+type route struct {
+	method string
+	// path is a ServeMux path pattern; a trailing "{$}" matches the path exactly
+	path    string
+	access  access
+	handler http.Handler
+}
+
+// routeGuards wraps handlers in the authentication middleware for each access level
+type routeGuards struct {
+	optionalAuth func(http.Handler) http.Handler
+	auth         func(http.Handler) http.Handler
+	requireAdmin func(http.Handler) http.Handler
+	// quota counts requests to mutating routes against the daily quota of the authenticated user
+	quota func(http.Handler) http.Handler
+}
+
+// wrap applies the middleware that enforces a
+func (g routeGuards) wrap(a access, h http.Handler) http.Handler {
+	switch a {
+	case accessOptional:
+		return g.optionalAuth(h)
+	case accessAuth:
+		return g.auth(h)
+	case accessAdmin:
+		return g.auth(g.requireAdmin(h))
+	default:
+		return h
+	}
+}
+
+// registerRoutes registers every route on mux behind its authentication middleware.
+// Routes with a mutating method also count against the user quota once the user is known;
+// public ones check a token for that when one is sent.
+// Requests are recorded in registry under the route pattern, including those the middleware rejects.
+func registerRoutes(mux *http.ServeMux, routes []route, guards routeGuards, registry *metrics.Registry) {
+	for _, rt := range routes {
+		pattern := rt.method + " " + rt.path
+		h := rt.handler
+		if isMutatingMethod(rt.method) {
+			h = guards.quota(h)
+			// Public routes learn the user from a token when one is sent, so the quota counts them too
+			if rt.access == accessPublic {
+				h = guards.optionalAuth(h)
+			}
+		}
+		mux.Handle(pattern, middleware.Metrics(registry, pattern)(guards.wrap(rt.access, h)))
+	}
+}
+
+// isMutatingMethod reports whether requests with method may change data
+func isMutatingMethod(method string) bool {
+	return method != http.MethodGet && method != http.MethodHead && method != http.MethodOptions
+}
+
+// probeMethods are the methods tried when answering OPTIONS
+var probeMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// optionsMiddleware answers OPTIONS requests with 204 and an Allow header listing the
+// methods registered for the path, found by asking mux which patterns would match.
+// Per-path OPTIONS patterns cannot be registered instead: routes told apart only by
+// their method (e.g. users/{id}/restore and users/by-email/{email}) would conflict.
+// CORS preflight requests are answered before reaching this middleware.
+// Paths with no registered method fall through to next, which answers 404.
+func optionsMiddleware(mux *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var allowed []string
+		probe := r.Clone(r.Context())
+		for _, method := range probeMethods {
+			probe.Method = method
+			if _, pattern := mux.Handler(probe); pattern != "" {
+				allowed = append(allowed, method)
+			}
+		}
+		if len(allowed) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Like ServeMux's own 405 responses, GET implies HEAD
+		if slices.Contains(allowed, http.MethodGet) {
+			allowed = append(allowed, http.MethodHead)
+		}
+		allowed = append(allowed, http.MethodOptions)
+		slices.Sort(allowed)
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+const (
+	// trailingSlashOff leaves paths as they are, so /api/v1/articles/ is 404
+	trailingSlashOff = "off"
+	// trailingSlashRedirect answers 308 with the path without the trailing slash
+	trailingSlashRedirect = "redirect"
+	// trailingSlashRewrite serves the path without the trailing slash directly
+	trailingSlashRewrite = "rewrite"
+)
+
+// trailingSlashMiddleware makes paths with a trailing slash reach the route registered
+// without one, by redirecting (308, which keeps the method and body) or by rewriting
+// the path, depending on mode (config.TrailingSlashRedirect or config.TrailingSlashRewrite;
+// config.TrailingSlashOff disables it). Only paths that mux does not route as they are
+// but would route without the slash are touched, so routes registered with a trailing
+// slash (/api/v1/ and /media/) and the root path keep working as before.
+func trailingSlashMiddleware(mux *http.ServeMux, mode string, next http.Handler) http.Handler {
+	if mode == config.TrailingSlashOff {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		trimmed := strings.TrimRight(r.URL.Path, "/")
+		if trimmed == r.URL.Path || trimmed == "" || routed(mux, r, r.URL.Path) || !routed(mux, r, trimmed) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if mode == config.TrailingSlashRedirect {
+			target := trimmed
+			if r.URL.RawQuery != "" {
+				target += "?" + r.URL.RawQuery
+			}
+			http.Redirect(w, r, target, http.StatusPermanentRedirect)
+			return
+		}
+
+		// Rewrite on a shallow copy, as http.StripPrefix does
+		u := *r.URL
+		u.Path = trimmed
+		u.RawPath = strings.TrimRight(u.RawPath, "/")
+		r2 := new(http.Request)
+		*r2 = *r
+		r2.URL = &u
+		next.ServeHTTP(w, r2)
+	})
+}
+
+// routed reports whether mux has a route for the method of r at path.
+// OPTIONS requests count as routed when any method is, as optionsMiddleware answers them.
+func routed(mux *http.ServeMux, r *http.Request, path string) bool {
+	probe := r.Clone(r.Context())
+	probe.URL.Path = path
+	probe.URL.RawPath = ""
+	methods := []string{r.Method}
+	if r.Method == http.MethodOptions {
+		methods = probeMethods
+	}
+	for _, method := range methods {
+		probe.Method = method
+		if _, pattern := mux.Handler(probe); pattern != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// manifestRoute describes one route in the manifest
+type manifestRoute struct {
+	Method       string `json:"method"`
+	Path         string `json:"path"`
+	AuthRequired bool   `json:"auth_required"`
+	// Auth is "none", "optional", "required" or "admin"
+	Auth string `json:"auth"`
+}
+
+// manifestResponse is the body of GET /api/v1/
+type manifestResponse struct {
+	Routes []manifestRoute `json:"routes"`
+}
+
+// manifestHandler returns a handler listing routes in registration order.
+// The manifest is built once, since the registry does not change after startup.
+func manifestHandler(routes []route) http.HandlerFunc {
+	resp := manifestResponse{Routes: make([]manifestRoute, len(routes))}
+	for i, rt := range routes {
+		resp.Routes[i] = manifestRoute{
+			Method:       rt.method,
+			Path:         strings.TrimSuffix(rt.path, "{$}"),
+			AuthRequired: rt.access == accessAuth || rt.access == accessAdmin,
+			Auth:         rt.access.String(),
+		}
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(resp)
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/para7/nanaket-cms/internal/config"
+	"github.com/para7/nanaket-cms/internal/db"
+	"github.com/para7/nanaket-cms/internal/metrics"
+	"github.com/para7/nanaket-cms/internal/middleware"
+)
+
+func TestRegisterRoutesQuotaSeesUser(t *testing.T) {
+	// Every guard that authenticates stores the same user, standing in for a valid token
+	withUser := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), middleware.UserContextKey, db.User{ID: 1})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+	var counted []string
+	guards := routeGuards{
+		optionalAuth: withUser,
+		auth:         withUser,
+		requireAdmin: func(next http.Handler) http.Handler { return next },
+		quota: func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if _, ok := middleware.GetUserFromContext(r.Context()); ok {
+					counted = append(counted, r.Method+" "+r.URL.Path)
+				}
+				next.ServeHTTP(w, r)
+			})
+		},
+	}
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	mux := http.NewServeMux()
+	registerRoutes(mux, []route{
+		{http.MethodGet, "/public", accessPublic, ok},
+		{http.MethodPost, "/public", accessPublic, ok},
+		{http.MethodPost, "/optional", accessOptional, ok},
+		{http.MethodPost, "/auth", accessAuth, ok},
+	}, guards, metrics.NewRegistry())
+
+	for _, req := range []struct{ method, path string }{
+		{http.MethodGet, "/public"},
+		{http.MethodPost, "/public"},
+		{http.MethodPost, "/optional"},
+		{http.MethodPost, "/auth"},
+	} {
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(req.method, req.path, nil))
+	}
+
+	want := []string{"POST /public", "POST /optional", "POST /auth"}
+	if !slices.Equal(counted, want) {
+		t.Errorf("counted %v, want %v", counted, want)
+	}
+}
+
+func TestTrailingSlashMiddleware(t *testing.T) {
+	mux := http.NewServeMux()
+	for _, pattern := range []string{"GET /{$}", "GET /api/v1/articles", "GET /api/v1/articles/{id}", "GET /media/"} {
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(r.URL.Path + "?" + r.URL.RawQuery))
+		})
+	}
+
+	tests := []struct {
+		mode     string
+		path     string
+		status   int
+		location string // for redirects
+		body     string // served path and query otherwise
+	}{
+		{config.TrailingSlashRedirect, "/api/v1/articles/?page=2", http.StatusPermanentRedirect, "/api/v1/articles?page=2", ""},
+		{config.TrailingSlashRedirect, "/api/v1/articles/1/", http.StatusPermanentRedirect, "/api/v1/articles/1", ""},
+		{config.TrailingSlashRedirect, "/api/v1/articles", http.StatusOK, "", "/api/v1/articles?"},
+		{config.TrailingSlashRedirect, "/", http.StatusOK, "", "/?"},
+		{config.TrailingSlashRedirect, "/media/", http.StatusOK, "", "/media/?"},
+		{config.TrailingSlashRedirect, "/missing/", http.StatusNotFound, "", ""},
+		{config.TrailingSlashRewrite, "/api/v1/articles/?page=2", http.StatusOK, "", "/api/v1/articles?page=2"},
+		{config.TrailingSlashRewrite, "/api/v1/articles/1/", http.StatusOK, "", "/api/v1/articles/1?"},
+		{config.TrailingSlashRewrite, "/", http.StatusOK, "", "/?"},
+		{config.TrailingSlashOff, "/api/v1/articles/", http.StatusNotFound, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode+" "+tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			trailingSlashMiddleware(mux, tt.mode, mux).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if tt.location != "" {
+				if got := rec.Header().Get("Location"); got != tt.location {
+					t.Errorf("Location = %q, want %q", got, tt.location)
+				}
+			}
+			if tt.body != "" && rec.Body.String() != tt.body {
+				t.Errorf("served %q, want %q", rec.Body.String(), tt.body)
+			}
+		})
+	}
+}
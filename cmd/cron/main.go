@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/para7/nanaket-cms/internal/config"
+	"github.com/para7/nanaket-cms/internal/db"
+	"github.com/para7/nanaket-cms/internal/logger"
+	"github.com/para7/nanaket-cms/internal/repository"
+	"github.com/para7/nanaket-cms/internal/storage"
+	"github.com/para7/nanaket-cms/internal/usecase"
+	"github.com/para7/nanaket-cms/internal/webhook"
+)
+
+// runTimeout bounds a single scheduled run
+const runTimeout = 5 * time.Minute
+
+// cron runs scheduled jobs once and exits.
+// Invoke it periodically from the scheduler of the deployment (crontab, Kubernetes CronJob, etc.).
+func main() {
+	slog.SetDefault(logger.New(os.Stdout, logger.ParseLevel(os.Getenv("LOG_LEVEL"))))
+
+	// Settings shared with the API server
+	cfg, err := config.Load()
+	if err != nil {
+		slog.Error("Invalid configuration", "error", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), runTimeout)
+	defer cancel()
+
+	pool, err := pgxpool.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		slog.Error("Unable to connect to database", "error", err)
+		os.Exit(1)
+	}
+	defer pool.Close()
+
+	// Media store (only used to build featured image URLs; same defaults as the API server)
+	mediaStore, err := storage.NewLocalStore(cfg.Media.Dir, cfg.Media.BaseURL)
+	if err != nil {
+		slog.Error("Unable to create media directory", "error", err)
+		pool.Close()
+		os.Exit(1)
+	}
+
+	queries := db.New(pool)
+	articleUsecase := usecase.NewArticleUsecase(
+		repository.NewArticleRepository(queries),
+		repository.NewTagRepository(queries),
+		repository.NewMediaRepository(queries),
+		mediaStore,
+		repository.NewCategoryRepository(queries),
+		repository.NewUserRepository(queries),
+		repository.NewIdempotencyRepository(queries),
+		repository.NewArticleRevisionRepository(queries),
+		repository.NewArticleTranslationRepository(queries),
+		repository.NewTransactor(pool),
+		cfg.Articles.MaxPublishAhead,
+		cfg.Articles.MaxPinned,
+		cfg.Articles.DefaultLocale,
+		false, // cron jobs never write article content
+	)
+	webhookUsecase := usecase.NewWebhookUsecase(
+		repository.NewWebhookRepository(queries),
+		webhook.NewHTTPSender(cfg.WebhookTimeout),
+		cfg.WebhookTimeout,
+	)
+	// Only DeleteExpiredLinks is used, so no mailer or link URL is needed
+	magicLinkUsecase := usecase.NewMagicLinkUsecase(
+		repository.NewLoginLinkRepository(queries),
+		repository.NewAuthRepository(queries),
+		nil,
+		"",
+		"",
+		usecase.DefaultMagicLinkTTL,
+	)
+
+	// Only DeleteExpired is used, so the limit does not matter
+	quotaUsecase := usecase.NewQuotaUsecase(repository.NewQuotaRepository(queries), usecase.DefaultUserWriteQuota)
+
+	// Run every job even if an earlier one failed
+	ok := publishScheduledArticles(ctx, articleUsecase, webhookUsecase)
+	ok = deleteExpiredIdempotencyKeys(ctx, articleUsecase) && ok
+	ok = deleteExpiredLoginLinks(ctx, magicLinkUsecase) && ok
+	ok = assignMissingPublicIDs(ctx, articleUsecase) && ok
+	ok = deleteExpiredQuotaUsage(ctx, quotaUsecase) && ok
+	if !ok {
+		pool.Close()
+		os.Exit(1)
+	}
+}
+
+// publishScheduledArticles publishes drafts whose published_at has passed.
+// Webhooks are notified of each published article before the run exits.
+// It reports whether the job completed without errors.
+func publishScheduledArticles(ctx context.Context, articleUsecase usecase.ArticleUsecase, webhookUsecase usecase.WebhookUsecase) bool {
+	published, err := articleUsecase.PublishScheduledArticles(ctx)
+	for _, article := range published {
+		slog.Info("Published scheduled article", "article_id", article.ID, "published_at", article.PublishedAt.Time)
+		detail, err := articleUsecase.GetArticle(ctx, article.ID)
+		if err != nil {
+			slog.Error("Loading published article for webhooks failed", "article_id", article.ID, "error", err)
+			continue
+		}
+		webhookUsecase.Dispatch(ctx, usecase.EventArticleUpdated, detail)
+	}
+	slog.Info("Scheduled publishing finished", "published", len(published))
+	if err != nil {
+		slog.Error("Scheduled publishing failed", "error", err)
+		return false
+	}
+	return true
+}
+
+// deleteExpiredIdempotencyKeys removes idempotency keys past their 24-hour lifetime.
+// It reports whether the job completed without errors.
+func deleteExpiredIdempotencyKeys(ctx context.Context, articleUsecase usecase.ArticleUsecase) bool {
+	deleted, err := articleUsecase.DeleteExpiredIdempotencyKeys(ctx)
+	if err != nil {
+		slog.Error("Deleting expired idempotency keys failed", "error", err)
+		return false
+	}
+	slog.Info("Expired idempotency keys deleted", "deleted", deleted)
+	return true
+}
+
+// deleteExpiredLoginLinks removes magic login links past their expiry.
+// It reports whether the job completed without errors.
+func deleteExpiredLoginLinks(ctx context.Context, magicLinkUsecase usecase.MagicLinkUsecase) bool {
+	deleted, err := magicLinkUsecase.DeleteExpiredLinks(ctx)
+	if err != nil {
+		slog.Error("Deleting expired login links failed", "error", err)
+		return false
+	}
+	slog.Info("Expired login links deleted", "deleted", deleted)
+	return true
+}
+
+// assignMissingPublicIDs gives public IDs to articles created before they were introduced.
+// It reports whether the job completed without errors.
+func assignMissingPublicIDs(ctx context.Context, articleUsecase usecase.ArticleUsecase) bool {
+	assigned, err := articleUsecase.AssignMissingPublicIDs(ctx)
+	if err != nil {
+		slog.Error("Assigning public IDs failed", "error", err, "assigned", assigned)
+		return false
+	}
+	slog.Info("Missing public IDs assigned", "assigned", assigned)
+	return true
+}
+
+// deleteExpiredQuotaUsage removes the user quota counts of days before today (UTC).
+// It reports whether the job completed without errors.
+func deleteExpiredQuotaUsage(ctx context.Context, quotaUsecase usecase.QuotaUsecase) bool {
+	deleted, err := quotaUsecase.DeleteExpired(ctx, time.Now())
+	if err != nil {
+		slog.Error("Deleting expired quota usage failed", "error", err)
+		return false
+	}
+	slog.Info("Expired quota usage deleted", "deleted", deleted)
+	return true
+}
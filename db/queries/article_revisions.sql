@@ -0,0 +1,15 @@
+-- name: CreateArticleRevision :execrows
+-- 更新前の記事を保存する。行ロックを取るため、同じトランザクション内の更新と食い違わない
+INSERT INTO article_revisions (article_id, version, title, content)
+SELECT id, version, title, content FROM articles
+WHERE id = $1 AND deleted_at IS NULL
+FOR UPDATE;
+
+-- name: GetArticleRevision :one
+SELECT * FROM article_revisions
+WHERE id = $1 AND article_id = $2 LIMIT 1;
+
+-- name: ListArticleRevisions :many
+SELECT * FROM article_revisions
+WHERE article_id = $1
+ORDER BY id DESC;
@@ -0,0 +1,12 @@
+-- name: UpsertArticleTranslation :one
+INSERT INTO article_translations (article_id, locale, title, content)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (article_id, locale) DO UPDATE
+SET title = EXCLUDED.title, content = EXCLUDED.content
+RETURNING *;
+
+-- name: ListArticleTranslations :many
+-- 一覧表示用。記事ごとにどの言語を使うかはアプリケーション側で希望順に選ぶ
+SELECT * FROM article_translations
+WHERE article_id = ANY(sqlc.arg(article_ids)::bigint[])
+  AND locale = ANY(sqlc.arg(locales)::text[]);
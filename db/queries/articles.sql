@@ -1,31 +1,414 @@
 -- name: GetArticle :one
 SELECT * FROM articles
-WHERE id = $1 LIMIT 1;
+WHERE id = $1 AND deleted_at IS NULL LIMIT 1;
+
+-- name: GetArticleBySlug :one
+SELECT * FROM articles
+WHERE slug = $1 AND deleted_at IS NULL LIMIT 1;
+
+-- name: GetArticleByPublicID :one
+SELECT * FROM articles
+WHERE public_id = $1 AND deleted_at IS NULL LIMIT 1;
+
+-- name: ArticleSlugExists :one
+SELECT EXISTS (
+    SELECT 1 FROM articles
+    WHERE slug = $1
+);
 
 -- name: ListArticles :many
 SELECT * FROM articles
+WHERE deleted_at IS NULL
 ORDER BY id;
 
 -- name: CreateArticle :one
 INSERT INTO articles (
-    user_id, title, content, published_at
+    user_id, title, content, published_at, status, slug, featured_image_id, category_id, public_id
 ) VALUES (
-    $1, $2, $3, $4
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
 )
 RETURNING *;
 
 -- name: UpdateArticle :one
 UPDATE articles
-SET user_id = $1, title = $2, content = $3, published_at = $4, updated_at = CURRENT_TIMESTAMP
-WHERE id = $5
+SET user_id = $1, title = $2, content = $3, published_at = $4, status = $5, featured_image_id = $6, category_id = $7, version = version + 1, updated_at = CURRENT_TIMESTAMP
+WHERE id = $8 AND deleted_at IS NULL
+  AND (sqlc.narg(expected_version)::int IS NULL OR version = sqlc.narg(expected_version))
+RETURNING *;
+
+-- name: SoftDeleteArticle :execrows
+UPDATE articles
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: SoftDeleteArticles :many
+UPDATE articles
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id = ANY(sqlc.arg(ids)::bigint[]) AND deleted_at IS NULL
+RETURNING id;
+
+-- name: RestoreArticle :one
+UPDATE articles
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
 RETURNING *;
 
--- name: DeleteArticle :exec
+-- name: HardDeleteArticle :execrows
 DELETE FROM articles
 WHERE id = $1;
 
+-- name: ListArticlesByID :many
+-- 既定の並び順（ピン留めの後は ID の降順）。cursor_* に前ページ最後の記事を渡すキーセットページングで、cursor_id が NULL なら先頭ページ
+SELECT * FROM articles
+WHERE deleted_at IS NULL
+  AND status = sqlc.arg(status)
+  AND (sqlc.narg(tag)::text IS NULL OR EXISTS (
+      SELECT 1 FROM article_tags at
+      INNER JOIN tags t ON t.id = at.tag_id
+      WHERE at.article_id = articles.id AND t.name = sqlc.narg(tag)
+  ))
+  AND (sqlc.narg(category_ids)::bigint[] IS NULL OR category_id = ANY(sqlc.narg(category_ids)::bigint[]))
+  AND (sqlc.narg(published_from)::timestamp IS NULL OR published_at >= sqlc.narg(published_from))
+  AND (sqlc.narg(published_to)::timestamp IS NULL OR published_at <= sqlc.narg(published_to))
+  AND (status <> 'published' OR published_at IS NULL OR published_at <= CURRENT_TIMESTAMP)
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR is_pinned < sqlc.arg(cursor_pinned)::boolean
+    OR (is_pinned = sqlc.arg(cursor_pinned) AND id < sqlc.narg(cursor_id)))
+ORDER BY is_pinned DESC, id DESC
+LIMIT sqlc.arg(max_results);
+
+-- name: ListArticlesByCreatedAt :many
+-- ListArticlesByID と同じく (ピン留め, 作成日時, ID) のキーセットページング
+SELECT * FROM articles
+WHERE deleted_at IS NULL
+  AND status = sqlc.arg(status)
+  AND (sqlc.narg(tag)::text IS NULL OR EXISTS (
+      SELECT 1 FROM article_tags at
+      INNER JOIN tags t ON t.id = at.tag_id
+      WHERE at.article_id = articles.id AND t.name = sqlc.narg(tag)
+  ))
+  AND (sqlc.narg(category_ids)::bigint[] IS NULL OR category_id = ANY(sqlc.narg(category_ids)::bigint[]))
+  AND (sqlc.narg(published_from)::timestamp IS NULL OR published_at >= sqlc.narg(published_from))
+  AND (sqlc.narg(published_to)::timestamp IS NULL OR published_at <= sqlc.narg(published_to))
+  AND (status <> 'published' OR published_at IS NULL OR published_at <= CURRENT_TIMESTAMP)
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR is_pinned < sqlc.arg(cursor_pinned)::boolean
+    OR (is_pinned = sqlc.arg(cursor_pinned) AND (
+        created_at > sqlc.narg(cursor_time)::timestamp
+        OR (created_at = sqlc.narg(cursor_time) AND id < sqlc.narg(cursor_id))
+    )))
+ORDER BY is_pinned DESC, created_at, id DESC
+LIMIT sqlc.arg(max_results);
+
+-- name: ListArticlesByCreatedAtDesc :many
+-- ListArticlesByID と同じく (ピン留め, 作成日時, ID) のキーセットページング
+SELECT * FROM articles
+WHERE deleted_at IS NULL
+  AND status = sqlc.arg(status)
+  AND (sqlc.narg(tag)::text IS NULL OR EXISTS (
+      SELECT 1 FROM article_tags at
+      INNER JOIN tags t ON t.id = at.tag_id
+      WHERE at.article_id = articles.id AND t.name = sqlc.narg(tag)
+  ))
+  AND (sqlc.narg(category_ids)::bigint[] IS NULL OR category_id = ANY(sqlc.narg(category_ids)::bigint[]))
+  AND (sqlc.narg(published_from)::timestamp IS NULL OR published_at >= sqlc.narg(published_from))
+  AND (sqlc.narg(published_to)::timestamp IS NULL OR published_at <= sqlc.narg(published_to))
+  AND (status <> 'published' OR published_at IS NULL OR published_at <= CURRENT_TIMESTAMP)
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR is_pinned < sqlc.arg(cursor_pinned)::boolean
+    OR (is_pinned = sqlc.arg(cursor_pinned) AND (
+        created_at < sqlc.narg(cursor_time)::timestamp
+        OR (created_at = sqlc.narg(cursor_time) AND id < sqlc.narg(cursor_id))
+    )))
+ORDER BY is_pinned DESC, created_at DESC, id DESC
+LIMIT sqlc.arg(max_results);
+
+-- name: ListArticlesByPublishedAt :many
+-- (ピン留め, 公開日時, ID) のキーセットページング。公開日時のない記事は最後で、cursor_time が NULL ならその中で続ける
+SELECT * FROM articles
+WHERE deleted_at IS NULL
+  AND status = sqlc.arg(status)
+  AND (sqlc.narg(tag)::text IS NULL OR EXISTS (
+      SELECT 1 FROM article_tags at
+      INNER JOIN tags t ON t.id = at.tag_id
+      WHERE at.article_id = articles.id AND t.name = sqlc.narg(tag)
+  ))
+  AND (sqlc.narg(category_ids)::bigint[] IS NULL OR category_id = ANY(sqlc.narg(category_ids)::bigint[]))
+  AND (sqlc.narg(published_from)::timestamp IS NULL OR published_at >= sqlc.narg(published_from))
+  AND (sqlc.narg(published_to)::timestamp IS NULL OR published_at <= sqlc.narg(published_to))
+  AND (status <> 'published' OR published_at IS NULL OR published_at <= CURRENT_TIMESTAMP)
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR is_pinned < sqlc.arg(cursor_pinned)::boolean
+    OR (is_pinned = sqlc.arg(cursor_pinned) AND (
+        published_at > sqlc.narg(cursor_time)::timestamp
+        OR (published_at IS NULL AND sqlc.narg(cursor_time) IS NOT NULL)
+        OR (published_at IS NOT DISTINCT FROM sqlc.narg(cursor_time) AND id < sqlc.narg(cursor_id))
+    )))
+ORDER BY is_pinned DESC, published_at NULLS LAST, id DESC
+LIMIT sqlc.arg(max_results);
+
+-- name: ListArticlesByPublishedAtDesc :many
+-- (ピン留め, 公開日時, ID) のキーセットページング。公開日時のない記事は最後で、cursor_time が NULL ならその中で続ける
+SELECT * FROM articles
+WHERE deleted_at IS NULL
+  AND status = sqlc.arg(status)
+  AND (sqlc.narg(tag)::text IS NULL OR EXISTS (
+      SELECT 1 FROM article_tags at
+      INNER JOIN tags t ON t.id = at.tag_id
+      WHERE at.article_id = articles.id AND t.name = sqlc.narg(tag)
+  ))
+  AND (sqlc.narg(category_ids)::bigint[] IS NULL OR category_id = ANY(sqlc.narg(category_ids)::bigint[]))
+  AND (sqlc.narg(published_from)::timestamp IS NULL OR published_at >= sqlc.narg(published_from))
+  AND (sqlc.narg(published_to)::timestamp IS NULL OR published_at <= sqlc.narg(published_to))
+  AND (status <> 'published' OR published_at IS NULL OR published_at <= CURRENT_TIMESTAMP)
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR is_pinned < sqlc.arg(cursor_pinned)::boolean
+    OR (is_pinned = sqlc.arg(cursor_pinned) AND (
+        published_at < sqlc.narg(cursor_time)::timestamp
+        OR (published_at IS NULL AND sqlc.narg(cursor_time) IS NOT NULL)
+        OR (published_at IS NOT DISTINCT FROM sqlc.narg(cursor_time) AND id < sqlc.narg(cursor_id))
+    )))
+ORDER BY is_pinned DESC, published_at DESC NULLS LAST, id DESC
+LIMIT sqlc.arg(max_results);
+
+-- name: ListArticlesByTitle :many
+-- ListArticlesByID と同じく (ピン留め, タイトル, ID) のキーセットページング
+SELECT * FROM articles
+WHERE deleted_at IS NULL
+  AND status = sqlc.arg(status)
+  AND (sqlc.narg(tag)::text IS NULL OR EXISTS (
+      SELECT 1 FROM article_tags at
+      INNER JOIN tags t ON t.id = at.tag_id
+      WHERE at.article_id = articles.id AND t.name = sqlc.narg(tag)
+  ))
+  AND (sqlc.narg(category_ids)::bigint[] IS NULL OR category_id = ANY(sqlc.narg(category_ids)::bigint[]))
+  AND (sqlc.narg(published_from)::timestamp IS NULL OR published_at >= sqlc.narg(published_from))
+  AND (sqlc.narg(published_to)::timestamp IS NULL OR published_at <= sqlc.narg(published_to))
+  AND (status <> 'published' OR published_at IS NULL OR published_at <= CURRENT_TIMESTAMP)
+  AND (sqlc.narg(cursor_id)::bigint IS NULL
+    OR is_pinned < sqlc.arg(cursor_pinned)::boolean
+    OR (is_pinned = sqlc.arg(cursor_pinned) AND (
+        title > sqlc.narg(cursor_title)::text
+        OR (title = sqlc.narg(cursor_title) AND id < sqlc.narg(cursor_id))
+    )))
+ORDER BY is_pinned DESC, title, id DESC
+LIMIT sqlc.arg(max_results);
+
+-- name: CountArticles :one
+SELECT COUNT(*) FROM articles
+WHERE deleted_at IS NULL
+  AND status = sqlc.arg(status)
+  AND (sqlc.narg(user_id)::bigint IS NULL OR user_id = sqlc.narg(user_id))
+  AND (sqlc.narg(tag)::text IS NULL OR EXISTS (
+      SELECT 1 FROM article_tags at
+      INNER JOIN tags t ON t.id = at.tag_id
+      WHERE at.article_id = articles.id AND t.name = sqlc.narg(tag)
+  ))
+  AND (sqlc.narg(category_ids)::bigint[] IS NULL OR category_id = ANY(sqlc.narg(category_ids)::bigint[]))
+  AND (sqlc.narg(published_from)::timestamp IS NULL OR published_at >= sqlc.narg(published_from))
+  AND (sqlc.narg(published_to)::timestamp IS NULL OR published_at <= sqlc.narg(published_to))
+  AND (status <> 'published' OR published_at IS NULL OR published_at <= CURRENT_TIMESTAMP);
+
+-- name: ListArchiveMonths :many
+-- 公開済み記事の件数を公開年月ごとに集計する（新しい月から）
+SELECT
+    EXTRACT(YEAR FROM published_at)::int AS year,
+    EXTRACT(MONTH FROM published_at)::int AS month,
+    COUNT(*) AS count
+FROM articles
+WHERE deleted_at IS NULL
+  AND status = 'published'
+  AND published_at IS NOT NULL
+  AND published_at <= CURRENT_TIMESTAMP
+GROUP BY year, month
+ORDER BY year DESC, month DESC;
+
+-- name: CountArticlesByAuthor :many
+-- 有効なユーザーごとの記事数を多い順に集計する（記事のないユーザーは include_empty のときのみ含める）
+SELECT
+    u.id AS user_id,
+    u.name,
+    COUNT(a.id) AS count
+FROM users u
+LEFT JOIN articles a ON a.user_id = u.id
+  AND a.deleted_at IS NULL
+  AND (sqlc.narg(status)::text IS NULL OR a.status = sqlc.narg(status))
+  AND (a.status <> 'published' OR a.published_at IS NULL OR a.published_at <= CURRENT_TIMESTAMP)
+WHERE u.deleted_at IS NULL
+GROUP BY u.id, u.name
+HAVING sqlc.arg(include_empty)::boolean OR COUNT(a.id) > 0
+ORDER BY count DESC, u.id;
+
+-- name: ListArticlesForExport :many
+-- エクスポート用。ID順のキーセットページングのため、途中で記事が削除されても行が重複・欠落しない
+SELECT * FROM articles
+WHERE deleted_at IS NULL
+  AND status = sqlc.arg(status)
+  AND (sqlc.narg(user_id)::bigint IS NULL OR user_id = sqlc.narg(user_id))
+  AND (sqlc.narg(tag)::text IS NULL OR EXISTS (
+      SELECT 1 FROM article_tags at
+      INNER JOIN tags t ON t.id = at.tag_id
+      WHERE at.article_id = articles.id AND t.name = sqlc.narg(tag)
+  ))
+  AND (status <> 'published' OR published_at IS NULL OR published_at <= CURRENT_TIMESTAMP)
+  AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(max_results);
+
+-- name: SearchArticles :many
+SELECT * FROM articles
+WHERE status = 'published'
+  AND deleted_at IS NULL
+  AND (published_at IS NULL OR published_at <= CURRENT_TIMESTAMP)
+  AND (title ILIKE sqlc.arg(pattern) OR content ILIKE sqlc.arg(pattern))
+ORDER BY id DESC
+LIMIT sqlc.arg(max_results);
+
+-- name: ListRelatedArticles :many
+-- 指定記事とタグを共有する公開済み記事を、共有タグ数の多い順に返す
+SELECT * FROM articles
+WHERE deleted_at IS NULL
+  AND status = 'published'
+  AND (published_at IS NULL OR published_at <= CURRENT_TIMESTAMP)
+  AND id <> sqlc.arg(article_id)
+  AND EXISTS (
+      SELECT 1 FROM article_tags at
+      WHERE at.article_id = articles.id
+        AND at.tag_id IN (SELECT tag_id FROM article_tags WHERE article_id = sqlc.arg(article_id))
+  )
+ORDER BY (
+    SELECT COUNT(*) FROM article_tags at
+    WHERE at.article_id = articles.id
+      AND at.tag_id IN (SELECT tag_id FROM article_tags WHERE article_id = sqlc.arg(article_id))
+) DESC, published_at DESC NULLS LAST, id DESC
+LIMIT sqlc.arg(max_results);
+
+-- name: ListRecentArticlesByAuthor :many
+-- 同じ著者の公開済み記事を新しい順に返す（関連記事のタグがない場合の代替）
+SELECT * FROM articles
+WHERE deleted_at IS NULL
+  AND status = 'published'
+  AND (published_at IS NULL OR published_at <= CURRENT_TIMESTAMP)
+  AND user_id = sqlc.arg(user_id)
+  AND id <> sqlc.arg(exclude_id)
+ORDER BY published_at DESC NULLS LAST, id DESC
+LIMIT sqlc.arg(max_results);
+
+-- name: ListScheduledArticles :many
+SELECT * FROM articles
+WHERE status = 'draft'
+  AND deleted_at IS NULL
+  AND published_at IS NOT NULL
+  AND published_at <= CURRENT_TIMESTAMP
+ORDER BY published_at;
+
+-- name: PublishScheduledArticle :one
+UPDATE articles
+SET status = 'published', version = version + 1, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status = 'draft'
+  AND deleted_at IS NULL
+  AND published_at <= CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: PublishArticle :one
+-- 公開中の記事（公開日時が未設定または経過済み）は対象外。呼び出し側で冪等に扱う
+UPDATE articles
+SET status = 'published', published_at = CURRENT_TIMESTAMP, version = version + 1, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND deleted_at IS NULL
+  AND NOT (status = 'published' AND (published_at IS NULL OR published_at <= CURRENT_TIMESTAMP))
+RETURNING *;
+
+-- name: UnpublishArticle :one
+-- 公開日時のない下書きは対象外。呼び出し側で冪等に扱う
+UPDATE articles
+SET status = 'draft', published_at = NULL, version = version + 1, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND deleted_at IS NULL
+  AND NOT (status = 'draft' AND published_at IS NULL)
+RETURNING *;
+
+-- name: TransferArticle :one
+-- 記事の作成者を変更する。すでに移動先のユーザーが作成者なら行を返さない
+UPDATE articles
+SET user_id = sqlc.arg(new_user_id), version = version + 1, updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)
+  AND deleted_at IS NULL
+  AND user_id <> sqlc.arg(new_user_id)
+RETURNING *;
+
+-- name: TransferArticlesByUser :execrows
+-- ユーザーの記事をすべて別のユーザーに移す（論理削除済みの記事も含む）
+UPDATE articles
+SET user_id = sqlc.arg(new_user_id), version = version + 1, updated_at = CURRENT_TIMESTAMP
+WHERE user_id = sqlc.arg(user_id);
+
+-- name: IncrementArticleViewCount :exec
+UPDATE articles
+SET view_count = view_count + 1
+WHERE id = $1 AND deleted_at IS NULL;
+
 -- name: ListArticlesByUser :many
 SELECT * FROM articles
-WHERE user_id = $1
+WHERE user_id = $1 AND deleted_at IS NULL
+ORDER BY id;
+
+
+-- name: ListAllArticles :many
+-- 論理削除済みの記事も含む（バックアップ用）
+SELECT * FROM articles
 ORDER BY id;
 
+-- name: ListExistingArticleIDs :many
+-- 論理削除済みの記事も含む
+SELECT id FROM articles
+WHERE id = ANY(sqlc.arg(ids)::bigint[]);
+
+-- name: ListExistingArticleSlugs :many
+-- 論理削除済みの記事も含む
+SELECT slug FROM articles
+WHERE slug = ANY(sqlc.arg(slugs)::text[]);
+
+-- name: ImportArticle :one
+-- バックアップからの取り込み用。id が NULL なら採番し、日時や閲覧数はバックアップの値を使う
+INSERT INTO articles (
+    id, user_id, title, content, published_at, created_at, updated_at, status, slug,
+    deleted_at, view_count, featured_image_id, version, category_id, is_pinned, public_id
+) VALUES (
+    COALESCE(sqlc.narg(id)::bigint, nextval(pg_get_serial_sequence('articles', 'id'))),
+    sqlc.arg(user_id), sqlc.arg(title), sqlc.arg(content), sqlc.narg(published_at),
+    sqlc.arg(created_at), sqlc.arg(updated_at), sqlc.arg(status), sqlc.narg(slug),
+    sqlc.narg(deleted_at), sqlc.arg(view_count), sqlc.narg(featured_image_id), sqlc.arg(version), sqlc.narg(category_id), sqlc.arg(is_pinned), sqlc.narg(public_id)
+)
+RETURNING *;
+
+-- name: SyncArticleIDSequence :exec
+-- ID を指定して取り込んだ後、採番が取り込んだ ID と衝突しないよう進める
+SELECT setval(pg_get_serial_sequence('articles', 'id'), GREATEST((SELECT MAX(id) FROM articles), 1));
+
+-- name: LockPinnedArticles :exec
+-- ピン留め件数の確認と更新を直列化する（トランザクション終了まで保持）
+SELECT pg_advisory_xact_lock(hashtext('articles.is_pinned'));
+
+-- name: CountPinnedArticles :one
+SELECT COUNT(*) FROM articles
+WHERE is_pinned AND deleted_at IS NULL;
+
+-- name: SetArticlePinned :one
+UPDATE articles
+SET is_pinned = $1
+WHERE id = $2 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: ListArticlesWithoutPublicID :many
+-- 公開IDの導入前に作成された記事の補完用
+SELECT id FROM articles
+WHERE public_id IS NULL
+ORDER BY id
+LIMIT $1;
+
+-- name: SetArticlePublicID :execrows
+UPDATE articles
+SET public_id = $2
+WHERE id = $1 AND public_id IS NULL;
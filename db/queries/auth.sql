@@ -1,6 +1,7 @@
 -- name: GetUserByEmail :one
+-- 大文字小文字を区別せずに検索する（論理削除済みのユーザーも含む）
 SELECT * FROM users
-WHERE email = $1 LIMIT 1;
+WHERE LOWER(email) = LOWER($1) LIMIT 1;
 
 -- name: CreateAccessToken :one
 INSERT INTO access_tokens (
@@ -15,8 +16,47 @@ SELECT u.* FROM users u
 INNER JOIN access_tokens t ON u.id = t.user_id
 WHERE t.token = $1
   AND (t.expires_at IS NULL OR t.expires_at > CURRENT_TIMESTAMP)
+  AND u.deleted_at IS NULL
 LIMIT 1;
 
+-- name: GetAccessToken :one
+SELECT * FROM access_tokens
+WHERE token = $1
+  AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+LIMIT 1;
+
+-- name: ListAccessTokensByUser :many
+SELECT * FROM access_tokens
+WHERE user_id = $1
+  AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+ORDER BY id DESC;
+
+-- name: DeleteAccessTokenByID :execrows
+DELETE FROM access_tokens
+WHERE id = $1 AND user_id = $2;
+
+-- name: TouchAccessToken :exec
+-- 書き込みを抑えるため、1分以内に記録済みなら更新しない
+UPDATE access_tokens
+SET last_used_at = CURRENT_TIMESTAMP
+WHERE token = $1
+  AND (last_used_at IS NULL OR last_used_at < CURRENT_TIMESTAMP - INTERVAL '1 minute');
+
+-- name: DeleteAccessTokensByUser :exec
+DELETE FROM access_tokens
+WHERE user_id = $1;
+
 -- name: DeleteAccessToken :exec
 DELETE FROM access_tokens
 WHERE token = $1;
+
+-- name: RefreshToken :one
+WITH old AS (
+    DELETE FROM access_tokens
+    WHERE token = sqlc.arg(old_token)
+      AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+    RETURNING user_id
+)
+INSERT INTO access_tokens (user_id, token, expires_at)
+SELECT user_id, sqlc.arg(new_token)::varchar, sqlc.arg(expires_at)::timestamp FROM old
+RETURNING *;
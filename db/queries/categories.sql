@@ -0,0 +1,25 @@
+-- name: CreateCategory :one
+INSERT INTO categories (
+    name, slug, parent_id
+) VALUES (
+    $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetCategory :one
+SELECT * FROM categories
+WHERE id = $1 LIMIT 1;
+
+-- name: ListCategories :many
+SELECT * FROM categories
+ORDER BY id;
+
+-- name: UpdateCategory :one
+UPDATE categories
+SET name = $1, slug = $2, parent_id = $3, updated_at = CURRENT_TIMESTAMP
+WHERE id = $4
+RETURNING *;
+
+-- name: DeleteCategory :execrows
+DELETE FROM categories
+WHERE id = $1;
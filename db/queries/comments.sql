@@ -0,0 +1,13 @@
+-- name: CreateComment :one
+INSERT INTO comments (
+    article_id, user_id, temp_user_name, content
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: ListCommentsByArticle :many
+SELECT * FROM comments
+WHERE article_id = $1 AND id < $2
+ORDER BY id DESC
+LIMIT $3;
@@ -0,0 +1,25 @@
+-- name: ClaimIdempotencyKey :execrows
+-- 未使用のキーに加え、期限切れ（24時間）のキーと放棄された処理中（1分）のキーを確保し直せる
+INSERT INTO idempotency_keys (key)
+VALUES ($1)
+ON CONFLICT (key) DO UPDATE
+SET article_id = NULL, created_at = CURRENT_TIMESTAMP
+WHERE idempotency_keys.created_at < CURRENT_TIMESTAMP - INTERVAL '24 hours'
+   OR (idempotency_keys.article_id IS NULL AND idempotency_keys.created_at < CURRENT_TIMESTAMP - INTERVAL '1 minute');
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys
+WHERE key = $1 LIMIT 1;
+
+-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys
+SET article_id = $2
+WHERE key = $1;
+
+-- name: ReleaseIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE key = $1 AND article_id IS NULL;
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys
+WHERE created_at < CURRENT_TIMESTAMP - INTERVAL '24 hours';
@@ -0,0 +1,23 @@
+-- name: CreateLoginLink :exec
+-- email を指定するとメールアドレス変更の確認リンクになる
+INSERT INTO login_links (
+    user_id, token, email, expires_at
+) VALUES (
+    $1, $2, $3, $4
+);
+
+-- name: ConsumeLoginLink :one
+-- 削除できた1件の呼び出しだけがユーザーIDを受け取るため、同時に使われても一度しか成功しない
+DELETE FROM login_links
+WHERE token = $1 AND email IS NULL AND expires_at > CURRENT_TIMESTAMP
+RETURNING user_id;
+
+-- name: ConsumeEmailChangeLink :one
+-- ConsumeLoginLink と同じく、削除できた1件の呼び出しだけが新しいメールアドレスを受け取る
+DELETE FROM login_links
+WHERE token = $1 AND email IS NOT NULL AND expires_at > CURRENT_TIMESTAMP
+RETURNING user_id, email;
+
+-- name: DeleteExpiredLoginLinks :execrows
+DELETE FROM login_links
+WHERE expires_at <= CURRENT_TIMESTAMP;
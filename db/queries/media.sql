@@ -0,0 +1,25 @@
+-- name: CreateMediaFile :one
+INSERT INTO media_files (
+    user_id, filename, object_key, content_type, size
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetMediaFile :one
+SELECT * FROM media_files
+WHERE id = $1 LIMIT 1;
+
+-- name: ListMediaFiles :many
+SELECT * FROM media_files
+WHERE id < $1
+ORDER BY id DESC
+LIMIT $2;
+
+-- name: DeleteMediaFile :execrows
+DELETE FROM media_files
+WHERE id = $1;
+
+-- name: ListMediaFilesByIDs :many
+SELECT * FROM media_files
+WHERE id = ANY(sqlc.arg(ids)::bigint[]);
@@ -0,0 +1,9 @@
+-- name: GetSetting :one
+SELECT value FROM settings
+WHERE key = $1 LIMIT 1;
+
+-- name: SetSetting :exec
+INSERT INTO settings (key, value)
+VALUES ($1, $2)
+ON CONFLICT (key) DO UPDATE
+SET value = EXCLUDED.value, updated_at = CURRENT_TIMESTAMP;
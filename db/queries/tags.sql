@@ -0,0 +1,25 @@
+-- name: UpsertTag :one
+INSERT INTO tags (name) VALUES ($1)
+ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+RETURNING *;
+
+-- name: AttachTag :exec
+INSERT INTO article_tags (article_id, tag_id) VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: DetachTagsExcept :exec
+DELETE FROM article_tags
+WHERE article_id = sqlc.arg(article_id)
+  AND NOT (tag_id = ANY(sqlc.arg(tag_ids)::bigint[]));
+
+-- name: ListTagsByArticle :many
+SELECT t.* FROM tags t
+INNER JOIN article_tags at ON at.tag_id = t.id
+WHERE at.article_id = $1
+ORDER BY t.name;
+
+-- name: ListTagNamesByArticles :many
+SELECT at.article_id, t.name FROM article_tags at
+INNER JOIN tags t ON t.id = at.tag_id
+WHERE at.article_id = ANY(sqlc.arg(article_ids)::bigint[])
+ORDER BY t.name;
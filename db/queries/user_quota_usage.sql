@@ -0,0 +1,12 @@
+-- name: ConsumeUserQuota :one
+-- 上限未満のときだけ当日の件数を1増やして返す（上限に達していれば行を返さない）
+INSERT INTO user_quota_usage (user_id, day, count)
+VALUES (sqlc.arg(user_id), sqlc.arg(day), 1)
+ON CONFLICT (user_id, day) DO UPDATE
+SET count = user_quota_usage.count + 1
+WHERE user_quota_usage.count < sqlc.arg(max_count)
+RETURNING count;
+
+-- name: DeleteUserQuotaUsageBefore :execrows
+DELETE FROM user_quota_usage
+WHERE day < $1;
@@ -1,25 +1,111 @@
 -- name: GetUser :one
 SELECT * FROM users
-WHERE id = $1 LIMIT 1;
+WHERE id = $1 AND deleted_at IS NULL LIMIT 1;
 
 -- name: ListUsers :many
 SELECT * FROM users
+WHERE deleted_at IS NULL
 ORDER BY id;
 
+-- name: ListUsersByIDs :many
+-- 論理削除済みのユーザーも含む（記事の作成者表示用）
+SELECT * FROM users
+WHERE id = ANY(sqlc.arg(ids)::bigint[])
+ORDER BY id;
+
+-- name: ListUsersPaginated :many
+SELECT * FROM users
+WHERE deleted_at IS NULL
+ORDER BY id
+LIMIT $1 OFFSET $2;
+
+-- name: CountUsers :one
+SELECT COUNT(*) FROM users
+WHERE deleted_at IS NULL;
+
 -- name: CreateUser :one
 INSERT INTO users (
-    email, name
+    email, name, role, created_at, updated_at
 ) VALUES (
-    $1, $2
+    $1, $2, $3, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
 )
 RETURNING *;
 
 -- name: UpdateUser :one
 UPDATE users
 SET email = $1, name = $2, updated_at = CURRENT_TIMESTAMP
-WHERE id = $3
+WHERE id = $3 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: SetUserEmail :one
+-- 確認済みの新しいメールアドレスに変更する
+UPDATE users
+SET email = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: SoftDeleteUser :execrows
+UPDATE users
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: RestoreUser :one
+UPDATE users
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: UpsertUserByEmail :one
+INSERT INTO users (
+    email, name, role, created_at, updated_at
+) VALUES (
+    $1, $2, $3, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+)
+ON CONFLICT ((LOWER(email))) DO UPDATE
+SET name = EXCLUDED.name, updated_at = CURRENT_TIMESTAMP
+WHERE users.deleted_at IS NULL
+RETURNING *, (xmax = 0) AS inserted;
+
+-- name: ListAllUsers :many
+-- 論理削除済みのユーザーも含む（バックアップ用）
+SELECT * FROM users
+ORDER BY id;
+
+-- name: ListExistingUserEmails :many
+-- 論理削除済みのユーザーも含む。emails は小文字で渡す
+SELECT email FROM users
+WHERE LOWER(email) = ANY(sqlc.arg(emails)::text[]);
+
+-- name: ImportUser :one
+-- バックアップからの取り込み用。id が NULL なら採番し、日時はバックアップの値を使う
+INSERT INTO users (
+    id, email, name, role, created_at, updated_at, deleted_at
+) VALUES (
+    COALESCE(sqlc.narg(id)::bigint, nextval(pg_get_serial_sequence('users', 'id'))),
+    sqlc.arg(email), sqlc.arg(name), sqlc.arg(role),
+    sqlc.arg(created_at), sqlc.arg(updated_at), sqlc.narg(deleted_at)
+)
 RETURNING *;
 
--- name: DeleteUser :exec
-DELETE FROM users
-WHERE id = $1;
+-- name: SyncUserIDSequence :exec
+-- ID を指定して取り込んだ後、採番が取り込んだ ID と衝突しないよう進める
+SELECT setval(pg_get_serial_sequence('users', 'id'), GREATEST((SELECT MAX(id) FROM users), 1));
+
+-- name: SetUserPassword :execrows
+-- password_hash が NULL ならパスワードログインを無効にする
+UPDATE users
+SET password_hash = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: SearchUsers :many
+-- pattern は ILIKE のパターン。呼び出し側でワイルドカードをエスケープする
+SELECT * FROM users
+WHERE deleted_at IS NULL
+  AND (name ILIKE sqlc.arg(pattern) OR email ILIKE sqlc.arg(pattern))
+ORDER BY id
+LIMIT sqlc.arg(max_results) OFFSET sqlc.arg(row_offset);
+
+-- name: CountSearchUsers :one
+SELECT COUNT(*) FROM users
+WHERE deleted_at IS NULL
+  AND (name ILIKE sqlc.arg(pattern) OR email ILIKE sqlc.arg(pattern));
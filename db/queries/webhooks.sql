@@ -0,0 +1,31 @@
+-- name: CreateWebhook :one
+INSERT INTO webhooks (
+    url, secret, events
+) VALUES (
+    $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetWebhook :one
+SELECT * FROM webhooks
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhooks :many
+SELECT * FROM webhooks
+ORDER BY id;
+
+-- name: ListWebhooksByEvent :many
+SELECT * FROM webhooks
+WHERE sqlc.arg(event)::text = ANY(events)
+ORDER BY id;
+
+-- name: UpdateWebhook :one
+-- secret が NULL なら現在のシークレットを維持する
+UPDATE webhooks
+SET url = sqlc.arg(url), secret = COALESCE(sqlc.narg(secret), secret), events = sqlc.arg(events), updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteWebhook :execrows
+DELETE FROM webhooks
+WHERE id = $1;
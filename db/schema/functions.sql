@@ -13,8 +13,16 @@ CREATE TRIGGER update_users_updated_at BEFORE UPDATE ON users
 FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
 
 -- articles テーブルの updated_at 自動更新トリガー
+-- 閲覧数のカウントアップは記事の更新として扱わない
 DROP TRIGGER IF EXISTS update_articles_updated_at ON articles;
 CREATE TRIGGER update_articles_updated_at BEFORE UPDATE ON articles
+FOR EACH ROW
+WHEN (OLD.view_count IS NOT DISTINCT FROM NEW.view_count)
+EXECUTE FUNCTION update_updated_at_column();
+
+-- article_translations テーブルの updated_at 自動更新トリガー
+DROP TRIGGER IF EXISTS update_article_translations_updated_at ON article_translations;
+CREATE TRIGGER update_article_translations_updated_at BEFORE UPDATE ON article_translations
 FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
 
 -- comments テーブルの updated_at 自動更新トリガー
@@ -2,11 +2,12 @@
 -- このファイルは開発・テスト環境用です
 
 -- テストユーザーの作成
-INSERT INTO users (name, email) VALUES
-  ('ユーザー1', 'test@example.com')
+INSERT INTO users (name, email, role) VALUES
+  ('ユーザー1', 'test@example.com', 'admin')
 ON CONFLICT (email) DO NOTHING;
 
--- テスト用アクセストークンの作成
+-- テスト用アクセストークンの作成（平文は 'test-token-1'）
 -- ユーザーIDは users テーブルから取得
+-- トークンはSHA-256ハッシュで保存する
 INSERT INTO access_tokens (user_id, token, expires_at) VALUES
-    (1, 'test-token-1', NULL);
\ No newline at end of file
+    (1, encode(sha256('test-token-1'::bytea), 'hex'), NULL);
\ No newline at end of file
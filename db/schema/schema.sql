@@ -4,9 +4,40 @@ CREATE TABLE IF NOT EXISTS users (
     name TEXT NOT NULL,            -- ユーザー名
     email VARCHAR(255) NOT NULL UNIQUE,     -- メールアドレス
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,  -- 作成日時
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,  -- 更新日時
+    role VARCHAR(20) NOT NULL DEFAULT 'viewer'
+        CHECK (role IN ('admin', 'editor', 'viewer')),  -- 権限ロール
+    deleted_at TIMESTAMP,                  -- 削除日時（NULL = 未削除）。削除済みユーザーの記事は残し、作成者は非アクティブ扱い
+    password_hash VARCHAR(255)             -- パスワードのbcryptハッシュ（NULL = パスワード未設定）。APIレスポンスには含めない
+);
+
+-- メールアドレスの一意性は大文字小文字を区別しない（検索もこのインデックスを使う）
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_lower ON users(LOWER(email));
+
+-- メディア（アップロード画像）テーブル
+CREATE TABLE IF NOT EXISTS media_files (
+    id BIGSERIAL PRIMARY KEY,              -- メディアID
+    user_id BIGINT REFERENCES users(id) ON DELETE SET NULL,  -- アップロードしたユーザーID
+    filename VARCHAR(255) NOT NULL,        -- アップロード時のファイル名
+    object_key VARCHAR(255) NOT NULL UNIQUE,  -- ストレージ上のオブジェクトキー
+    content_type VARCHAR(100) NOT NULL,    -- MIMEタイプ
+    size BIGINT NOT NULL,                  -- ファイルサイズ（バイト）
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP  -- 作成日時
+);
+
+-- カテゴリテーブル（親子関係を持つ）
+CREATE TABLE IF NOT EXISTS categories (
+    id BIGSERIAL PRIMARY KEY,              -- カテゴリID
+    name VARCHAR(100) NOT NULL,            -- カテゴリ名
+    slug VARCHAR(255) NOT NULL UNIQUE,     -- URL用スラッグ
+    parent_id BIGINT REFERENCES categories(id),  -- 親カテゴリID（NULL = ルート）。循環はアプリケーション側で防止。子を持つカテゴリは削除不可
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,  -- 作成日時
     updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP   -- 更新日時
 );
 
+-- 子カテゴリ検索用インデックス
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories(parent_id);
+
 -- 記事情報テーブル
 CREATE TABLE IF NOT EXISTS articles (
     id BIGSERIAL PRIMARY KEY,              -- 記事ID
@@ -15,13 +46,75 @@ CREATE TABLE IF NOT EXISTS articles (
     content TEXT NOT NULL,                 -- 記事本文
     published_at TIMESTAMP,                -- 公開日時（NULL = 下書き）
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,  -- 作成日時
-    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP   -- 更新日時
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,  -- 更新日時
+    status VARCHAR(20) NOT NULL DEFAULT 'draft'
+        CHECK (status IN ('draft', 'published', 'archived')),  -- 公開状態
+    slug VARCHAR(255) UNIQUE,              -- URL用スラッグ（作成時にタイトルから生成）
+    deleted_at TIMESTAMP,                  -- 削除日時（NULL = 未削除）
+    view_count BIGINT NOT NULL DEFAULT 0,  -- 閲覧数
+    featured_image_id BIGINT REFERENCES media_files(id),  -- アイキャッチ画像ID（参照中のメディアは削除不可）
+    version INTEGER NOT NULL DEFAULT 1,    -- 楽観的排他制御用バージョン（更新ごとに加算）
+    category_id BIGINT REFERENCES categories(id),  -- カテゴリID（参照中のカテゴリは削除不可）
+    is_pinned BOOLEAN NOT NULL DEFAULT FALSE,  -- ピン留め（一覧の先頭に表示）
+    public_id VARCHAR(26) UNIQUE           -- 公開ID（ULID、作成時にアプリケーションで生成）
 );
 
 -- 作成者による記事検索用インデックス
 CREATE INDEX IF NOT EXISTS idx_articles_user_id ON articles(user_id);
 -- 公開日時による記事検索用インデックス
 CREATE INDEX IF NOT EXISTS idx_articles_published_at ON articles(published_at);
+-- 公開状態による記事検索用インデックス
+CREATE INDEX IF NOT EXISTS idx_articles_status ON articles(status);
+-- 作成日時による記事並び替え用インデックス
+CREATE INDEX IF NOT EXISTS idx_articles_created_at ON articles(created_at);
+-- アイキャッチ画像の参照確認用インデックス
+CREATE INDEX IF NOT EXISTS idx_articles_featured_image_id ON articles(featured_image_id);
+-- カテゴリによる記事検索用インデックス
+CREATE INDEX IF NOT EXISTS idx_articles_category_id ON articles(category_id);
+-- ピン留め件数の確認用インデックス
+CREATE INDEX IF NOT EXISTS idx_articles_is_pinned ON articles(is_pinned) WHERE is_pinned;
+
+-- 記事の編集履歴テーブル（更新のたびに更新前のタイトルと本文を保存）
+CREATE TABLE IF NOT EXISTS article_revisions (
+    id BIGSERIAL PRIMARY KEY,              -- リビジョンID
+    article_id BIGINT NOT NULL REFERENCES articles(id) ON DELETE CASCADE,  -- 記事ID
+    version INTEGER NOT NULL,              -- 保存時点の記事バージョン
+    title VARCHAR(500) NOT NULL,           -- 更新前の記事タイトル
+    content TEXT NOT NULL,                 -- 更新前の記事本文
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP  -- 作成日時（= 更新日時）
+);
+
+-- 記事による履歴検索用インデックス
+CREATE INDEX IF NOT EXISTS idx_article_revisions_article_id ON article_revisions(article_id);
+
+-- 記事の翻訳テーブル（既定言語の本文は articles に持つ）
+CREATE TABLE IF NOT EXISTS article_translations (
+    article_id BIGINT NOT NULL REFERENCES articles(id) ON DELETE CASCADE,  -- 記事ID
+    locale VARCHAR(35) NOT NULL,           -- 言語タグ（BCP 47、例: en, en-US）
+    title VARCHAR(500) NOT NULL,           -- 翻訳したタイトル
+    content TEXT NOT NULL,                 -- 翻訳した本文
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,  -- 作成日時
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,  -- 更新日時
+    PRIMARY KEY (article_id, locale)
+);
+
+-- タグ情報テーブル
+CREATE TABLE IF NOT EXISTS tags (
+    id BIGSERIAL PRIMARY KEY,              -- タグID
+    name VARCHAR(100) NOT NULL UNIQUE,     -- タグ名
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP  -- 作成日時
+);
+
+-- 記事とタグの関連テーブル
+CREATE TABLE IF NOT EXISTS article_tags (
+    article_id BIGINT NOT NULL REFERENCES articles(id) ON DELETE CASCADE,  -- 記事ID
+    tag_id BIGINT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,          -- タグID
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,  -- 作成日時
+    PRIMARY KEY (article_id, tag_id)
+);
+
+-- タグによる記事検索用インデックス
+CREATE INDEX IF NOT EXISTS idx_article_tags_tag_id ON article_tags(tag_id);
 
 -- コメント情報テーブル
 CREATE TABLE IF NOT EXISTS comments (
@@ -46,12 +139,66 @@ CREATE INDEX IF NOT EXISTS idx_comments_user_id ON comments(user_id);
 CREATE TABLE IF NOT EXISTS access_tokens (
     id BIGSERIAL PRIMARY KEY,              -- トークンID
     user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,  -- ユーザーID
-    token VARCHAR(255) NOT NULL UNIQUE,    -- アクセストークン
+    token VARCHAR(255) NOT NULL UNIQUE,    -- アクセストークンのSHA-256ハッシュ（16進）。平文は保存しない
     expires_at TIMESTAMP,                  -- 有効期限（NULL = 無期限）
-    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP  -- 作成日時
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,  -- 作成日時
+    last_used_at TIMESTAMP                 -- 最終使用日時（NULL = 未使用）
 );
 
 -- トークン検索用インデックス
 CREATE INDEX IF NOT EXISTS idx_access_tokens_token ON access_tokens(token);
 -- ユーザーIDによる検索用インデックス
-CREATE INDEX IF NOT EXISTS idx_access_tokens_user_id ON access_tokens(user_id);
\ No newline at end of file
+CREATE INDEX IF NOT EXISTS idx_access_tokens_user_id ON access_tokens(user_id);
+
+
+-- ログインリンク（マジックリンク）テーブル。使用時に行を削除して一度きりにする
+-- メールアドレス変更の確認リンクも同じ仕組みで保存する
+CREATE TABLE IF NOT EXISTS login_links (
+    id BIGSERIAL PRIMARY KEY,              -- リンクID
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,  -- ユーザーID
+    token VARCHAR(64) NOT NULL UNIQUE,     -- リンクに含めるトークンのSHA-256ハッシュ（16進）。平文は保存しない
+    email VARCHAR(255),                    -- 確認後に設定する新しいメールアドレス（NULL = ログインリンク）
+    expires_at TIMESTAMP NOT NULL,         -- 有効期限
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP  -- 作成日時
+);
+
+-- 期限切れリンク削除用インデックス
+CREATE INDEX IF NOT EXISTS idx_login_links_expires_at ON login_links(expires_at);
+
+
+-- Webhook 登録テーブル（記事の作成・更新・削除を通知する）
+CREATE TABLE IF NOT EXISTS webhooks (
+    id BIGSERIAL PRIMARY KEY,              -- Webhook ID
+    url VARCHAR(2048) NOT NULL,            -- 通知先URL
+    secret VARCHAR(255) NOT NULL,          -- 署名（X-Signature）用の共有シークレット
+    events TEXT[] NOT NULL,                -- 通知するイベント（例: article.created）
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,  -- 作成日時
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP   -- 更新日時
+);
+
+
+-- 冪等キーテーブル（記事作成リトライによる重複防止）
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key VARCHAR(255) PRIMARY KEY,          -- Idempotency-Keyヘッダーの値
+    article_id BIGINT REFERENCES articles(id) ON DELETE CASCADE,  -- 作成された記事ID（NULL = 処理中）
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP  -- 作成日時（24時間で失効）
+);
+
+-- 期限切れキー削除用インデックス
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys(created_at);
+
+-- 実行時設定テーブル（メンテナンスモードなど、再起動せずに全インスタンスで切り替える値）
+CREATE TABLE IF NOT EXISTS settings (
+    key VARCHAR(255) PRIMARY KEY,          -- 設定キー（例: maintenance_mode）
+    value TEXT NOT NULL,                   -- 設定値
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP  -- 更新日時
+);
+
+
+-- ユーザーごとの1日あたりの書き込みリクエスト数（APIクォータ用。日付はUTC）
+CREATE TABLE IF NOT EXISTS user_quota_usage (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,  -- ユーザーID
+    day DATE NOT NULL,                     -- 集計日（UTC）
+    count INTEGER NOT NULL,                -- その日に受け付けたリクエスト数
+    PRIMARY KEY (user_id, day)
+);
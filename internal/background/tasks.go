@@ -0,0 +1,63 @@
+// Package background runs work that outlives the request that started it,
+// such as view counts, token last_used_at updates and webhook deliveries,
+// so that a graceful shutdown can wait for it instead of dropping it.
+package background
+
+import (
+	"context"
+	"sync"
+)
+
+// Tasks tracks background work until it finishes.
+// It is safe for concurrent use.
+//
+// The server runs as a long-lived process, so there is no runtime hook such as
+// Workers' waitUntil to hand work to. Tasks is the fallback: each task runs in its own
+// goroutine and Wait, called on shutdown after the HTTP server has stopped accepting
+// requests, blocks until they are done or its context expires. Work still running at
+// that point is lost when the process exits, as are tasks of a process that is killed.
+type Tasks struct {
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+	closing bool
+}
+
+// New creates an empty Tasks
+func New() *Tasks {
+	return &Tasks{}
+}
+
+// Go runs fn in a new goroutine and tracks it until it returns.
+// Once Wait has been called, fn runs in the calling goroutine instead,
+// so work started during shutdown is not lost.
+func (t *Tasks) Go(fn func()) {
+	t.mu.Lock()
+	if t.closing {
+		t.mu.Unlock()
+		fn()
+		return
+	}
+	t.wg.Go(fn)
+	t.mu.Unlock()
+}
+
+// Wait blocks until every task started by Go has finished or ctx is done,
+// in which case it returns ctx.Err().
+func (t *Tasks) Wait(ctx context.Context) error {
+	t.mu.Lock()
+	t.closing = true
+	t.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
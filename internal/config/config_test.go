@@ -0,0 +1,89 @@
+package config
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/para7/nanaket-cms/internal/usecase"
+)
+
+// env returns a getenv reading from vars
+func env(vars map[string]string) func(string) string {
+	return func(key string) string { return vars[key] }
+}
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := load(env(nil))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Media.BaseURL != DefaultSiteBaseURL+"/media" {
+		t.Errorf("media base URL = %q", cfg.Media.BaseURL)
+	}
+	if !cfg.Cookie.Secure || !cfg.HTTP.CORSAllowCredentials || cfg.HTTP.TrustProxy {
+		t.Errorf("booleans = %+v, %+v", cfg.Cookie, cfg.HTTP)
+	}
+	if cfg.HTTP.TrailingSlash != TrailingSlashOff || cfg.Articles.DefaultLocale != usecase.DefaultLocale {
+		t.Errorf("trailing slash %q, locale %q", cfg.HTTP.TrailingSlash, cfg.Articles.DefaultLocale)
+	}
+	if cfg.RateLimits.Login != (RateLimit{Limit: 10, Window: time.Minute}) {
+		t.Errorf("login rate limit = %+v", cfg.RateLimits.Login)
+	}
+	if cfg.Pages != (Pages{Default: DefaultPageSize, Max: DefaultMaxPageSize}) {
+		t.Errorf("pages = %+v", cfg.Pages)
+	}
+}
+
+func TestLoadValues(t *testing.T) {
+	cfg, err := load(env(map[string]string{
+		"DB_READ_RETRIES":      "0",
+		"CORS_ALLOWED_ORIGINS": "https://a.example, ,https://b.example",
+		"TRAILING_SLASH":       "rewrite",
+		"TRUST_PROXY":          "true",
+		"MEDIA_MAX_BYTES":      "2048",
+		"LOGIN_RATE_LIMIT":     "3",
+		"DEFAULT_LOCALE":       "en",
+	}))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.ReadRetry.Retries != 0 {
+		t.Errorf("read retries = %d, want 0", cfg.ReadRetry.Retries)
+	}
+	if want := []string{"https://a.example", "https://b.example"}; !slices.Equal(cfg.HTTP.CORSAllowedOrigins, want) {
+		t.Errorf("CORS origins = %v, want %v", cfg.HTTP.CORSAllowedOrigins, want)
+	}
+	if cfg.HTTP.TrailingSlash != TrailingSlashRewrite || !cfg.HTTP.TrustProxy {
+		t.Errorf("HTTP = %+v", cfg.HTTP)
+	}
+	if cfg.Media.MaxBytes != 2048 || cfg.RateLimits.Login.Limit != 3 || cfg.Articles.DefaultLocale != "en" {
+		t.Errorf("media max %d, login limit %d, locale %q", cfg.Media.MaxBytes, cfg.RateLimits.Login.Limit, cfg.Articles.DefaultLocale)
+	}
+}
+
+func TestLoadMalformed(t *testing.T) {
+	tests := []struct {
+		key, value string
+	}{
+		{"REQUEST_TIMEOUT", "soon"},
+		{"WEBHOOK_TIMEOUT", "-1s"},
+		{"USER_WRITE_QUOTA", "0"},
+		{"DB_READ_RETRIES", "-1"},
+		{"MAX_BODY_BYTES", "1MB"},
+		{"TRUST_PROXY", "yes"},
+		{"COOKIE_SECURE", "0"},
+		{"TRAILING_SLASH", "strip"},
+		{"DEFAULT_LOCALE", "not a locale"},
+		{"SITE_BASE_URL", "example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			_, err := load(env(map[string]string{tt.key: tt.value}))
+			if err == nil || !strings.Contains(err.Error(), tt.key) {
+				t.Errorf("got %v, want an error naming %s", err, tt.key)
+			}
+		})
+	}
+}
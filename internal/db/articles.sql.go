@@ -11,20 +11,142 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const articleSlugExists = `-- name: ArticleSlugExists :one
+SELECT EXISTS (
+    SELECT 1 FROM articles
+    WHERE slug = $1
+)
+`
+
+func (q *Queries) ArticleSlugExists(ctx context.Context, slug *string) (bool, error) {
+	row := q.db.QueryRow(ctx, articleSlugExists, slug)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const countArticles = `-- name: CountArticles :one
+SELECT COUNT(*) FROM articles
+WHERE deleted_at IS NULL
+  AND status = $1
+  AND ($2::bigint IS NULL OR user_id = $2)
+  AND ($3::text IS NULL OR EXISTS (
+      SELECT 1 FROM article_tags at
+      INNER JOIN tags t ON t.id = at.tag_id
+      WHERE at.article_id = articles.id AND t.name = $3
+  ))
+  AND ($4::bigint[] IS NULL OR category_id = ANY($4::bigint[]))
+  AND ($5::timestamp IS NULL OR published_at >= $5)
+  AND ($6::timestamp IS NULL OR published_at <= $6)
+  AND (status <> 'published' OR published_at IS NULL OR published_at <= CURRENT_TIMESTAMP)
+`
+
+type CountArticlesParams struct {
+	Status        string           `json:"status"`
+	UserID        *int64           `json:"user_id"`
+	Tag           *string          `json:"tag"`
+	CategoryIds   []int64          `json:"category_ids"`
+	PublishedFrom pgtype.Timestamp `json:"published_from"`
+	PublishedTo   pgtype.Timestamp `json:"published_to"`
+}
+
+func (q *Queries) CountArticles(ctx context.Context, arg CountArticlesParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countArticles,
+		arg.Status,
+		arg.UserID,
+		arg.Tag,
+		arg.CategoryIds,
+		arg.PublishedFrom,
+		arg.PublishedTo,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countArticlesByAuthor = `-- name: CountArticlesByAuthor :many
+SELECT
+    u.id AS user_id,
+    u.name,
+    COUNT(a.id) AS count
+FROM users u
+LEFT JOIN articles a ON a.user_id = u.id
+  AND a.deleted_at IS NULL
+  AND ($1::text IS NULL OR a.status = $1)
+  AND (a.status <> 'published' OR a.published_at IS NULL OR a.published_at <= CURRENT_TIMESTAMP)
+WHERE u.deleted_at IS NULL
+GROUP BY u.id, u.name
+HAVING $2::boolean OR COUNT(a.id) > 0
+ORDER BY count DESC, u.id
+`
+
+type CountArticlesByAuthorParams struct {
+	Status       *string `json:"status"`
+	IncludeEmpty bool    `json:"include_empty"`
+}
+
+type CountArticlesByAuthorRow struct {
+	UserID int64  `json:"user_id"`
+	Name   string `json:"name"`
+	Count  int64  `json:"count"`
+}
+
+// 有効なユーザーごとの記事数を多い順に集計する（記事のないユーザーは include_empty のときのみ含める）
+func (q *Queries) CountArticlesByAuthor(ctx context.Context, arg CountArticlesByAuthorParams) ([]CountArticlesByAuthorRow, error) {
+	rows, err := q.db.Query(ctx, countArticlesByAuthor, arg.Status, arg.IncludeEmpty)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CountArticlesByAuthorRow{}
+	for rows.Next() {
+		var i CountArticlesByAuthorRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Name,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countPinnedArticles = `-- name: CountPinnedArticles :one
+SELECT COUNT(*) FROM articles
+WHERE is_pinned AND deleted_at IS NULL
+`
+
+func (q *Queries) CountPinnedArticles(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countPinnedArticles)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createArticle = `-- name: CreateArticle :one
 INSERT INTO articles (
-    user_id, title, content, published_at
+    user_id, title, content, published_at, status, slug, featured_image_id, category_id, public_id
 ) VALUES (
-    $1, $2, $3, $4
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
 )
-RETURNING id, user_id, title, content, published_at, created_at, updated_at
+RETURNING id, user_id, title, content, published_at, created_at, updated_at, status, slug, deleted_at, view_count, featured_image_id, version, category_id, is_pinned, public_id
 `
 
 type CreateArticleParams struct {
-	UserID      int64            `json:"user_id"`
-	Title       string           `json:"title"`
-	Content     string           `json:"content"`
-	PublishedAt pgtype.Timestamp `json:"published_at"`
+	UserID          int64            `json:"user_id"`
+	Title           string           `json:"title"`
+	Content         string           `json:"content"`
+	PublishedAt     pgtype.Timestamp `json:"published_at"`
+	Status          string           `json:"status"`
+	Slug            *string          `json:"slug"`
+	FeaturedImageID *int64           `json:"featured_image_id"`
+	CategoryID      *int64           `json:"category_id"`
+	PublicID        *string          `json:"public_id"`
 }
 
 func (q *Queries) CreateArticle(ctx context.Context, arg CreateArticleParams) (Article, error) {
@@ -33,7 +155,99 @@ func (q *Queries) CreateArticle(ctx context.Context, arg CreateArticleParams) (A
 		arg.Title,
 		arg.Content,
 		arg.PublishedAt,
+		arg.Status,
+		arg.Slug,
+		arg.FeaturedImageID,
+		arg.CategoryID,
+		arg.PublicID,
+	)
+	var i Article
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Content,
+		&i.PublishedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.Slug,
+		&i.DeletedAt,
+		&i.ViewCount,
+		&i.FeaturedImageID,
+		&i.Version,
+		&i.CategoryID,
+		&i.IsPinned,
+		&i.PublicID,
+	)
+	return i, err
+}
+
+const getArticle = `-- name: GetArticle :one
+SELECT id, user_id, title, content, published_at, created_at, updated_at, status, slug, deleted_at, view_count, featured_image_id, version, category_id, is_pinned, public_id FROM articles
+WHERE id = $1 AND deleted_at IS NULL LIMIT 1
+`
+
+func (q *Queries) GetArticle(ctx context.Context, id int64) (Article, error) {
+	row := q.db.QueryRow(ctx, getArticle, id)
+	var i Article
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Content,
+		&i.PublishedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.Slug,
+		&i.DeletedAt,
+		&i.ViewCount,
+		&i.FeaturedImageID,
+		&i.Version,
+		&i.CategoryID,
+		&i.IsPinned,
+		&i.PublicID,
+	)
+	return i, err
+}
+
+const getArticleByPublicID = `-- name: GetArticleByPublicID :one
+SELECT id, user_id, title, content, published_at, created_at, updated_at, status, slug, deleted_at, view_count, featured_image_id, version, category_id, is_pinned, public_id FROM articles
+WHERE public_id = $1 AND deleted_at IS NULL LIMIT 1
+`
+
+func (q *Queries) GetArticleByPublicID(ctx context.Context, publicID *string) (Article, error) {
+	row := q.db.QueryRow(ctx, getArticleByPublicID, publicID)
+	var i Article
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Content,
+		&i.PublishedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.Slug,
+		&i.DeletedAt,
+		&i.ViewCount,
+		&i.FeaturedImageID,
+		&i.Version,
+		&i.CategoryID,
+		&i.IsPinned,
+		&i.PublicID,
 	)
+	return i, err
+}
+
+const getArticleBySlug = `-- name: GetArticleBySlug :one
+SELECT id, user_id, title, content, published_at, created_at, updated_at, status, slug, deleted_at, view_count, featured_image_id, version, category_id, is_pinned, public_id FROM articles
+WHERE slug = $1 AND deleted_at IS NULL LIMIT 1
+`
+
+func (q *Queries) GetArticleBySlug(ctx context.Context, slug *string) (Article, error) {
+	row := q.db.QueryRow(ctx, getArticleBySlug, slug)
 	var i Article
 	err := row.Scan(
 		&i.ID,
@@ -43,27 +257,84 @@ func (q *Queries) CreateArticle(ctx context.Context, arg CreateArticleParams) (A
 		&i.PublishedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.Slug,
+		&i.DeletedAt,
+		&i.ViewCount,
+		&i.FeaturedImageID,
+		&i.Version,
+		&i.CategoryID,
+		&i.IsPinned,
+		&i.PublicID,
 	)
 	return i, err
 }
 
-const deleteArticle = `-- name: DeleteArticle :exec
+const hardDeleteArticle = `-- name: HardDeleteArticle :execrows
 DELETE FROM articles
 WHERE id = $1
 `
 
-func (q *Queries) DeleteArticle(ctx context.Context, id int64) error {
-	_, err := q.db.Exec(ctx, deleteArticle, id)
-	return err
+func (q *Queries) HardDeleteArticle(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, hardDeleteArticle, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
-const getArticle = `-- name: GetArticle :one
-SELECT id, user_id, title, content, published_at, created_at, updated_at FROM articles
-WHERE id = $1 LIMIT 1
+const importArticle = `-- name: ImportArticle :one
+INSERT INTO articles (
+    id, user_id, title, content, published_at, created_at, updated_at, status, slug,
+    deleted_at, view_count, featured_image_id, version, category_id, is_pinned, public_id
+) VALUES (
+    COALESCE($1::bigint, nextval(pg_get_serial_sequence('articles', 'id'))),
+    $2, $3, $4, $5,
+    $6, $7, $8, $9,
+    $10, $11, $12, $13, $14, $15, $16
+)
+RETURNING id, user_id, title, content, published_at, created_at, updated_at, status, slug, deleted_at, view_count, featured_image_id, version, category_id, is_pinned, public_id
 `
 
-func (q *Queries) GetArticle(ctx context.Context, id int64) (Article, error) {
-	row := q.db.QueryRow(ctx, getArticle, id)
+type ImportArticleParams struct {
+	ID              *int64           `json:"id"`
+	UserID          int64            `json:"user_id"`
+	Title           string           `json:"title"`
+	Content         string           `json:"content"`
+	PublishedAt     pgtype.Timestamp `json:"published_at"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	UpdatedAt       pgtype.Timestamp `json:"updated_at"`
+	Status          string           `json:"status"`
+	Slug            *string          `json:"slug"`
+	DeletedAt       pgtype.Timestamp `json:"deleted_at"`
+	ViewCount       int64            `json:"view_count"`
+	FeaturedImageID *int64           `json:"featured_image_id"`
+	Version         int32            `json:"version"`
+	CategoryID      *int64           `json:"category_id"`
+	IsPinned        bool             `json:"is_pinned"`
+	PublicID        *string          `json:"public_id"`
+}
+
+// バックアップからの取り込み用。id が NULL なら採番し、日時や閲覧数はバックアップの値を使う
+func (q *Queries) ImportArticle(ctx context.Context, arg ImportArticleParams) (Article, error) {
+	row := q.db.QueryRow(ctx, importArticle,
+		arg.ID,
+		arg.UserID,
+		arg.Title,
+		arg.Content,
+		arg.PublishedAt,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.Status,
+		arg.Slug,
+		arg.DeletedAt,
+		arg.ViewCount,
+		arg.FeaturedImageID,
+		arg.Version,
+		arg.CategoryID,
+		arg.IsPinned,
+		arg.PublicID,
+	)
 	var i Article
 	err := row.Scan(
 		&i.ID,
@@ -73,12 +344,121 @@ func (q *Queries) GetArticle(ctx context.Context, id int64) (Article, error) {
 		&i.PublishedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.Slug,
+		&i.DeletedAt,
+		&i.ViewCount,
+		&i.FeaturedImageID,
+		&i.Version,
+		&i.CategoryID,
+		&i.IsPinned,
+		&i.PublicID,
 	)
 	return i, err
 }
 
+const incrementArticleViewCount = `-- name: IncrementArticleViewCount :exec
+UPDATE articles
+SET view_count = view_count + 1
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) IncrementArticleViewCount(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, incrementArticleViewCount, id)
+	return err
+}
+
+const listAllArticles = `-- name: ListAllArticles :many
+SELECT id, user_id, title, content, published_at, created_at, updated_at, status, slug, deleted_at, view_count, featured_image_id, version, category_id, is_pinned, public_id FROM articles
+ORDER BY id
+`
+
+// 論理削除済みの記事も含む（バックアップ用）
+func (q *Queries) ListAllArticles(ctx context.Context) ([]Article, error) {
+	rows, err := q.db.Query(ctx, listAllArticles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Article{}
+	for rows.Next() {
+		var i Article
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Content,
+			&i.PublishedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.Slug,
+			&i.DeletedAt,
+			&i.ViewCount,
+			&i.FeaturedImageID,
+			&i.Version,
+			&i.CategoryID,
+			&i.IsPinned,
+			&i.PublicID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listArchiveMonths = `-- name: ListArchiveMonths :many
+SELECT
+    EXTRACT(YEAR FROM published_at)::int AS year,
+    EXTRACT(MONTH FROM published_at)::int AS month,
+    COUNT(*) AS count
+FROM articles
+WHERE deleted_at IS NULL
+  AND status = 'published'
+  AND published_at IS NOT NULL
+  AND published_at <= CURRENT_TIMESTAMP
+GROUP BY year, month
+ORDER BY year DESC, month DESC
+`
+
+type ListArchiveMonthsRow struct {
+	Year  int32 `json:"year"`
+	Month int32 `json:"month"`
+	Count int64 `json:"count"`
+}
+
+// 公開済み記事の件数を公開年月ごとに集計する（新しい月から）
+func (q *Queries) ListArchiveMonths(ctx context.Context) ([]ListArchiveMonthsRow, error) {
+	rows, err := q.db.Query(ctx, listArchiveMonths)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListArchiveMonthsRow{}
+	for rows.Next() {
+		var i ListArchiveMonthsRow
+		if err := rows.Scan(
+			&i.Year,
+			&i.Month,
+			&i.Count,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listArticles = `-- name: ListArticles :many
-SELECT id, user_id, title, content, published_at, created_at, updated_at FROM articles
+SELECT id, user_id, title, content, published_at, created_at, updated_at, status, slug, deleted_at, view_count, featured_image_id, version, category_id, is_pinned, public_id FROM articles
+WHERE deleted_at IS NULL
 ORDER BY id
 `
 
@@ -99,6 +479,15 @@ func (q *Queries) ListArticles(ctx context.Context) ([]Article, error) {
 			&i.PublishedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Status,
+			&i.Slug,
+			&i.DeletedAt,
+			&i.ViewCount,
+			&i.FeaturedImageID,
+			&i.Version,
+			&i.CategoryID,
+			&i.IsPinned,
+			&i.PublicID,
 		); err != nil {
 			return nil, err
 		}
@@ -110,14 +499,215 @@ func (q *Queries) ListArticles(ctx context.Context) ([]Article, error) {
 	return items, nil
 }
 
-const listArticlesByUser = `-- name: ListArticlesByUser :many
-SELECT id, user_id, title, content, published_at, created_at, updated_at FROM articles
-WHERE user_id = $1
-ORDER BY id
+const listArticlesByCreatedAt = `-- name: ListArticlesByCreatedAt :many
+SELECT id, user_id, title, content, published_at, created_at, updated_at, status, slug, deleted_at, view_count, featured_image_id, version, category_id, is_pinned, public_id FROM articles
+WHERE deleted_at IS NULL
+  AND status = $1
+  AND ($2::text IS NULL OR EXISTS (
+      SELECT 1 FROM article_tags at
+      INNER JOIN tags t ON t.id = at.tag_id
+      WHERE at.article_id = articles.id AND t.name = $2
+  ))
+  AND ($3::bigint[] IS NULL OR category_id = ANY($3::bigint[]))
+  AND ($4::timestamp IS NULL OR published_at >= $4)
+  AND ($5::timestamp IS NULL OR published_at <= $5)
+  AND (status <> 'published' OR published_at IS NULL OR published_at <= CURRENT_TIMESTAMP)
+  AND ($6::bigint IS NULL
+    OR is_pinned < $7::boolean
+    OR (is_pinned = $7 AND (
+        created_at > $8::timestamp
+        OR (created_at = $8 AND id < $6)
+    )))
+ORDER BY is_pinned DESC, created_at, id DESC
+LIMIT $9
 `
 
-func (q *Queries) ListArticlesByUser(ctx context.Context, userID int64) ([]Article, error) {
-	rows, err := q.db.Query(ctx, listArticlesByUser, userID)
+type ListArticlesByCreatedAtParams struct {
+	Status        string           `json:"status"`
+	Tag           *string          `json:"tag"`
+	CategoryIds   []int64          `json:"category_ids"`
+	PublishedFrom pgtype.Timestamp `json:"published_from"`
+	PublishedTo   pgtype.Timestamp `json:"published_to"`
+	CursorID      *int64           `json:"cursor_id"`
+	CursorPinned  bool             `json:"cursor_pinned"`
+	CursorTime    pgtype.Timestamp `json:"cursor_time"`
+	MaxResults    int32            `json:"max_results"`
+}
+
+// ListArticlesByID と同じく (ピン留め, 作成日時, ID) のキーセットページング
+func (q *Queries) ListArticlesByCreatedAt(ctx context.Context, arg ListArticlesByCreatedAtParams) ([]Article, error) {
+	rows, err := q.db.Query(ctx, listArticlesByCreatedAt,
+		arg.Status,
+		arg.Tag,
+		arg.CategoryIds,
+		arg.PublishedFrom,
+		arg.PublishedTo,
+		arg.CursorID,
+		arg.CursorPinned,
+		arg.CursorTime,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Article{}
+	for rows.Next() {
+		var i Article
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Content,
+			&i.PublishedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.Slug,
+			&i.DeletedAt,
+			&i.ViewCount,
+			&i.FeaturedImageID,
+			&i.Version,
+			&i.CategoryID,
+			&i.IsPinned,
+			&i.PublicID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listArticlesByCreatedAtDesc = `-- name: ListArticlesByCreatedAtDesc :many
+SELECT id, user_id, title, content, published_at, created_at, updated_at, status, slug, deleted_at, view_count, featured_image_id, version, category_id, is_pinned, public_id FROM articles
+WHERE deleted_at IS NULL
+  AND status = $1
+  AND ($2::text IS NULL OR EXISTS (
+      SELECT 1 FROM article_tags at
+      INNER JOIN tags t ON t.id = at.tag_id
+      WHERE at.article_id = articles.id AND t.name = $2
+  ))
+  AND ($3::bigint[] IS NULL OR category_id = ANY($3::bigint[]))
+  AND ($4::timestamp IS NULL OR published_at >= $4)
+  AND ($5::timestamp IS NULL OR published_at <= $5)
+  AND (status <> 'published' OR published_at IS NULL OR published_at <= CURRENT_TIMESTAMP)
+  AND ($6::bigint IS NULL
+    OR is_pinned < $7::boolean
+    OR (is_pinned = $7 AND (
+        created_at < $8::timestamp
+        OR (created_at = $8 AND id < $6)
+    )))
+ORDER BY is_pinned DESC, created_at DESC, id DESC
+LIMIT $9
+`
+
+type ListArticlesByCreatedAtDescParams struct {
+	Status        string           `json:"status"`
+	Tag           *string          `json:"tag"`
+	CategoryIds   []int64          `json:"category_ids"`
+	PublishedFrom pgtype.Timestamp `json:"published_from"`
+	PublishedTo   pgtype.Timestamp `json:"published_to"`
+	CursorID      *int64           `json:"cursor_id"`
+	CursorPinned  bool             `json:"cursor_pinned"`
+	CursorTime    pgtype.Timestamp `json:"cursor_time"`
+	MaxResults    int32            `json:"max_results"`
+}
+
+// ListArticlesByID と同じく (ピン留め, 作成日時, ID) のキーセットページング
+func (q *Queries) ListArticlesByCreatedAtDesc(ctx context.Context, arg ListArticlesByCreatedAtDescParams) ([]Article, error) {
+	rows, err := q.db.Query(ctx, listArticlesByCreatedAtDesc,
+		arg.Status,
+		arg.Tag,
+		arg.CategoryIds,
+		arg.PublishedFrom,
+		arg.PublishedTo,
+		arg.CursorID,
+		arg.CursorPinned,
+		arg.CursorTime,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Article{}
+	for rows.Next() {
+		var i Article
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Content,
+			&i.PublishedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.Slug,
+			&i.DeletedAt,
+			&i.ViewCount,
+			&i.FeaturedImageID,
+			&i.Version,
+			&i.CategoryID,
+			&i.IsPinned,
+			&i.PublicID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listArticlesByID = `-- name: ListArticlesByID :many
+SELECT id, user_id, title, content, published_at, created_at, updated_at, status, slug, deleted_at, view_count, featured_image_id, version, category_id, is_pinned, public_id FROM articles
+WHERE deleted_at IS NULL
+  AND status = $1
+  AND ($2::text IS NULL OR EXISTS (
+      SELECT 1 FROM article_tags at
+      INNER JOIN tags t ON t.id = at.tag_id
+      WHERE at.article_id = articles.id AND t.name = $2
+  ))
+  AND ($3::bigint[] IS NULL OR category_id = ANY($3::bigint[]))
+  AND ($4::timestamp IS NULL OR published_at >= $4)
+  AND ($5::timestamp IS NULL OR published_at <= $5)
+  AND (status <> 'published' OR published_at IS NULL OR published_at <= CURRENT_TIMESTAMP)
+  AND ($6::bigint IS NULL
+    OR is_pinned < $7::boolean
+    OR (is_pinned = $7 AND id < $6))
+ORDER BY is_pinned DESC, id DESC
+LIMIT $8
+`
+
+type ListArticlesByIDParams struct {
+	Status        string           `json:"status"`
+	Tag           *string          `json:"tag"`
+	CategoryIds   []int64          `json:"category_ids"`
+	PublishedFrom pgtype.Timestamp `json:"published_from"`
+	PublishedTo   pgtype.Timestamp `json:"published_to"`
+	CursorID      *int64           `json:"cursor_id"`
+	CursorPinned  bool             `json:"cursor_pinned"`
+	MaxResults    int32            `json:"max_results"`
+}
+
+// 既定の並び順（ピン留めの後は ID の降順）。cursor_* に前ページ最後の記事を渡すキーセットページングで、cursor_id が NULL なら先頭ページ
+func (q *Queries) ListArticlesByID(ctx context.Context, arg ListArticlesByIDParams) ([]Article, error) {
+	rows, err := q.db.Query(ctx, listArticlesByID,
+		arg.Status,
+		arg.Tag,
+		arg.CategoryIds,
+		arg.PublishedFrom,
+		arg.PublishedTo,
+		arg.CursorID,
+		arg.CursorPinned,
+		arg.MaxResults,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -133,6 +723,15 @@ func (q *Queries) ListArticlesByUser(ctx context.Context, userID int64) ([]Artic
 			&i.PublishedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Status,
+			&i.Slug,
+			&i.DeletedAt,
+			&i.ViewCount,
+			&i.FeaturedImageID,
+			&i.Version,
+			&i.CategoryID,
+			&i.IsPinned,
+			&i.PublicID,
 		); err != nil {
 			return nil, err
 		}
@@ -144,19 +743,989 @@ func (q *Queries) ListArticlesByUser(ctx context.Context, userID int64) ([]Artic
 	return items, nil
 }
 
+const listArticlesByPublishedAt = `-- name: ListArticlesByPublishedAt :many
+SELECT id, user_id, title, content, published_at, created_at, updated_at, status, slug, deleted_at, view_count, featured_image_id, version, category_id, is_pinned, public_id FROM articles
+WHERE deleted_at IS NULL
+  AND status = $1
+  AND ($2::text IS NULL OR EXISTS (
+      SELECT 1 FROM article_tags at
+      INNER JOIN tags t ON t.id = at.tag_id
+      WHERE at.article_id = articles.id AND t.name = $2
+  ))
+  AND ($3::bigint[] IS NULL OR category_id = ANY($3::bigint[]))
+  AND ($4::timestamp IS NULL OR published_at >= $4)
+  AND ($5::timestamp IS NULL OR published_at <= $5)
+  AND (status <> 'published' OR published_at IS NULL OR published_at <= CURRENT_TIMESTAMP)
+  AND ($6::bigint IS NULL
+    OR is_pinned < $7::boolean
+    OR (is_pinned = $7 AND (
+        published_at > $8::timestamp
+        OR (published_at IS NULL AND $8 IS NOT NULL)
+        OR (published_at IS NOT DISTINCT FROM $8 AND id < $6)
+    )))
+ORDER BY is_pinned DESC, published_at NULLS LAST, id DESC
+LIMIT $9
+`
+
+type ListArticlesByPublishedAtParams struct {
+	Status        string           `json:"status"`
+	Tag           *string          `json:"tag"`
+	CategoryIds   []int64          `json:"category_ids"`
+	PublishedFrom pgtype.Timestamp `json:"published_from"`
+	PublishedTo   pgtype.Timestamp `json:"published_to"`
+	CursorID      *int64           `json:"cursor_id"`
+	CursorPinned  bool             `json:"cursor_pinned"`
+	CursorTime    pgtype.Timestamp `json:"cursor_time"`
+	MaxResults    int32            `json:"max_results"`
+}
+
+// (ピン留め, 公開日時, ID) のキーセットページング。公開日時のない記事は最後で、cursor_time が NULL ならその中で続ける
+func (q *Queries) ListArticlesByPublishedAt(ctx context.Context, arg ListArticlesByPublishedAtParams) ([]Article, error) {
+	rows, err := q.db.Query(ctx, listArticlesByPublishedAt,
+		arg.Status,
+		arg.Tag,
+		arg.CategoryIds,
+		arg.PublishedFrom,
+		arg.PublishedTo,
+		arg.CursorID,
+		arg.CursorPinned,
+		arg.CursorTime,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Article{}
+	for rows.Next() {
+		var i Article
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Content,
+			&i.PublishedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.Slug,
+			&i.DeletedAt,
+			&i.ViewCount,
+			&i.FeaturedImageID,
+			&i.Version,
+			&i.CategoryID,
+			&i.IsPinned,
+			&i.PublicID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listArticlesByPublishedAtDesc = `-- name: ListArticlesByPublishedAtDesc :many
+SELECT id, user_id, title, content, published_at, created_at, updated_at, status, slug, deleted_at, view_count, featured_image_id, version, category_id, is_pinned, public_id FROM articles
+WHERE deleted_at IS NULL
+  AND status = $1
+  AND ($2::text IS NULL OR EXISTS (
+      SELECT 1 FROM article_tags at
+      INNER JOIN tags t ON t.id = at.tag_id
+      WHERE at.article_id = articles.id AND t.name = $2
+  ))
+  AND ($3::bigint[] IS NULL OR category_id = ANY($3::bigint[]))
+  AND ($4::timestamp IS NULL OR published_at >= $4)
+  AND ($5::timestamp IS NULL OR published_at <= $5)
+  AND (status <> 'published' OR published_at IS NULL OR published_at <= CURRENT_TIMESTAMP)
+  AND ($6::bigint IS NULL
+    OR is_pinned < $7::boolean
+    OR (is_pinned = $7 AND (
+        published_at < $8::timestamp
+        OR (published_at IS NULL AND $8 IS NOT NULL)
+        OR (published_at IS NOT DISTINCT FROM $8 AND id < $6)
+    )))
+ORDER BY is_pinned DESC, published_at DESC NULLS LAST, id DESC
+LIMIT $9
+`
+
+type ListArticlesByPublishedAtDescParams struct {
+	Status        string           `json:"status"`
+	Tag           *string          `json:"tag"`
+	CategoryIds   []int64          `json:"category_ids"`
+	PublishedFrom pgtype.Timestamp `json:"published_from"`
+	PublishedTo   pgtype.Timestamp `json:"published_to"`
+	CursorID      *int64           `json:"cursor_id"`
+	CursorPinned  bool             `json:"cursor_pinned"`
+	CursorTime    pgtype.Timestamp `json:"cursor_time"`
+	MaxResults    int32            `json:"max_results"`
+}
+
+// (ピン留め, 公開日時, ID) のキーセットページング。公開日時のない記事は最後で、cursor_time が NULL ならその中で続ける
+func (q *Queries) ListArticlesByPublishedAtDesc(ctx context.Context, arg ListArticlesByPublishedAtDescParams) ([]Article, error) {
+	rows, err := q.db.Query(ctx, listArticlesByPublishedAtDesc,
+		arg.Status,
+		arg.Tag,
+		arg.CategoryIds,
+		arg.PublishedFrom,
+		arg.PublishedTo,
+		arg.CursorID,
+		arg.CursorPinned,
+		arg.CursorTime,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Article{}
+	for rows.Next() {
+		var i Article
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Content,
+			&i.PublishedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.Slug,
+			&i.DeletedAt,
+			&i.ViewCount,
+			&i.FeaturedImageID,
+			&i.Version,
+			&i.CategoryID,
+			&i.IsPinned,
+			&i.PublicID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listArticlesByTitle = `-- name: ListArticlesByTitle :many
+SELECT id, user_id, title, content, published_at, created_at, updated_at, status, slug, deleted_at, view_count, featured_image_id, version, category_id, is_pinned, public_id FROM articles
+WHERE deleted_at IS NULL
+  AND status = $1
+  AND ($2::text IS NULL OR EXISTS (
+      SELECT 1 FROM article_tags at
+      INNER JOIN tags t ON t.id = at.tag_id
+      WHERE at.article_id = articles.id AND t.name = $2
+  ))
+  AND ($3::bigint[] IS NULL OR category_id = ANY($3::bigint[]))
+  AND ($4::timestamp IS NULL OR published_at >= $4)
+  AND ($5::timestamp IS NULL OR published_at <= $5)
+  AND (status <> 'published' OR published_at IS NULL OR published_at <= CURRENT_TIMESTAMP)
+  AND ($6::bigint IS NULL
+    OR is_pinned < $7::boolean
+    OR (is_pinned = $7 AND (
+        title > $8::text
+        OR (title = $8 AND id < $6)
+    )))
+ORDER BY is_pinned DESC, title, id DESC
+LIMIT $9
+`
+
+type ListArticlesByTitleParams struct {
+	Status        string           `json:"status"`
+	Tag           *string          `json:"tag"`
+	CategoryIds   []int64          `json:"category_ids"`
+	PublishedFrom pgtype.Timestamp `json:"published_from"`
+	PublishedTo   pgtype.Timestamp `json:"published_to"`
+	CursorID      *int64           `json:"cursor_id"`
+	CursorPinned  bool             `json:"cursor_pinned"`
+	CursorTitle   *string          `json:"cursor_title"`
+	MaxResults    int32            `json:"max_results"`
+}
+
+// ListArticlesByID と同じく (ピン留め, タイトル, ID) のキーセットページング
+func (q *Queries) ListArticlesByTitle(ctx context.Context, arg ListArticlesByTitleParams) ([]Article, error) {
+	rows, err := q.db.Query(ctx, listArticlesByTitle,
+		arg.Status,
+		arg.Tag,
+		arg.CategoryIds,
+		arg.PublishedFrom,
+		arg.PublishedTo,
+		arg.CursorID,
+		arg.CursorPinned,
+		arg.CursorTitle,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Article{}
+	for rows.Next() {
+		var i Article
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Content,
+			&i.PublishedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.Slug,
+			&i.DeletedAt,
+			&i.ViewCount,
+			&i.FeaturedImageID,
+			&i.Version,
+			&i.CategoryID,
+			&i.IsPinned,
+			&i.PublicID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listArticlesByUser = `-- name: ListArticlesByUser :many
+SELECT id, user_id, title, content, published_at, created_at, updated_at, status, slug, deleted_at, view_count, featured_image_id, version, category_id, is_pinned, public_id FROM articles
+WHERE user_id = $1 AND deleted_at IS NULL
+ORDER BY id
+`
+
+func (q *Queries) ListArticlesByUser(ctx context.Context, userID int64) ([]Article, error) {
+	rows, err := q.db.Query(ctx, listArticlesByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Article{}
+	for rows.Next() {
+		var i Article
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Content,
+			&i.PublishedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.Slug,
+			&i.DeletedAt,
+			&i.ViewCount,
+			&i.FeaturedImageID,
+			&i.Version,
+			&i.CategoryID,
+			&i.IsPinned,
+			&i.PublicID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listArticlesForExport = `-- name: ListArticlesForExport :many
+SELECT id, user_id, title, content, published_at, created_at, updated_at, status, slug, deleted_at, view_count, featured_image_id, version, category_id, is_pinned, public_id FROM articles
+WHERE deleted_at IS NULL
+  AND status = $1
+  AND ($2::bigint IS NULL OR user_id = $2)
+  AND ($3::text IS NULL OR EXISTS (
+      SELECT 1 FROM article_tags at
+      INNER JOIN tags t ON t.id = at.tag_id
+      WHERE at.article_id = articles.id AND t.name = $3
+  ))
+  AND (status <> 'published' OR published_at IS NULL OR published_at <= CURRENT_TIMESTAMP)
+  AND id > $4
+ORDER BY id
+LIMIT $5
+`
+
+type ListArticlesForExportParams struct {
+	Status     string  `json:"status"`
+	UserID     *int64  `json:"user_id"`
+	Tag        *string `json:"tag"`
+	AfterID    int64   `json:"after_id"`
+	MaxResults int32   `json:"max_results"`
+}
+
+// エクスポート用。ID順のキーセットページングのため、途中で記事が削除されても行が重複・欠落しない
+func (q *Queries) ListArticlesForExport(ctx context.Context, arg ListArticlesForExportParams) ([]Article, error) {
+	rows, err := q.db.Query(ctx, listArticlesForExport,
+		arg.Status,
+		arg.UserID,
+		arg.Tag,
+		arg.AfterID,
+		arg.MaxResults,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Article{}
+	for rows.Next() {
+		var i Article
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Content,
+			&i.PublishedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.Slug,
+			&i.DeletedAt,
+			&i.ViewCount,
+			&i.FeaturedImageID,
+			&i.Version,
+			&i.CategoryID,
+			&i.IsPinned,
+			&i.PublicID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listArticlesWithoutPublicID = `-- name: ListArticlesWithoutPublicID :many
+SELECT id FROM articles
+WHERE public_id IS NULL
+ORDER BY id
+LIMIT $1
+`
+
+// 公開IDの導入前に作成された記事の補完用
+func (q *Queries) ListArticlesWithoutPublicID(ctx context.Context, limit int32) ([]int64, error) {
+	rows, err := q.db.Query(ctx, listArticlesWithoutPublicID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExistingArticleIDs = `-- name: ListExistingArticleIDs :many
+SELECT id FROM articles
+WHERE id = ANY($1::bigint[])
+`
+
+// 論理削除済みの記事も含む
+func (q *Queries) ListExistingArticleIDs(ctx context.Context, ids []int64) ([]int64, error) {
+	rows, err := q.db.Query(ctx, listExistingArticleIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExistingArticleSlugs = `-- name: ListExistingArticleSlugs :many
+SELECT slug FROM articles
+WHERE slug = ANY($1::text[])
+`
+
+// 論理削除済みの記事も含む
+func (q *Queries) ListExistingArticleSlugs(ctx context.Context, slugs []string) ([]*string, error) {
+	rows, err := q.db.Query(ctx, listExistingArticleSlugs, slugs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*string{}
+	for rows.Next() {
+		var slug *string
+		if err := rows.Scan(&slug); err != nil {
+			return nil, err
+		}
+		items = append(items, slug)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRecentArticlesByAuthor = `-- name: ListRecentArticlesByAuthor :many
+SELECT id, user_id, title, content, published_at, created_at, updated_at, status, slug, deleted_at, view_count, featured_image_id, version, category_id, is_pinned, public_id FROM articles
+WHERE deleted_at IS NULL
+  AND status = 'published'
+  AND (published_at IS NULL OR published_at <= CURRENT_TIMESTAMP)
+  AND user_id = $1
+  AND id <> $2
+ORDER BY published_at DESC NULLS LAST, id DESC
+LIMIT $3
+`
+
+type ListRecentArticlesByAuthorParams struct {
+	UserID     int64 `json:"user_id"`
+	ExcludeID  int64 `json:"exclude_id"`
+	MaxResults int32 `json:"max_results"`
+}
+
+// 同じ著者の公開済み記事を新しい順に返す（関連記事のタグがない場合の代替）
+func (q *Queries) ListRecentArticlesByAuthor(ctx context.Context, arg ListRecentArticlesByAuthorParams) ([]Article, error) {
+	rows, err := q.db.Query(ctx, listRecentArticlesByAuthor, arg.UserID, arg.ExcludeID, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Article{}
+	for rows.Next() {
+		var i Article
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Content,
+			&i.PublishedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.Slug,
+			&i.DeletedAt,
+			&i.ViewCount,
+			&i.FeaturedImageID,
+			&i.Version,
+			&i.CategoryID,
+			&i.IsPinned,
+			&i.PublicID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRelatedArticles = `-- name: ListRelatedArticles :many
+SELECT id, user_id, title, content, published_at, created_at, updated_at, status, slug, deleted_at, view_count, featured_image_id, version, category_id, is_pinned, public_id FROM articles
+WHERE deleted_at IS NULL
+  AND status = 'published'
+  AND (published_at IS NULL OR published_at <= CURRENT_TIMESTAMP)
+  AND id <> $1
+  AND EXISTS (
+      SELECT 1 FROM article_tags at
+      WHERE at.article_id = articles.id
+        AND at.tag_id IN (SELECT tag_id FROM article_tags WHERE article_id = $1)
+  )
+ORDER BY (
+    SELECT COUNT(*) FROM article_tags at
+    WHERE at.article_id = articles.id
+      AND at.tag_id IN (SELECT tag_id FROM article_tags WHERE article_id = $1)
+) DESC, published_at DESC NULLS LAST, id DESC
+LIMIT $2
+`
+
+type ListRelatedArticlesParams struct {
+	ArticleID  int64 `json:"article_id"`
+	MaxResults int32 `json:"max_results"`
+}
+
+// 指定記事とタグを共有する公開済み記事を、共有タグ数の多い順に返す
+func (q *Queries) ListRelatedArticles(ctx context.Context, arg ListRelatedArticlesParams) ([]Article, error) {
+	rows, err := q.db.Query(ctx, listRelatedArticles, arg.ArticleID, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Article{}
+	for rows.Next() {
+		var i Article
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Content,
+			&i.PublishedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.Slug,
+			&i.DeletedAt,
+			&i.ViewCount,
+			&i.FeaturedImageID,
+			&i.Version,
+			&i.CategoryID,
+			&i.IsPinned,
+			&i.PublicID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listScheduledArticles = `-- name: ListScheduledArticles :many
+SELECT id, user_id, title, content, published_at, created_at, updated_at, status, slug, deleted_at, view_count, featured_image_id, version, category_id, is_pinned, public_id FROM articles
+WHERE status = 'draft'
+  AND deleted_at IS NULL
+  AND published_at IS NOT NULL
+  AND published_at <= CURRENT_TIMESTAMP
+ORDER BY published_at
+`
+
+func (q *Queries) ListScheduledArticles(ctx context.Context) ([]Article, error) {
+	rows, err := q.db.Query(ctx, listScheduledArticles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Article{}
+	for rows.Next() {
+		var i Article
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Content,
+			&i.PublishedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.Slug,
+			&i.DeletedAt,
+			&i.ViewCount,
+			&i.FeaturedImageID,
+			&i.Version,
+			&i.CategoryID,
+			&i.IsPinned,
+			&i.PublicID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockPinnedArticles = `-- name: LockPinnedArticles :exec
+SELECT pg_advisory_xact_lock(hashtext('articles.is_pinned'))
+`
+
+// ピン留め件数の確認と更新を直列化する（トランザクション終了まで保持）
+func (q *Queries) LockPinnedArticles(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, lockPinnedArticles)
+	return err
+}
+
+const publishArticle = `-- name: PublishArticle :one
+UPDATE articles
+SET status = 'published', published_at = CURRENT_TIMESTAMP, version = version + 1, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND deleted_at IS NULL
+  AND NOT (status = 'published' AND (published_at IS NULL OR published_at <= CURRENT_TIMESTAMP))
+RETURNING id, user_id, title, content, published_at, created_at, updated_at, status, slug, deleted_at, view_count, featured_image_id, version, category_id, is_pinned, public_id
+`
+
+// 公開中の記事（公開日時が未設定または経過済み）は対象外。呼び出し側で冪等に扱う
+func (q *Queries) PublishArticle(ctx context.Context, id int64) (Article, error) {
+	row := q.db.QueryRow(ctx, publishArticle, id)
+	var i Article
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Content,
+		&i.PublishedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.Slug,
+		&i.DeletedAt,
+		&i.ViewCount,
+		&i.FeaturedImageID,
+		&i.Version,
+		&i.CategoryID,
+		&i.IsPinned,
+		&i.PublicID,
+	)
+	return i, err
+}
+
+const publishScheduledArticle = `-- name: PublishScheduledArticle :one
+UPDATE articles
+SET status = 'published', version = version + 1, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND status = 'draft'
+  AND deleted_at IS NULL
+  AND published_at <= CURRENT_TIMESTAMP
+RETURNING id, user_id, title, content, published_at, created_at, updated_at, status, slug, deleted_at, view_count, featured_image_id, version, category_id, is_pinned, public_id
+`
+
+func (q *Queries) PublishScheduledArticle(ctx context.Context, id int64) (Article, error) {
+	row := q.db.QueryRow(ctx, publishScheduledArticle, id)
+	var i Article
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Content,
+		&i.PublishedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.Slug,
+		&i.DeletedAt,
+		&i.ViewCount,
+		&i.FeaturedImageID,
+		&i.Version,
+		&i.CategoryID,
+		&i.IsPinned,
+		&i.PublicID,
+	)
+	return i, err
+}
+
+const restoreArticle = `-- name: RestoreArticle :one
+UPDATE articles
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING id, user_id, title, content, published_at, created_at, updated_at, status, slug, deleted_at, view_count, featured_image_id, version, category_id, is_pinned, public_id
+`
+
+func (q *Queries) RestoreArticle(ctx context.Context, id int64) (Article, error) {
+	row := q.db.QueryRow(ctx, restoreArticle, id)
+	var i Article
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Content,
+		&i.PublishedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.Slug,
+		&i.DeletedAt,
+		&i.ViewCount,
+		&i.FeaturedImageID,
+		&i.Version,
+		&i.CategoryID,
+		&i.IsPinned,
+		&i.PublicID,
+	)
+	return i, err
+}
+
+const searchArticles = `-- name: SearchArticles :many
+SELECT id, user_id, title, content, published_at, created_at, updated_at, status, slug, deleted_at, view_count, featured_image_id, version, category_id, is_pinned, public_id FROM articles
+WHERE status = 'published'
+  AND deleted_at IS NULL
+  AND (published_at IS NULL OR published_at <= CURRENT_TIMESTAMP)
+  AND (title ILIKE $1 OR content ILIKE $1)
+ORDER BY id DESC
+LIMIT $2
+`
+
+type SearchArticlesParams struct {
+	Pattern    string `json:"pattern"`
+	MaxResults int32  `json:"max_results"`
+}
+
+func (q *Queries) SearchArticles(ctx context.Context, arg SearchArticlesParams) ([]Article, error) {
+	rows, err := q.db.Query(ctx, searchArticles, arg.Pattern, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Article{}
+	for rows.Next() {
+		var i Article
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Content,
+			&i.PublishedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.Slug,
+			&i.DeletedAt,
+			&i.ViewCount,
+			&i.FeaturedImageID,
+			&i.Version,
+			&i.CategoryID,
+			&i.IsPinned,
+			&i.PublicID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setArticlePinned = `-- name: SetArticlePinned :one
+UPDATE articles
+SET is_pinned = $1
+WHERE id = $2 AND deleted_at IS NULL
+RETURNING id, user_id, title, content, published_at, created_at, updated_at, status, slug, deleted_at, view_count, featured_image_id, version, category_id, is_pinned, public_id
+`
+
+type SetArticlePinnedParams struct {
+	IsPinned bool  `json:"is_pinned"`
+	ID       int64 `json:"id"`
+}
+
+func (q *Queries) SetArticlePinned(ctx context.Context, arg SetArticlePinnedParams) (Article, error) {
+	row := q.db.QueryRow(ctx, setArticlePinned, arg.IsPinned, arg.ID)
+	var i Article
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Content,
+		&i.PublishedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.Slug,
+		&i.DeletedAt,
+		&i.ViewCount,
+		&i.FeaturedImageID,
+		&i.Version,
+		&i.CategoryID,
+		&i.IsPinned,
+		&i.PublicID,
+	)
+	return i, err
+}
+
+const setArticlePublicID = `-- name: SetArticlePublicID :execrows
+UPDATE articles
+SET public_id = $2
+WHERE id = $1 AND public_id IS NULL
+`
+
+type SetArticlePublicIDParams struct {
+	ID       int64   `json:"id"`
+	PublicID *string `json:"public_id"`
+}
+
+func (q *Queries) SetArticlePublicID(ctx context.Context, arg SetArticlePublicIDParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setArticlePublicID, arg.ID, arg.PublicID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const softDeleteArticle = `-- name: SoftDeleteArticle :execrows
+UPDATE articles
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) SoftDeleteArticle(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, softDeleteArticle, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const softDeleteArticles = `-- name: SoftDeleteArticles :many
+UPDATE articles
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id = ANY($1::bigint[]) AND deleted_at IS NULL
+RETURNING id
+`
+
+func (q *Queries) SoftDeleteArticles(ctx context.Context, ids []int64) ([]int64, error) {
+	rows, err := q.db.Query(ctx, softDeleteArticles, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const syncArticleIDSequence = `-- name: SyncArticleIDSequence :exec
+SELECT setval(pg_get_serial_sequence('articles', 'id'), GREATEST((SELECT MAX(id) FROM articles), 1))
+`
+
+// ID を指定して取り込んだ後、採番が取り込んだ ID と衝突しないよう進める
+func (q *Queries) SyncArticleIDSequence(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, syncArticleIDSequence)
+	return err
+}
+
+const transferArticle = `-- name: TransferArticle :one
+UPDATE articles
+SET user_id = $1, version = version + 1, updated_at = CURRENT_TIMESTAMP
+WHERE id = $2
+  AND deleted_at IS NULL
+  AND user_id <> $1
+RETURNING id, user_id, title, content, published_at, created_at, updated_at, status, slug, deleted_at, view_count, featured_image_id, version, category_id, is_pinned, public_id
+`
+
+type TransferArticleParams struct {
+	NewUserID int64 `json:"new_user_id"`
+	ID        int64 `json:"id"`
+}
+
+// 記事の作成者を変更する。すでに移動先のユーザーが作成者なら行を返さない
+func (q *Queries) TransferArticle(ctx context.Context, arg TransferArticleParams) (Article, error) {
+	row := q.db.QueryRow(ctx, transferArticle, arg.NewUserID, arg.ID)
+	var i Article
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Content,
+		&i.PublishedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.Slug,
+		&i.DeletedAt,
+		&i.ViewCount,
+		&i.FeaturedImageID,
+		&i.Version,
+		&i.CategoryID,
+		&i.IsPinned,
+		&i.PublicID,
+	)
+	return i, err
+}
+
+const transferArticlesByUser = `-- name: TransferArticlesByUser :execrows
+UPDATE articles
+SET user_id = $1, version = version + 1, updated_at = CURRENT_TIMESTAMP
+WHERE user_id = $2
+`
+
+type TransferArticlesByUserParams struct {
+	NewUserID int64 `json:"new_user_id"`
+	UserID    int64 `json:"user_id"`
+}
+
+// ユーザーの記事をすべて別のユーザーに移す（論理削除済みの記事も含む）
+func (q *Queries) TransferArticlesByUser(ctx context.Context, arg TransferArticlesByUserParams) (int64, error) {
+	result, err := q.db.Exec(ctx, transferArticlesByUser, arg.NewUserID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const unpublishArticle = `-- name: UnpublishArticle :one
+UPDATE articles
+SET status = 'draft', published_at = NULL, version = version + 1, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND deleted_at IS NULL
+  AND NOT (status = 'draft' AND published_at IS NULL)
+RETURNING id, user_id, title, content, published_at, created_at, updated_at, status, slug, deleted_at, view_count, featured_image_id, version, category_id, is_pinned, public_id
+`
+
+// 公開日時のない下書きは対象外。呼び出し側で冪等に扱う
+func (q *Queries) UnpublishArticle(ctx context.Context, id int64) (Article, error) {
+	row := q.db.QueryRow(ctx, unpublishArticle, id)
+	var i Article
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Content,
+		&i.PublishedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.Slug,
+		&i.DeletedAt,
+		&i.ViewCount,
+		&i.FeaturedImageID,
+		&i.Version,
+		&i.CategoryID,
+		&i.IsPinned,
+		&i.PublicID,
+	)
+	return i, err
+}
+
 const updateArticle = `-- name: UpdateArticle :one
 UPDATE articles
-SET user_id = $1, title = $2, content = $3, published_at = $4, updated_at = CURRENT_TIMESTAMP
-WHERE id = $5
-RETURNING id, user_id, title, content, published_at, created_at, updated_at
+SET user_id = $1, title = $2, content = $3, published_at = $4, status = $5, featured_image_id = $6, category_id = $7, version = version + 1, updated_at = CURRENT_TIMESTAMP
+WHERE id = $8 AND deleted_at IS NULL
+  AND ($9::int IS NULL OR version = $9)
+RETURNING id, user_id, title, content, published_at, created_at, updated_at, status, slug, deleted_at, view_count, featured_image_id, version, category_id, is_pinned, public_id
 `
 
 type UpdateArticleParams struct {
-	UserID      int64            `json:"user_id"`
-	Title       string           `json:"title"`
-	Content     string           `json:"content"`
-	PublishedAt pgtype.Timestamp `json:"published_at"`
-	ID          int64            `json:"id"`
+	UserID          int64            `json:"user_id"`
+	Title           string           `json:"title"`
+	Content         string           `json:"content"`
+	PublishedAt     pgtype.Timestamp `json:"published_at"`
+	Status          string           `json:"status"`
+	FeaturedImageID *int64           `json:"featured_image_id"`
+	CategoryID      *int64           `json:"category_id"`
+	ID              int64            `json:"id"`
+	ExpectedVersion *int32           `json:"expected_version"`
 }
 
 func (q *Queries) UpdateArticle(ctx context.Context, arg UpdateArticleParams) (Article, error) {
@@ -165,7 +1734,11 @@ func (q *Queries) UpdateArticle(ctx context.Context, arg UpdateArticleParams) (A
 		arg.Title,
 		arg.Content,
 		arg.PublishedAt,
+		arg.Status,
+		arg.FeaturedImageID,
+		arg.CategoryID,
 		arg.ID,
+		arg.ExpectedVersion,
 	)
 	var i Article
 	err := row.Scan(
@@ -176,6 +1749,15 @@ func (q *Queries) UpdateArticle(ctx context.Context, arg UpdateArticleParams) (A
 		&i.PublishedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+		&i.Slug,
+		&i.DeletedAt,
+		&i.ViewCount,
+		&i.FeaturedImageID,
+		&i.Version,
+		&i.CategoryID,
+		&i.IsPinned,
+		&i.PublicID,
 	)
 	return i, err
 }
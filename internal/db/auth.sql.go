@@ -17,7 +17,7 @@ INSERT INTO access_tokens (
 ) VALUES (
     $1, $2, $3
 )
-RETURNING id, user_id, token, expires_at, created_at
+RETURNING id, user_id, token, expires_at, created_at, last_used_at
 `
 
 type CreateAccessTokenParams struct {
@@ -35,6 +35,7 @@ func (q *Queries) CreateAccessToken(ctx context.Context, arg CreateAccessTokenPa
 		&i.Token,
 		&i.ExpiresAt,
 		&i.CreatedAt,
+		&i.LastUsedAt,
 	)
 	return i, err
 }
@@ -49,11 +50,61 @@ func (q *Queries) DeleteAccessToken(ctx context.Context, token string) error {
 	return err
 }
 
+const deleteAccessTokenByID = `-- name: DeleteAccessTokenByID :execrows
+DELETE FROM access_tokens
+WHERE id = $1 AND user_id = $2
+`
+
+type DeleteAccessTokenByIDParams struct {
+	ID     int64 `json:"id"`
+	UserID int64 `json:"user_id"`
+}
+
+func (q *Queries) DeleteAccessTokenByID(ctx context.Context, arg DeleteAccessTokenByIDParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteAccessTokenByID, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteAccessTokensByUser = `-- name: DeleteAccessTokensByUser :exec
+DELETE FROM access_tokens
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteAccessTokensByUser(ctx context.Context, userID int64) error {
+	_, err := q.db.Exec(ctx, deleteAccessTokensByUser, userID)
+	return err
+}
+
+const getAccessToken = `-- name: GetAccessToken :one
+SELECT id, user_id, token, expires_at, created_at, last_used_at FROM access_tokens
+WHERE token = $1
+  AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+LIMIT 1
+`
+
+func (q *Queries) GetAccessToken(ctx context.Context, token string) (AccessToken, error) {
+	row := q.db.QueryRow(ctx, getAccessToken, token)
+	var i AccessToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Token,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, name, email, created_at, updated_at FROM users
-WHERE email = $1 LIMIT 1
+SELECT id, name, email, created_at, updated_at, role, deleted_at, password_hash FROM users
+WHERE LOWER(email) = LOWER($1) LIMIT 1
 `
 
+// 大文字小文字を区別せずに検索する（論理削除済みのユーザーも含む）
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
 	row := q.db.QueryRow(ctx, getUserByEmail, email)
 	var i User
@@ -63,15 +114,19 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.Email,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Role,
+		&i.DeletedAt,
+		&i.PasswordHash,
 	)
 	return i, err
 }
 
 const getUserByToken = `-- name: GetUserByToken :one
-SELECT u.id, u.name, u.email, u.created_at, u.updated_at FROM users u
+SELECT u.id, u.name, u.email, u.created_at, u.updated_at, u.role, u.deleted_at, u.password_hash FROM users u
 INNER JOIN access_tokens t ON u.id = t.user_id
 WHERE t.token = $1
   AND (t.expires_at IS NULL OR t.expires_at > CURRENT_TIMESTAMP)
+  AND u.deleted_at IS NULL
 LIMIT 1
 `
 
@@ -84,6 +139,88 @@ func (q *Queries) GetUserByToken(ctx context.Context, token string) (User, error
 		&i.Email,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Role,
+		&i.DeletedAt,
+		&i.PasswordHash,
 	)
 	return i, err
 }
+
+const listAccessTokensByUser = `-- name: ListAccessTokensByUser :many
+SELECT id, user_id, token, expires_at, created_at, last_used_at FROM access_tokens
+WHERE user_id = $1
+  AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+ORDER BY id DESC
+`
+
+func (q *Queries) ListAccessTokensByUser(ctx context.Context, userID int64) ([]AccessToken, error) {
+	rows, err := q.db.Query(ctx, listAccessTokensByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AccessToken{}
+	for rows.Next() {
+		var i AccessToken
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Token,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const refreshToken = `-- name: RefreshToken :one
+WITH old AS (
+    DELETE FROM access_tokens
+    WHERE token = $1
+      AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+    RETURNING user_id
+)
+INSERT INTO access_tokens (user_id, token, expires_at)
+SELECT user_id, $2::varchar, $3::timestamp FROM old
+RETURNING id, user_id, token, expires_at, created_at, last_used_at
+`
+
+type RefreshTokenParams struct {
+	OldToken  string           `json:"old_token"`
+	NewToken  string           `json:"new_token"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+}
+
+func (q *Queries) RefreshToken(ctx context.Context, arg RefreshTokenParams) (AccessToken, error) {
+	row := q.db.QueryRow(ctx, refreshToken, arg.OldToken, arg.NewToken, arg.ExpiresAt)
+	var i AccessToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Token,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const touchAccessToken = `-- name: TouchAccessToken :exec
+UPDATE access_tokens
+SET last_used_at = CURRENT_TIMESTAMP
+WHERE token = $1
+  AND (last_used_at IS NULL OR last_used_at < CURRENT_TIMESTAMP - INTERVAL '1 minute')
+`
+
+// 書き込みを抑えるため、1分以内に記録済みなら更新しない
+func (q *Queries) TouchAccessToken(ctx context.Context, token string) error {
+	_, err := q.db.Exec(ctx, touchAccessToken, token)
+	return err
+}
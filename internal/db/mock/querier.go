@@ -0,0 +1,920 @@
+// Package mock provides stand-in implementations of db.Querier and the repository
+// interfaces, so handlers, usecases and repositories can be exercised without a database.
+//
+// Every mock has a <Method>Func field for each method of its interface. A method calls
+// its function when set and otherwise falls back to the embedded interface. Leave the
+// embedded interface nil to make unexpected calls panic, or set it to a real
+// implementation to override only some methods:
+//
+//	q := &mock.Querier{
+//		CreateArticleFunc: func(ctx context.Context, arg db.CreateArticleParams) (db.Article, error) {
+//			return db.Article{ID: 1, UserID: arg.UserID, Title: arg.Title, Content: arg.Content}, nil
+//		},
+//		ListTagsByArticleFunc: func(ctx context.Context, articleID int64) ([]db.Tag, error) {
+//			return nil, nil
+//		},
+//	}
+//	articles := repository.NewArticleRepository(q)
+package mock
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/para7/nanaket-cms/internal/db"
+)
+
+// Querier is a db.Querier whose methods call the function field of the same name
+// and fall back to the embedded db.Querier when it is nil.
+type Querier struct {
+	db.Querier
+
+	ArticleSlugExistsFunc             func(ctx context.Context, slug *string) (bool, error)
+	AttachTagFunc                     func(ctx context.Context, arg db.AttachTagParams) error
+	ClaimIdempotencyKeyFunc           func(ctx context.Context, key string) (int64, error)
+	CompleteIdempotencyKeyFunc        func(ctx context.Context, arg db.CompleteIdempotencyKeyParams) error
+	ConsumeEmailChangeLinkFunc        func(ctx context.Context, token string) (db.ConsumeEmailChangeLinkRow, error)
+	ConsumeLoginLinkFunc              func(ctx context.Context, token string) (int64, error)
+	ConsumeUserQuotaFunc              func(ctx context.Context, arg db.ConsumeUserQuotaParams) (int32, error)
+	CountArticlesFunc                 func(ctx context.Context, arg db.CountArticlesParams) (int64, error)
+	CountArticlesByAuthorFunc         func(ctx context.Context, arg db.CountArticlesByAuthorParams) ([]db.CountArticlesByAuthorRow, error)
+	CountPinnedArticlesFunc           func(ctx context.Context) (int64, error)
+	CountSearchUsersFunc              func(ctx context.Context, pattern string) (int64, error)
+	CountUsersFunc                    func(ctx context.Context) (int64, error)
+	CreateAccessTokenFunc             func(ctx context.Context, arg db.CreateAccessTokenParams) (db.AccessToken, error)
+	CreateArticleFunc                 func(ctx context.Context, arg db.CreateArticleParams) (db.Article, error)
+	CreateArticleRevisionFunc         func(ctx context.Context, id int64) (int64, error)
+	CreateCategoryFunc                func(ctx context.Context, arg db.CreateCategoryParams) (db.Category, error)
+	CreateCommentFunc                 func(ctx context.Context, arg db.CreateCommentParams) (db.Comment, error)
+	CreateLoginLinkFunc               func(ctx context.Context, arg db.CreateLoginLinkParams) error
+	CreateMediaFileFunc               func(ctx context.Context, arg db.CreateMediaFileParams) (db.MediaFile, error)
+	CreateUserFunc                    func(ctx context.Context, arg db.CreateUserParams) (db.User, error)
+	CreateWebhookFunc                 func(ctx context.Context, arg db.CreateWebhookParams) (db.Webhook, error)
+	DeleteAccessTokenFunc             func(ctx context.Context, token string) error
+	DeleteAccessTokenByIDFunc         func(ctx context.Context, arg db.DeleteAccessTokenByIDParams) (int64, error)
+	DeleteAccessTokensByUserFunc      func(ctx context.Context, userID int64) error
+	DeleteCategoryFunc                func(ctx context.Context, id int64) (int64, error)
+	DeleteExpiredIdempotencyKeysFunc  func(ctx context.Context) (int64, error)
+	DeleteExpiredLoginLinksFunc       func(ctx context.Context) (int64, error)
+	DeleteMediaFileFunc               func(ctx context.Context, id int64) (int64, error)
+	DeleteUserQuotaUsageBeforeFunc    func(ctx context.Context, day pgtype.Date) (int64, error)
+	DeleteWebhookFunc                 func(ctx context.Context, id int64) (int64, error)
+	DetachTagsExceptFunc              func(ctx context.Context, arg db.DetachTagsExceptParams) error
+	GetAccessTokenFunc                func(ctx context.Context, token string) (db.AccessToken, error)
+	GetArticleFunc                    func(ctx context.Context, id int64) (db.Article, error)
+	GetArticleByPublicIDFunc          func(ctx context.Context, publicID *string) (db.Article, error)
+	GetArticleBySlugFunc              func(ctx context.Context, slug *string) (db.Article, error)
+	GetArticleRevisionFunc            func(ctx context.Context, arg db.GetArticleRevisionParams) (db.ArticleRevision, error)
+	GetCategoryFunc                   func(ctx context.Context, id int64) (db.Category, error)
+	GetIdempotencyKeyFunc             func(ctx context.Context, key string) (db.IdempotencyKey, error)
+	GetMediaFileFunc                  func(ctx context.Context, id int64) (db.MediaFile, error)
+	GetSettingFunc                    func(ctx context.Context, key string) (string, error)
+	GetUserFunc                       func(ctx context.Context, id int64) (db.User, error)
+	GetUserByEmailFunc                func(ctx context.Context, email string) (db.User, error)
+	GetUserByTokenFunc                func(ctx context.Context, token string) (db.User, error)
+	GetWebhookFunc                    func(ctx context.Context, id int64) (db.Webhook, error)
+	HardDeleteArticleFunc             func(ctx context.Context, id int64) (int64, error)
+	ImportArticleFunc                 func(ctx context.Context, arg db.ImportArticleParams) (db.Article, error)
+	ImportUserFunc                    func(ctx context.Context, arg db.ImportUserParams) (db.User, error)
+	IncrementArticleViewCountFunc     func(ctx context.Context, id int64) error
+	ListAccessTokensByUserFunc        func(ctx context.Context, userID int64) ([]db.AccessToken, error)
+	ListAllArticlesFunc               func(ctx context.Context) ([]db.Article, error)
+	ListAllUsersFunc                  func(ctx context.Context) ([]db.User, error)
+	ListArchiveMonthsFunc             func(ctx context.Context) ([]db.ListArchiveMonthsRow, error)
+	ListArticleRevisionsFunc          func(ctx context.Context, articleID int64) ([]db.ArticleRevision, error)
+	ListArticleTranslationsFunc       func(ctx context.Context, arg db.ListArticleTranslationsParams) ([]db.ArticleTranslation, error)
+	ListArticlesFunc                  func(ctx context.Context) ([]db.Article, error)
+	ListArticlesByCreatedAtFunc       func(ctx context.Context, arg db.ListArticlesByCreatedAtParams) ([]db.Article, error)
+	ListArticlesByCreatedAtDescFunc   func(ctx context.Context, arg db.ListArticlesByCreatedAtDescParams) ([]db.Article, error)
+	ListArticlesByIDFunc              func(ctx context.Context, arg db.ListArticlesByIDParams) ([]db.Article, error)
+	ListArticlesByPublishedAtFunc     func(ctx context.Context, arg db.ListArticlesByPublishedAtParams) ([]db.Article, error)
+	ListArticlesByPublishedAtDescFunc func(ctx context.Context, arg db.ListArticlesByPublishedAtDescParams) ([]db.Article, error)
+	ListArticlesByTitleFunc           func(ctx context.Context, arg db.ListArticlesByTitleParams) ([]db.Article, error)
+	ListArticlesByUserFunc            func(ctx context.Context, userID int64) ([]db.Article, error)
+	ListArticlesForExportFunc         func(ctx context.Context, arg db.ListArticlesForExportParams) ([]db.Article, error)
+	ListArticlesWithoutPublicIDFunc   func(ctx context.Context, limit int32) ([]int64, error)
+	ListCategoriesFunc                func(ctx context.Context) ([]db.Category, error)
+	ListCommentsByArticleFunc         func(ctx context.Context, arg db.ListCommentsByArticleParams) ([]db.Comment, error)
+	ListExistingArticleIDsFunc        func(ctx context.Context, ids []int64) ([]int64, error)
+	ListExistingArticleSlugsFunc      func(ctx context.Context, slugs []string) ([]*string, error)
+	ListExistingUserEmailsFunc        func(ctx context.Context, emails []string) ([]string, error)
+	ListMediaFilesFunc                func(ctx context.Context, arg db.ListMediaFilesParams) ([]db.MediaFile, error)
+	ListMediaFilesByIDsFunc           func(ctx context.Context, ids []int64) ([]db.MediaFile, error)
+	ListRecentArticlesByAuthorFunc    func(ctx context.Context, arg db.ListRecentArticlesByAuthorParams) ([]db.Article, error)
+	ListRelatedArticlesFunc           func(ctx context.Context, arg db.ListRelatedArticlesParams) ([]db.Article, error)
+	ListScheduledArticlesFunc         func(ctx context.Context) ([]db.Article, error)
+	ListTagNamesByArticlesFunc        func(ctx context.Context, articleIds []int64) ([]db.ListTagNamesByArticlesRow, error)
+	ListTagsByArticleFunc             func(ctx context.Context, articleID int64) ([]db.Tag, error)
+	ListUsersFunc                     func(ctx context.Context) ([]db.User, error)
+	ListUsersByIDsFunc                func(ctx context.Context, ids []int64) ([]db.User, error)
+	ListUsersPaginatedFunc            func(ctx context.Context, arg db.ListUsersPaginatedParams) ([]db.User, error)
+	ListWebhooksFunc                  func(ctx context.Context) ([]db.Webhook, error)
+	ListWebhooksByEventFunc           func(ctx context.Context, event string) ([]db.Webhook, error)
+	LockPinnedArticlesFunc            func(ctx context.Context) error
+	PublishArticleFunc                func(ctx context.Context, id int64) (db.Article, error)
+	PublishScheduledArticleFunc       func(ctx context.Context, id int64) (db.Article, error)
+	RefreshTokenFunc                  func(ctx context.Context, arg db.RefreshTokenParams) (db.AccessToken, error)
+	ReleaseIdempotencyKeyFunc         func(ctx context.Context, key string) error
+	RestoreArticleFunc                func(ctx context.Context, id int64) (db.Article, error)
+	RestoreUserFunc                   func(ctx context.Context, id int64) (db.User, error)
+	SearchArticlesFunc                func(ctx context.Context, arg db.SearchArticlesParams) ([]db.Article, error)
+	SearchUsersFunc                   func(ctx context.Context, arg db.SearchUsersParams) ([]db.User, error)
+	SetArticlePinnedFunc              func(ctx context.Context, arg db.SetArticlePinnedParams) (db.Article, error)
+	SetArticlePublicIDFunc            func(ctx context.Context, arg db.SetArticlePublicIDParams) (int64, error)
+	SetSettingFunc                    func(ctx context.Context, arg db.SetSettingParams) error
+	SetUserEmailFunc                  func(ctx context.Context, arg db.SetUserEmailParams) (db.User, error)
+	SetUserPasswordFunc               func(ctx context.Context, arg db.SetUserPasswordParams) (int64, error)
+	SoftDeleteArticleFunc             func(ctx context.Context, id int64) (int64, error)
+	SoftDeleteArticlesFunc            func(ctx context.Context, ids []int64) ([]int64, error)
+	SoftDeleteUserFunc                func(ctx context.Context, id int64) (int64, error)
+	SyncArticleIDSequenceFunc         func(ctx context.Context) error
+	SyncUserIDSequenceFunc            func(ctx context.Context) error
+	TouchAccessTokenFunc              func(ctx context.Context, token string) error
+	TransferArticleFunc               func(ctx context.Context, arg db.TransferArticleParams) (db.Article, error)
+	TransferArticlesByUserFunc        func(ctx context.Context, arg db.TransferArticlesByUserParams) (int64, error)
+	UnpublishArticleFunc              func(ctx context.Context, id int64) (db.Article, error)
+	UpdateArticleFunc                 func(ctx context.Context, arg db.UpdateArticleParams) (db.Article, error)
+	UpdateCategoryFunc                func(ctx context.Context, arg db.UpdateCategoryParams) (db.Category, error)
+	UpdateUserFunc                    func(ctx context.Context, arg db.UpdateUserParams) (db.User, error)
+	UpdateWebhookFunc                 func(ctx context.Context, arg db.UpdateWebhookParams) (db.Webhook, error)
+	UpsertArticleTranslationFunc      func(ctx context.Context, arg db.UpsertArticleTranslationParams) (db.ArticleTranslation, error)
+	UpsertTagFunc                     func(ctx context.Context, name string) (db.Tag, error)
+	UpsertUserByEmailFunc             func(ctx context.Context, arg db.UpsertUserByEmailParams) (db.UpsertUserByEmailRow, error)
+}
+
+func (m *Querier) ArticleSlugExists(ctx context.Context, slug *string) (bool, error) {
+	if m.ArticleSlugExistsFunc != nil {
+		return m.ArticleSlugExistsFunc(ctx, slug)
+	}
+	return m.Querier.ArticleSlugExists(ctx, slug)
+}
+
+func (m *Querier) AttachTag(ctx context.Context, arg db.AttachTagParams) error {
+	if m.AttachTagFunc != nil {
+		return m.AttachTagFunc(ctx, arg)
+	}
+	return m.Querier.AttachTag(ctx, arg)
+}
+
+func (m *Querier) ClaimIdempotencyKey(ctx context.Context, key string) (int64, error) {
+	if m.ClaimIdempotencyKeyFunc != nil {
+		return m.ClaimIdempotencyKeyFunc(ctx, key)
+	}
+	return m.Querier.ClaimIdempotencyKey(ctx, key)
+}
+
+func (m *Querier) CompleteIdempotencyKey(ctx context.Context, arg db.CompleteIdempotencyKeyParams) error {
+	if m.CompleteIdempotencyKeyFunc != nil {
+		return m.CompleteIdempotencyKeyFunc(ctx, arg)
+	}
+	return m.Querier.CompleteIdempotencyKey(ctx, arg)
+}
+
+func (m *Querier) ConsumeEmailChangeLink(ctx context.Context, token string) (db.ConsumeEmailChangeLinkRow, error) {
+	if m.ConsumeEmailChangeLinkFunc != nil {
+		return m.ConsumeEmailChangeLinkFunc(ctx, token)
+	}
+	return m.Querier.ConsumeEmailChangeLink(ctx, token)
+}
+
+func (m *Querier) ConsumeLoginLink(ctx context.Context, token string) (int64, error) {
+	if m.ConsumeLoginLinkFunc != nil {
+		return m.ConsumeLoginLinkFunc(ctx, token)
+	}
+	return m.Querier.ConsumeLoginLink(ctx, token)
+}
+
+func (m *Querier) ConsumeUserQuota(ctx context.Context, arg db.ConsumeUserQuotaParams) (int32, error) {
+	if m.ConsumeUserQuotaFunc != nil {
+		return m.ConsumeUserQuotaFunc(ctx, arg)
+	}
+	return m.Querier.ConsumeUserQuota(ctx, arg)
+}
+
+func (m *Querier) CountArticles(ctx context.Context, arg db.CountArticlesParams) (int64, error) {
+	if m.CountArticlesFunc != nil {
+		return m.CountArticlesFunc(ctx, arg)
+	}
+	return m.Querier.CountArticles(ctx, arg)
+}
+
+func (m *Querier) CountArticlesByAuthor(ctx context.Context, arg db.CountArticlesByAuthorParams) ([]db.CountArticlesByAuthorRow, error) {
+	if m.CountArticlesByAuthorFunc != nil {
+		return m.CountArticlesByAuthorFunc(ctx, arg)
+	}
+	return m.Querier.CountArticlesByAuthor(ctx, arg)
+}
+
+func (m *Querier) CountPinnedArticles(ctx context.Context) (int64, error) {
+	if m.CountPinnedArticlesFunc != nil {
+		return m.CountPinnedArticlesFunc(ctx)
+	}
+	return m.Querier.CountPinnedArticles(ctx)
+}
+
+func (m *Querier) CountSearchUsers(ctx context.Context, pattern string) (int64, error) {
+	if m.CountSearchUsersFunc != nil {
+		return m.CountSearchUsersFunc(ctx, pattern)
+	}
+	return m.Querier.CountSearchUsers(ctx, pattern)
+}
+
+func (m *Querier) CountUsers(ctx context.Context) (int64, error) {
+	if m.CountUsersFunc != nil {
+		return m.CountUsersFunc(ctx)
+	}
+	return m.Querier.CountUsers(ctx)
+}
+
+func (m *Querier) CreateAccessToken(ctx context.Context, arg db.CreateAccessTokenParams) (db.AccessToken, error) {
+	if m.CreateAccessTokenFunc != nil {
+		return m.CreateAccessTokenFunc(ctx, arg)
+	}
+	return m.Querier.CreateAccessToken(ctx, arg)
+}
+
+func (m *Querier) CreateArticle(ctx context.Context, arg db.CreateArticleParams) (db.Article, error) {
+	if m.CreateArticleFunc != nil {
+		return m.CreateArticleFunc(ctx, arg)
+	}
+	return m.Querier.CreateArticle(ctx, arg)
+}
+
+func (m *Querier) CreateArticleRevision(ctx context.Context, id int64) (int64, error) {
+	if m.CreateArticleRevisionFunc != nil {
+		return m.CreateArticleRevisionFunc(ctx, id)
+	}
+	return m.Querier.CreateArticleRevision(ctx, id)
+}
+
+func (m *Querier) CreateCategory(ctx context.Context, arg db.CreateCategoryParams) (db.Category, error) {
+	if m.CreateCategoryFunc != nil {
+		return m.CreateCategoryFunc(ctx, arg)
+	}
+	return m.Querier.CreateCategory(ctx, arg)
+}
+
+func (m *Querier) CreateComment(ctx context.Context, arg db.CreateCommentParams) (db.Comment, error) {
+	if m.CreateCommentFunc != nil {
+		return m.CreateCommentFunc(ctx, arg)
+	}
+	return m.Querier.CreateComment(ctx, arg)
+}
+
+func (m *Querier) CreateLoginLink(ctx context.Context, arg db.CreateLoginLinkParams) error {
+	if m.CreateLoginLinkFunc != nil {
+		return m.CreateLoginLinkFunc(ctx, arg)
+	}
+	return m.Querier.CreateLoginLink(ctx, arg)
+}
+
+func (m *Querier) CreateMediaFile(ctx context.Context, arg db.CreateMediaFileParams) (db.MediaFile, error) {
+	if m.CreateMediaFileFunc != nil {
+		return m.CreateMediaFileFunc(ctx, arg)
+	}
+	return m.Querier.CreateMediaFile(ctx, arg)
+}
+
+func (m *Querier) CreateUser(ctx context.Context, arg db.CreateUserParams) (db.User, error) {
+	if m.CreateUserFunc != nil {
+		return m.CreateUserFunc(ctx, arg)
+	}
+	return m.Querier.CreateUser(ctx, arg)
+}
+
+func (m *Querier) CreateWebhook(ctx context.Context, arg db.CreateWebhookParams) (db.Webhook, error) {
+	if m.CreateWebhookFunc != nil {
+		return m.CreateWebhookFunc(ctx, arg)
+	}
+	return m.Querier.CreateWebhook(ctx, arg)
+}
+
+func (m *Querier) DeleteAccessToken(ctx context.Context, token string) error {
+	if m.DeleteAccessTokenFunc != nil {
+		return m.DeleteAccessTokenFunc(ctx, token)
+	}
+	return m.Querier.DeleteAccessToken(ctx, token)
+}
+
+func (m *Querier) DeleteAccessTokenByID(ctx context.Context, arg db.DeleteAccessTokenByIDParams) (int64, error) {
+	if m.DeleteAccessTokenByIDFunc != nil {
+		return m.DeleteAccessTokenByIDFunc(ctx, arg)
+	}
+	return m.Querier.DeleteAccessTokenByID(ctx, arg)
+}
+
+func (m *Querier) DeleteAccessTokensByUser(ctx context.Context, userID int64) error {
+	if m.DeleteAccessTokensByUserFunc != nil {
+		return m.DeleteAccessTokensByUserFunc(ctx, userID)
+	}
+	return m.Querier.DeleteAccessTokensByUser(ctx, userID)
+}
+
+func (m *Querier) DeleteCategory(ctx context.Context, id int64) (int64, error) {
+	if m.DeleteCategoryFunc != nil {
+		return m.DeleteCategoryFunc(ctx, id)
+	}
+	return m.Querier.DeleteCategory(ctx, id)
+}
+
+func (m *Querier) DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	if m.DeleteExpiredIdempotencyKeysFunc != nil {
+		return m.DeleteExpiredIdempotencyKeysFunc(ctx)
+	}
+	return m.Querier.DeleteExpiredIdempotencyKeys(ctx)
+}
+
+func (m *Querier) DeleteExpiredLoginLinks(ctx context.Context) (int64, error) {
+	if m.DeleteExpiredLoginLinksFunc != nil {
+		return m.DeleteExpiredLoginLinksFunc(ctx)
+	}
+	return m.Querier.DeleteExpiredLoginLinks(ctx)
+}
+
+func (m *Querier) DeleteMediaFile(ctx context.Context, id int64) (int64, error) {
+	if m.DeleteMediaFileFunc != nil {
+		return m.DeleteMediaFileFunc(ctx, id)
+	}
+	return m.Querier.DeleteMediaFile(ctx, id)
+}
+
+func (m *Querier) DeleteUserQuotaUsageBefore(ctx context.Context, day pgtype.Date) (int64, error) {
+	if m.DeleteUserQuotaUsageBeforeFunc != nil {
+		return m.DeleteUserQuotaUsageBeforeFunc(ctx, day)
+	}
+	return m.Querier.DeleteUserQuotaUsageBefore(ctx, day)
+}
+
+func (m *Querier) DeleteWebhook(ctx context.Context, id int64) (int64, error) {
+	if m.DeleteWebhookFunc != nil {
+		return m.DeleteWebhookFunc(ctx, id)
+	}
+	return m.Querier.DeleteWebhook(ctx, id)
+}
+
+func (m *Querier) DetachTagsExcept(ctx context.Context, arg db.DetachTagsExceptParams) error {
+	if m.DetachTagsExceptFunc != nil {
+		return m.DetachTagsExceptFunc(ctx, arg)
+	}
+	return m.Querier.DetachTagsExcept(ctx, arg)
+}
+
+func (m *Querier) GetAccessToken(ctx context.Context, token string) (db.AccessToken, error) {
+	if m.GetAccessTokenFunc != nil {
+		return m.GetAccessTokenFunc(ctx, token)
+	}
+	return m.Querier.GetAccessToken(ctx, token)
+}
+
+func (m *Querier) GetArticle(ctx context.Context, id int64) (db.Article, error) {
+	if m.GetArticleFunc != nil {
+		return m.GetArticleFunc(ctx, id)
+	}
+	return m.Querier.GetArticle(ctx, id)
+}
+
+func (m *Querier) GetArticleByPublicID(ctx context.Context, publicID *string) (db.Article, error) {
+	if m.GetArticleByPublicIDFunc != nil {
+		return m.GetArticleByPublicIDFunc(ctx, publicID)
+	}
+	return m.Querier.GetArticleByPublicID(ctx, publicID)
+}
+
+func (m *Querier) GetArticleBySlug(ctx context.Context, slug *string) (db.Article, error) {
+	if m.GetArticleBySlugFunc != nil {
+		return m.GetArticleBySlugFunc(ctx, slug)
+	}
+	return m.Querier.GetArticleBySlug(ctx, slug)
+}
+
+func (m *Querier) GetArticleRevision(ctx context.Context, arg db.GetArticleRevisionParams) (db.ArticleRevision, error) {
+	if m.GetArticleRevisionFunc != nil {
+		return m.GetArticleRevisionFunc(ctx, arg)
+	}
+	return m.Querier.GetArticleRevision(ctx, arg)
+}
+
+func (m *Querier) GetCategory(ctx context.Context, id int64) (db.Category, error) {
+	if m.GetCategoryFunc != nil {
+		return m.GetCategoryFunc(ctx, id)
+	}
+	return m.Querier.GetCategory(ctx, id)
+}
+
+func (m *Querier) GetIdempotencyKey(ctx context.Context, key string) (db.IdempotencyKey, error) {
+	if m.GetIdempotencyKeyFunc != nil {
+		return m.GetIdempotencyKeyFunc(ctx, key)
+	}
+	return m.Querier.GetIdempotencyKey(ctx, key)
+}
+
+func (m *Querier) GetMediaFile(ctx context.Context, id int64) (db.MediaFile, error) {
+	if m.GetMediaFileFunc != nil {
+		return m.GetMediaFileFunc(ctx, id)
+	}
+	return m.Querier.GetMediaFile(ctx, id)
+}
+
+func (m *Querier) GetSetting(ctx context.Context, key string) (string, error) {
+	if m.GetSettingFunc != nil {
+		return m.GetSettingFunc(ctx, key)
+	}
+	return m.Querier.GetSetting(ctx, key)
+}
+
+func (m *Querier) GetUser(ctx context.Context, id int64) (db.User, error) {
+	if m.GetUserFunc != nil {
+		return m.GetUserFunc(ctx, id)
+	}
+	return m.Querier.GetUser(ctx, id)
+}
+
+func (m *Querier) GetUserByEmail(ctx context.Context, email string) (db.User, error) {
+	if m.GetUserByEmailFunc != nil {
+		return m.GetUserByEmailFunc(ctx, email)
+	}
+	return m.Querier.GetUserByEmail(ctx, email)
+}
+
+func (m *Querier) GetUserByToken(ctx context.Context, token string) (db.User, error) {
+	if m.GetUserByTokenFunc != nil {
+		return m.GetUserByTokenFunc(ctx, token)
+	}
+	return m.Querier.GetUserByToken(ctx, token)
+}
+
+func (m *Querier) GetWebhook(ctx context.Context, id int64) (db.Webhook, error) {
+	if m.GetWebhookFunc != nil {
+		return m.GetWebhookFunc(ctx, id)
+	}
+	return m.Querier.GetWebhook(ctx, id)
+}
+
+func (m *Querier) HardDeleteArticle(ctx context.Context, id int64) (int64, error) {
+	if m.HardDeleteArticleFunc != nil {
+		return m.HardDeleteArticleFunc(ctx, id)
+	}
+	return m.Querier.HardDeleteArticle(ctx, id)
+}
+
+func (m *Querier) ImportArticle(ctx context.Context, arg db.ImportArticleParams) (db.Article, error) {
+	if m.ImportArticleFunc != nil {
+		return m.ImportArticleFunc(ctx, arg)
+	}
+	return m.Querier.ImportArticle(ctx, arg)
+}
+
+func (m *Querier) ImportUser(ctx context.Context, arg db.ImportUserParams) (db.User, error) {
+	if m.ImportUserFunc != nil {
+		return m.ImportUserFunc(ctx, arg)
+	}
+	return m.Querier.ImportUser(ctx, arg)
+}
+
+func (m *Querier) IncrementArticleViewCount(ctx context.Context, id int64) error {
+	if m.IncrementArticleViewCountFunc != nil {
+		return m.IncrementArticleViewCountFunc(ctx, id)
+	}
+	return m.Querier.IncrementArticleViewCount(ctx, id)
+}
+
+func (m *Querier) ListAccessTokensByUser(ctx context.Context, userID int64) ([]db.AccessToken, error) {
+	if m.ListAccessTokensByUserFunc != nil {
+		return m.ListAccessTokensByUserFunc(ctx, userID)
+	}
+	return m.Querier.ListAccessTokensByUser(ctx, userID)
+}
+
+func (m *Querier) ListAllArticles(ctx context.Context) ([]db.Article, error) {
+	if m.ListAllArticlesFunc != nil {
+		return m.ListAllArticlesFunc(ctx)
+	}
+	return m.Querier.ListAllArticles(ctx)
+}
+
+func (m *Querier) ListAllUsers(ctx context.Context) ([]db.User, error) {
+	if m.ListAllUsersFunc != nil {
+		return m.ListAllUsersFunc(ctx)
+	}
+	return m.Querier.ListAllUsers(ctx)
+}
+
+func (m *Querier) ListArchiveMonths(ctx context.Context) ([]db.ListArchiveMonthsRow, error) {
+	if m.ListArchiveMonthsFunc != nil {
+		return m.ListArchiveMonthsFunc(ctx)
+	}
+	return m.Querier.ListArchiveMonths(ctx)
+}
+
+func (m *Querier) ListArticleRevisions(ctx context.Context, articleID int64) ([]db.ArticleRevision, error) {
+	if m.ListArticleRevisionsFunc != nil {
+		return m.ListArticleRevisionsFunc(ctx, articleID)
+	}
+	return m.Querier.ListArticleRevisions(ctx, articleID)
+}
+
+func (m *Querier) ListArticleTranslations(ctx context.Context, arg db.ListArticleTranslationsParams) ([]db.ArticleTranslation, error) {
+	if m.ListArticleTranslationsFunc != nil {
+		return m.ListArticleTranslationsFunc(ctx, arg)
+	}
+	return m.Querier.ListArticleTranslations(ctx, arg)
+}
+
+func (m *Querier) ListArticles(ctx context.Context) ([]db.Article, error) {
+	if m.ListArticlesFunc != nil {
+		return m.ListArticlesFunc(ctx)
+	}
+	return m.Querier.ListArticles(ctx)
+}
+
+func (m *Querier) ListArticlesByCreatedAt(ctx context.Context, arg db.ListArticlesByCreatedAtParams) ([]db.Article, error) {
+	if m.ListArticlesByCreatedAtFunc != nil {
+		return m.ListArticlesByCreatedAtFunc(ctx, arg)
+	}
+	return m.Querier.ListArticlesByCreatedAt(ctx, arg)
+}
+
+func (m *Querier) ListArticlesByCreatedAtDesc(ctx context.Context, arg db.ListArticlesByCreatedAtDescParams) ([]db.Article, error) {
+	if m.ListArticlesByCreatedAtDescFunc != nil {
+		return m.ListArticlesByCreatedAtDescFunc(ctx, arg)
+	}
+	return m.Querier.ListArticlesByCreatedAtDesc(ctx, arg)
+}
+
+func (m *Querier) ListArticlesByID(ctx context.Context, arg db.ListArticlesByIDParams) ([]db.Article, error) {
+	if m.ListArticlesByIDFunc != nil {
+		return m.ListArticlesByIDFunc(ctx, arg)
+	}
+	return m.Querier.ListArticlesByID(ctx, arg)
+}
+
+func (m *Querier) ListArticlesByPublishedAt(ctx context.Context, arg db.ListArticlesByPublishedAtParams) ([]db.Article, error) {
+	if m.ListArticlesByPublishedAtFunc != nil {
+		return m.ListArticlesByPublishedAtFunc(ctx, arg)
+	}
+	return m.Querier.ListArticlesByPublishedAt(ctx, arg)
+}
+
+func (m *Querier) ListArticlesByPublishedAtDesc(ctx context.Context, arg db.ListArticlesByPublishedAtDescParams) ([]db.Article, error) {
+	if m.ListArticlesByPublishedAtDescFunc != nil {
+		return m.ListArticlesByPublishedAtDescFunc(ctx, arg)
+	}
+	return m.Querier.ListArticlesByPublishedAtDesc(ctx, arg)
+}
+
+func (m *Querier) ListArticlesByTitle(ctx context.Context, arg db.ListArticlesByTitleParams) ([]db.Article, error) {
+	if m.ListArticlesByTitleFunc != nil {
+		return m.ListArticlesByTitleFunc(ctx, arg)
+	}
+	return m.Querier.ListArticlesByTitle(ctx, arg)
+}
+
+func (m *Querier) ListArticlesByUser(ctx context.Context, userID int64) ([]db.Article, error) {
+	if m.ListArticlesByUserFunc != nil {
+		return m.ListArticlesByUserFunc(ctx, userID)
+	}
+	return m.Querier.ListArticlesByUser(ctx, userID)
+}
+
+func (m *Querier) ListArticlesForExport(ctx context.Context, arg db.ListArticlesForExportParams) ([]db.Article, error) {
+	if m.ListArticlesForExportFunc != nil {
+		return m.ListArticlesForExportFunc(ctx, arg)
+	}
+	return m.Querier.ListArticlesForExport(ctx, arg)
+}
+
+func (m *Querier) ListArticlesWithoutPublicID(ctx context.Context, limit int32) ([]int64, error) {
+	if m.ListArticlesWithoutPublicIDFunc != nil {
+		return m.ListArticlesWithoutPublicIDFunc(ctx, limit)
+	}
+	return m.Querier.ListArticlesWithoutPublicID(ctx, limit)
+}
+
+func (m *Querier) ListCategories(ctx context.Context) ([]db.Category, error) {
+	if m.ListCategoriesFunc != nil {
+		return m.ListCategoriesFunc(ctx)
+	}
+	return m.Querier.ListCategories(ctx)
+}
+
+func (m *Querier) ListCommentsByArticle(ctx context.Context, arg db.ListCommentsByArticleParams) ([]db.Comment, error) {
+	if m.ListCommentsByArticleFunc != nil {
+		return m.ListCommentsByArticleFunc(ctx, arg)
+	}
+	return m.Querier.ListCommentsByArticle(ctx, arg)
+}
+
+func (m *Querier) ListExistingArticleIDs(ctx context.Context, ids []int64) ([]int64, error) {
+	if m.ListExistingArticleIDsFunc != nil {
+		return m.ListExistingArticleIDsFunc(ctx, ids)
+	}
+	return m.Querier.ListExistingArticleIDs(ctx, ids)
+}
+
+func (m *Querier) ListExistingArticleSlugs(ctx context.Context, slugs []string) ([]*string, error) {
+	if m.ListExistingArticleSlugsFunc != nil {
+		return m.ListExistingArticleSlugsFunc(ctx, slugs)
+	}
+	return m.Querier.ListExistingArticleSlugs(ctx, slugs)
+}
+
+func (m *Querier) ListExistingUserEmails(ctx context.Context, emails []string) ([]string, error) {
+	if m.ListExistingUserEmailsFunc != nil {
+		return m.ListExistingUserEmailsFunc(ctx, emails)
+	}
+	return m.Querier.ListExistingUserEmails(ctx, emails)
+}
+
+func (m *Querier) ListMediaFiles(ctx context.Context, arg db.ListMediaFilesParams) ([]db.MediaFile, error) {
+	if m.ListMediaFilesFunc != nil {
+		return m.ListMediaFilesFunc(ctx, arg)
+	}
+	return m.Querier.ListMediaFiles(ctx, arg)
+}
+
+func (m *Querier) ListMediaFilesByIDs(ctx context.Context, ids []int64) ([]db.MediaFile, error) {
+	if m.ListMediaFilesByIDsFunc != nil {
+		return m.ListMediaFilesByIDsFunc(ctx, ids)
+	}
+	return m.Querier.ListMediaFilesByIDs(ctx, ids)
+}
+
+func (m *Querier) ListRecentArticlesByAuthor(ctx context.Context, arg db.ListRecentArticlesByAuthorParams) ([]db.Article, error) {
+	if m.ListRecentArticlesByAuthorFunc != nil {
+		return m.ListRecentArticlesByAuthorFunc(ctx, arg)
+	}
+	return m.Querier.ListRecentArticlesByAuthor(ctx, arg)
+}
+
+func (m *Querier) ListRelatedArticles(ctx context.Context, arg db.ListRelatedArticlesParams) ([]db.Article, error) {
+	if m.ListRelatedArticlesFunc != nil {
+		return m.ListRelatedArticlesFunc(ctx, arg)
+	}
+	return m.Querier.ListRelatedArticles(ctx, arg)
+}
+
+func (m *Querier) ListScheduledArticles(ctx context.Context) ([]db.Article, error) {
+	if m.ListScheduledArticlesFunc != nil {
+		return m.ListScheduledArticlesFunc(ctx)
+	}
+	return m.Querier.ListScheduledArticles(ctx)
+}
+
+func (m *Querier) ListTagNamesByArticles(ctx context.Context, articleIds []int64) ([]db.ListTagNamesByArticlesRow, error) {
+	if m.ListTagNamesByArticlesFunc != nil {
+		return m.ListTagNamesByArticlesFunc(ctx, articleIds)
+	}
+	return m.Querier.ListTagNamesByArticles(ctx, articleIds)
+}
+
+func (m *Querier) ListTagsByArticle(ctx context.Context, articleID int64) ([]db.Tag, error) {
+	if m.ListTagsByArticleFunc != nil {
+		return m.ListTagsByArticleFunc(ctx, articleID)
+	}
+	return m.Querier.ListTagsByArticle(ctx, articleID)
+}
+
+func (m *Querier) ListUsers(ctx context.Context) ([]db.User, error) {
+	if m.ListUsersFunc != nil {
+		return m.ListUsersFunc(ctx)
+	}
+	return m.Querier.ListUsers(ctx)
+}
+
+func (m *Querier) ListUsersByIDs(ctx context.Context, ids []int64) ([]db.User, error) {
+	if m.ListUsersByIDsFunc != nil {
+		return m.ListUsersByIDsFunc(ctx, ids)
+	}
+	return m.Querier.ListUsersByIDs(ctx, ids)
+}
+
+func (m *Querier) ListUsersPaginated(ctx context.Context, arg db.ListUsersPaginatedParams) ([]db.User, error) {
+	if m.ListUsersPaginatedFunc != nil {
+		return m.ListUsersPaginatedFunc(ctx, arg)
+	}
+	return m.Querier.ListUsersPaginated(ctx, arg)
+}
+
+func (m *Querier) ListWebhooks(ctx context.Context) ([]db.Webhook, error) {
+	if m.ListWebhooksFunc != nil {
+		return m.ListWebhooksFunc(ctx)
+	}
+	return m.Querier.ListWebhooks(ctx)
+}
+
+func (m *Querier) ListWebhooksByEvent(ctx context.Context, event string) ([]db.Webhook, error) {
+	if m.ListWebhooksByEventFunc != nil {
+		return m.ListWebhooksByEventFunc(ctx, event)
+	}
+	return m.Querier.ListWebhooksByEvent(ctx, event)
+}
+
+func (m *Querier) LockPinnedArticles(ctx context.Context) error {
+	if m.LockPinnedArticlesFunc != nil {
+		return m.LockPinnedArticlesFunc(ctx)
+	}
+	return m.Querier.LockPinnedArticles(ctx)
+}
+
+func (m *Querier) PublishArticle(ctx context.Context, id int64) (db.Article, error) {
+	if m.PublishArticleFunc != nil {
+		return m.PublishArticleFunc(ctx, id)
+	}
+	return m.Querier.PublishArticle(ctx, id)
+}
+
+func (m *Querier) PublishScheduledArticle(ctx context.Context, id int64) (db.Article, error) {
+	if m.PublishScheduledArticleFunc != nil {
+		return m.PublishScheduledArticleFunc(ctx, id)
+	}
+	return m.Querier.PublishScheduledArticle(ctx, id)
+}
+
+func (m *Querier) RefreshToken(ctx context.Context, arg db.RefreshTokenParams) (db.AccessToken, error) {
+	if m.RefreshTokenFunc != nil {
+		return m.RefreshTokenFunc(ctx, arg)
+	}
+	return m.Querier.RefreshToken(ctx, arg)
+}
+
+func (m *Querier) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	if m.ReleaseIdempotencyKeyFunc != nil {
+		return m.ReleaseIdempotencyKeyFunc(ctx, key)
+	}
+	return m.Querier.ReleaseIdempotencyKey(ctx, key)
+}
+
+func (m *Querier) RestoreArticle(ctx context.Context, id int64) (db.Article, error) {
+	if m.RestoreArticleFunc != nil {
+		return m.RestoreArticleFunc(ctx, id)
+	}
+	return m.Querier.RestoreArticle(ctx, id)
+}
+
+func (m *Querier) RestoreUser(ctx context.Context, id int64) (db.User, error) {
+	if m.RestoreUserFunc != nil {
+		return m.RestoreUserFunc(ctx, id)
+	}
+	return m.Querier.RestoreUser(ctx, id)
+}
+
+func (m *Querier) SearchArticles(ctx context.Context, arg db.SearchArticlesParams) ([]db.Article, error) {
+	if m.SearchArticlesFunc != nil {
+		return m.SearchArticlesFunc(ctx, arg)
+	}
+	return m.Querier.SearchArticles(ctx, arg)
+}
+
+func (m *Querier) SearchUsers(ctx context.Context, arg db.SearchUsersParams) ([]db.User, error) {
+	if m.SearchUsersFunc != nil {
+		return m.SearchUsersFunc(ctx, arg)
+	}
+	return m.Querier.SearchUsers(ctx, arg)
+}
+
+func (m *Querier) SetArticlePinned(ctx context.Context, arg db.SetArticlePinnedParams) (db.Article, error) {
+	if m.SetArticlePinnedFunc != nil {
+		return m.SetArticlePinnedFunc(ctx, arg)
+	}
+	return m.Querier.SetArticlePinned(ctx, arg)
+}
+
+func (m *Querier) SetArticlePublicID(ctx context.Context, arg db.SetArticlePublicIDParams) (int64, error) {
+	if m.SetArticlePublicIDFunc != nil {
+		return m.SetArticlePublicIDFunc(ctx, arg)
+	}
+	return m.Querier.SetArticlePublicID(ctx, arg)
+}
+
+func (m *Querier) SetSetting(ctx context.Context, arg db.SetSettingParams) error {
+	if m.SetSettingFunc != nil {
+		return m.SetSettingFunc(ctx, arg)
+	}
+	return m.Querier.SetSetting(ctx, arg)
+}
+
+func (m *Querier) SetUserEmail(ctx context.Context, arg db.SetUserEmailParams) (db.User, error) {
+	if m.SetUserEmailFunc != nil {
+		return m.SetUserEmailFunc(ctx, arg)
+	}
+	return m.Querier.SetUserEmail(ctx, arg)
+}
+
+func (m *Querier) SetUserPassword(ctx context.Context, arg db.SetUserPasswordParams) (int64, error) {
+	if m.SetUserPasswordFunc != nil {
+		return m.SetUserPasswordFunc(ctx, arg)
+	}
+	return m.Querier.SetUserPassword(ctx, arg)
+}
+
+func (m *Querier) SoftDeleteArticle(ctx context.Context, id int64) (int64, error) {
+	if m.SoftDeleteArticleFunc != nil {
+		return m.SoftDeleteArticleFunc(ctx, id)
+	}
+	return m.Querier.SoftDeleteArticle(ctx, id)
+}
+
+func (m *Querier) SoftDeleteArticles(ctx context.Context, ids []int64) ([]int64, error) {
+	if m.SoftDeleteArticlesFunc != nil {
+		return m.SoftDeleteArticlesFunc(ctx, ids)
+	}
+	return m.Querier.SoftDeleteArticles(ctx, ids)
+}
+
+func (m *Querier) SoftDeleteUser(ctx context.Context, id int64) (int64, error) {
+	if m.SoftDeleteUserFunc != nil {
+		return m.SoftDeleteUserFunc(ctx, id)
+	}
+	return m.Querier.SoftDeleteUser(ctx, id)
+}
+
+func (m *Querier) SyncArticleIDSequence(ctx context.Context) error {
+	if m.SyncArticleIDSequenceFunc != nil {
+		return m.SyncArticleIDSequenceFunc(ctx)
+	}
+	return m.Querier.SyncArticleIDSequence(ctx)
+}
+
+func (m *Querier) SyncUserIDSequence(ctx context.Context) error {
+	if m.SyncUserIDSequenceFunc != nil {
+		return m.SyncUserIDSequenceFunc(ctx)
+	}
+	return m.Querier.SyncUserIDSequence(ctx)
+}
+
+func (m *Querier) TouchAccessToken(ctx context.Context, token string) error {
+	if m.TouchAccessTokenFunc != nil {
+		return m.TouchAccessTokenFunc(ctx, token)
+	}
+	return m.Querier.TouchAccessToken(ctx, token)
+}
+
+func (m *Querier) TransferArticle(ctx context.Context, arg db.TransferArticleParams) (db.Article, error) {
+	if m.TransferArticleFunc != nil {
+		return m.TransferArticleFunc(ctx, arg)
+	}
+	return m.Querier.TransferArticle(ctx, arg)
+}
+
+func (m *Querier) TransferArticlesByUser(ctx context.Context, arg db.TransferArticlesByUserParams) (int64, error) {
+	if m.TransferArticlesByUserFunc != nil {
+		return m.TransferArticlesByUserFunc(ctx, arg)
+	}
+	return m.Querier.TransferArticlesByUser(ctx, arg)
+}
+
+func (m *Querier) UnpublishArticle(ctx context.Context, id int64) (db.Article, error) {
+	if m.UnpublishArticleFunc != nil {
+		return m.UnpublishArticleFunc(ctx, id)
+	}
+	return m.Querier.UnpublishArticle(ctx, id)
+}
+
+func (m *Querier) UpdateArticle(ctx context.Context, arg db.UpdateArticleParams) (db.Article, error) {
+	if m.UpdateArticleFunc != nil {
+		return m.UpdateArticleFunc(ctx, arg)
+	}
+	return m.Querier.UpdateArticle(ctx, arg)
+}
+
+func (m *Querier) UpdateCategory(ctx context.Context, arg db.UpdateCategoryParams) (db.Category, error) {
+	if m.UpdateCategoryFunc != nil {
+		return m.UpdateCategoryFunc(ctx, arg)
+	}
+	return m.Querier.UpdateCategory(ctx, arg)
+}
+
+func (m *Querier) UpdateUser(ctx context.Context, arg db.UpdateUserParams) (db.User, error) {
+	if m.UpdateUserFunc != nil {
+		return m.UpdateUserFunc(ctx, arg)
+	}
+	return m.Querier.UpdateUser(ctx, arg)
+}
+
+func (m *Querier) UpdateWebhook(ctx context.Context, arg db.UpdateWebhookParams) (db.Webhook, error) {
+	if m.UpdateWebhookFunc != nil {
+		return m.UpdateWebhookFunc(ctx, arg)
+	}
+	return m.Querier.UpdateWebhook(ctx, arg)
+}
+
+func (m *Querier) UpsertArticleTranslation(ctx context.Context, arg db.UpsertArticleTranslationParams) (db.ArticleTranslation, error) {
+	if m.UpsertArticleTranslationFunc != nil {
+		return m.UpsertArticleTranslationFunc(ctx, arg)
+	}
+	return m.Querier.UpsertArticleTranslation(ctx, arg)
+}
+
+func (m *Querier) UpsertTag(ctx context.Context, name string) (db.Tag, error) {
+	if m.UpsertTagFunc != nil {
+		return m.UpsertTagFunc(ctx, name)
+	}
+	return m.Querier.UpsertTag(ctx, name)
+}
+
+func (m *Querier) UpsertUserByEmail(ctx context.Context, arg db.UpsertUserByEmailParams) (db.UpsertUserByEmailRow, error) {
+	if m.UpsertUserByEmailFunc != nil {
+		return m.UpsertUserByEmailFunc(ctx, arg)
+	}
+	return m.Querier.UpsertUserByEmail(ctx, arg)
+}
@@ -0,0 +1,963 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/para7/nanaket-cms/internal/db"
+	"github.com/para7/nanaket-cms/internal/repository"
+)
+
+// ArticleRepository is a repository.ArticleRepository whose methods call the function field of the same name
+// and fall back to the embedded repository.ArticleRepository when it is nil.
+type ArticleRepository struct {
+	repository.ArticleRepository
+
+	CreateFunc              func(ctx context.Context, userID int64, title, content, status, slug, publicID string, publishedAt *time.Time, featuredImageID, categoryID *int64) (db.Article, error)
+	GetByIDFunc             func(ctx context.Context, id int64) (db.Article, error)
+	GetBySlugFunc           func(ctx context.Context, slug string) (db.Article, error)
+	GetByPublicIDFunc       func(ctx context.Context, publicID string) (db.Article, error)
+	SlugExistsFunc          func(ctx context.Context, slug string) (bool, error)
+	ListFunc                func(ctx context.Context) ([]db.Article, error)
+	ListPaginatedFunc       func(ctx context.Context, sort, status, tag string, categoryIDs []int64, publishedFrom, publishedTo *time.Time, limit int32, after *repository.ArticleCursor) ([]db.Article, error)
+	CountFunc               func(ctx context.Context, status, tag string, categoryIDs []int64, publishedFrom, publishedTo *time.Time, userID int64) (int64, error)
+	ListForExportFunc       func(ctx context.Context, status, tag string, userID, afterID int64, limit int32) ([]db.Article, error)
+	ArchiveMonthsFunc       func(ctx context.Context) ([]db.ListArchiveMonthsRow, error)
+	CountByAuthorFunc       func(ctx context.Context, status string, includeEmpty bool) ([]db.CountArticlesByAuthorRow, error)
+	SearchFunc              func(ctx context.Context, pattern string, limit int32) ([]db.Article, error)
+	ListRelatedFunc         func(ctx context.Context, id int64, limit int32) ([]db.Article, error)
+	ListRecentByAuthorFunc  func(ctx context.Context, userID, excludeID int64, limit int32) ([]db.Article, error)
+	ListScheduledFunc       func(ctx context.Context) ([]db.Article, error)
+	PublishScheduledFunc    func(ctx context.Context, id int64) (db.Article, error)
+	PublishFunc             func(ctx context.Context, id int64) (db.Article, error)
+	UnpublishFunc           func(ctx context.Context, id int64) (db.Article, error)
+	TransferFunc            func(ctx context.Context, id, newUserID int64) (db.Article, error)
+	TransferAllByUserFunc   func(ctx context.Context, userID, newUserID int64) (int64, error)
+	UpdateFunc              func(ctx context.Context, id, userID int64, title, content, status string, publishedAt *time.Time, featuredImageID, categoryID *int64, expectedVersion *int32) (db.Article, error)
+	IncrementViewCountFunc  func(ctx context.Context, id int64) error
+	DeleteFunc              func(ctx context.Context, id int64) error
+	DeleteArticlesFunc      func(ctx context.Context, ids []int64) ([]int64, error)
+	RestoreFunc             func(ctx context.Context, id int64) (db.Article, error)
+	LockPinnedFunc          func(ctx context.Context) error
+	CountPinnedFunc         func(ctx context.Context) (int64, error)
+	SetPinnedFunc           func(ctx context.Context, id int64, pinned bool) (db.Article, error)
+	HardDeleteFunc          func(ctx context.Context, id int64) error
+	ListAllFunc             func(ctx context.Context) ([]db.Article, error)
+	ExistingIDsFunc         func(ctx context.Context, ids []int64) ([]int64, error)
+	ExistingSlugsFunc       func(ctx context.Context, slugs []string) ([]string, error)
+	ImportFunc              func(ctx context.Context, article db.Article, preserveID bool) (db.Article, error)
+	SyncIDSequenceFunc      func(ctx context.Context) error
+	ListWithoutPublicIDFunc func(ctx context.Context, limit int32) ([]int64, error)
+	SetPublicIDFunc         func(ctx context.Context, id int64, publicID string) error
+}
+
+func (m *ArticleRepository) Create(ctx context.Context, userID int64, title, content, status, slug, publicID string, publishedAt *time.Time, featuredImageID, categoryID *int64) (db.Article, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, userID, title, content, status, slug, publicID, publishedAt, featuredImageID, categoryID)
+	}
+	return m.ArticleRepository.Create(ctx, userID, title, content, status, slug, publicID, publishedAt, featuredImageID, categoryID)
+}
+
+func (m *ArticleRepository) GetByID(ctx context.Context, id int64) (db.Article, error) {
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(ctx, id)
+	}
+	return m.ArticleRepository.GetByID(ctx, id)
+}
+
+func (m *ArticleRepository) GetBySlug(ctx context.Context, slug string) (db.Article, error) {
+	if m.GetBySlugFunc != nil {
+		return m.GetBySlugFunc(ctx, slug)
+	}
+	return m.ArticleRepository.GetBySlug(ctx, slug)
+}
+
+func (m *ArticleRepository) GetByPublicID(ctx context.Context, publicID string) (db.Article, error) {
+	if m.GetByPublicIDFunc != nil {
+		return m.GetByPublicIDFunc(ctx, publicID)
+	}
+	return m.ArticleRepository.GetByPublicID(ctx, publicID)
+}
+
+func (m *ArticleRepository) SlugExists(ctx context.Context, slug string) (bool, error) {
+	if m.SlugExistsFunc != nil {
+		return m.SlugExistsFunc(ctx, slug)
+	}
+	return m.ArticleRepository.SlugExists(ctx, slug)
+}
+
+func (m *ArticleRepository) List(ctx context.Context) ([]db.Article, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+	return m.ArticleRepository.List(ctx)
+}
+
+func (m *ArticleRepository) ListPaginated(ctx context.Context, sort, status, tag string, categoryIDs []int64, publishedFrom, publishedTo *time.Time, limit int32, after *repository.ArticleCursor) ([]db.Article, error) {
+	if m.ListPaginatedFunc != nil {
+		return m.ListPaginatedFunc(ctx, sort, status, tag, categoryIDs, publishedFrom, publishedTo, limit, after)
+	}
+	return m.ArticleRepository.ListPaginated(ctx, sort, status, tag, categoryIDs, publishedFrom, publishedTo, limit, after)
+}
+
+func (m *ArticleRepository) Count(ctx context.Context, status, tag string, categoryIDs []int64, publishedFrom, publishedTo *time.Time, userID int64) (int64, error) {
+	if m.CountFunc != nil {
+		return m.CountFunc(ctx, status, tag, categoryIDs, publishedFrom, publishedTo, userID)
+	}
+	return m.ArticleRepository.Count(ctx, status, tag, categoryIDs, publishedFrom, publishedTo, userID)
+}
+
+func (m *ArticleRepository) ListForExport(ctx context.Context, status, tag string, userID, afterID int64, limit int32) ([]db.Article, error) {
+	if m.ListForExportFunc != nil {
+		return m.ListForExportFunc(ctx, status, tag, userID, afterID, limit)
+	}
+	return m.ArticleRepository.ListForExport(ctx, status, tag, userID, afterID, limit)
+}
+
+func (m *ArticleRepository) ArchiveMonths(ctx context.Context) ([]db.ListArchiveMonthsRow, error) {
+	if m.ArchiveMonthsFunc != nil {
+		return m.ArchiveMonthsFunc(ctx)
+	}
+	return m.ArticleRepository.ArchiveMonths(ctx)
+}
+
+func (m *ArticleRepository) CountByAuthor(ctx context.Context, status string, includeEmpty bool) ([]db.CountArticlesByAuthorRow, error) {
+	if m.CountByAuthorFunc != nil {
+		return m.CountByAuthorFunc(ctx, status, includeEmpty)
+	}
+	return m.ArticleRepository.CountByAuthor(ctx, status, includeEmpty)
+}
+
+func (m *ArticleRepository) Search(ctx context.Context, pattern string, limit int32) ([]db.Article, error) {
+	if m.SearchFunc != nil {
+		return m.SearchFunc(ctx, pattern, limit)
+	}
+	return m.ArticleRepository.Search(ctx, pattern, limit)
+}
+
+func (m *ArticleRepository) ListRelated(ctx context.Context, id int64, limit int32) ([]db.Article, error) {
+	if m.ListRelatedFunc != nil {
+		return m.ListRelatedFunc(ctx, id, limit)
+	}
+	return m.ArticleRepository.ListRelated(ctx, id, limit)
+}
+
+func (m *ArticleRepository) ListRecentByAuthor(ctx context.Context, userID, excludeID int64, limit int32) ([]db.Article, error) {
+	if m.ListRecentByAuthorFunc != nil {
+		return m.ListRecentByAuthorFunc(ctx, userID, excludeID, limit)
+	}
+	return m.ArticleRepository.ListRecentByAuthor(ctx, userID, excludeID, limit)
+}
+
+func (m *ArticleRepository) ListScheduled(ctx context.Context) ([]db.Article, error) {
+	if m.ListScheduledFunc != nil {
+		return m.ListScheduledFunc(ctx)
+	}
+	return m.ArticleRepository.ListScheduled(ctx)
+}
+
+func (m *ArticleRepository) PublishScheduled(ctx context.Context, id int64) (db.Article, error) {
+	if m.PublishScheduledFunc != nil {
+		return m.PublishScheduledFunc(ctx, id)
+	}
+	return m.ArticleRepository.PublishScheduled(ctx, id)
+}
+
+func (m *ArticleRepository) Publish(ctx context.Context, id int64) (db.Article, error) {
+	if m.PublishFunc != nil {
+		return m.PublishFunc(ctx, id)
+	}
+	return m.ArticleRepository.Publish(ctx, id)
+}
+
+func (m *ArticleRepository) Unpublish(ctx context.Context, id int64) (db.Article, error) {
+	if m.UnpublishFunc != nil {
+		return m.UnpublishFunc(ctx, id)
+	}
+	return m.ArticleRepository.Unpublish(ctx, id)
+}
+
+func (m *ArticleRepository) Transfer(ctx context.Context, id, newUserID int64) (db.Article, error) {
+	if m.TransferFunc != nil {
+		return m.TransferFunc(ctx, id, newUserID)
+	}
+	return m.ArticleRepository.Transfer(ctx, id, newUserID)
+}
+
+func (m *ArticleRepository) TransferAllByUser(ctx context.Context, userID, newUserID int64) (int64, error) {
+	if m.TransferAllByUserFunc != nil {
+		return m.TransferAllByUserFunc(ctx, userID, newUserID)
+	}
+	return m.ArticleRepository.TransferAllByUser(ctx, userID, newUserID)
+}
+
+func (m *ArticleRepository) Update(ctx context.Context, id, userID int64, title, content, status string, publishedAt *time.Time, featuredImageID, categoryID *int64, expectedVersion *int32) (db.Article, error) {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, id, userID, title, content, status, publishedAt, featuredImageID, categoryID, expectedVersion)
+	}
+	return m.ArticleRepository.Update(ctx, id, userID, title, content, status, publishedAt, featuredImageID, categoryID, expectedVersion)
+}
+
+func (m *ArticleRepository) IncrementViewCount(ctx context.Context, id int64) error {
+	if m.IncrementViewCountFunc != nil {
+		return m.IncrementViewCountFunc(ctx, id)
+	}
+	return m.ArticleRepository.IncrementViewCount(ctx, id)
+}
+
+func (m *ArticleRepository) Delete(ctx context.Context, id int64) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, id)
+	}
+	return m.ArticleRepository.Delete(ctx, id)
+}
+
+func (m *ArticleRepository) DeleteArticles(ctx context.Context, ids []int64) ([]int64, error) {
+	if m.DeleteArticlesFunc != nil {
+		return m.DeleteArticlesFunc(ctx, ids)
+	}
+	return m.ArticleRepository.DeleteArticles(ctx, ids)
+}
+
+func (m *ArticleRepository) Restore(ctx context.Context, id int64) (db.Article, error) {
+	if m.RestoreFunc != nil {
+		return m.RestoreFunc(ctx, id)
+	}
+	return m.ArticleRepository.Restore(ctx, id)
+}
+
+func (m *ArticleRepository) LockPinned(ctx context.Context) error {
+	if m.LockPinnedFunc != nil {
+		return m.LockPinnedFunc(ctx)
+	}
+	return m.ArticleRepository.LockPinned(ctx)
+}
+
+func (m *ArticleRepository) CountPinned(ctx context.Context) (int64, error) {
+	if m.CountPinnedFunc != nil {
+		return m.CountPinnedFunc(ctx)
+	}
+	return m.ArticleRepository.CountPinned(ctx)
+}
+
+func (m *ArticleRepository) SetPinned(ctx context.Context, id int64, pinned bool) (db.Article, error) {
+	if m.SetPinnedFunc != nil {
+		return m.SetPinnedFunc(ctx, id, pinned)
+	}
+	return m.ArticleRepository.SetPinned(ctx, id, pinned)
+}
+
+func (m *ArticleRepository) HardDelete(ctx context.Context, id int64) error {
+	if m.HardDeleteFunc != nil {
+		return m.HardDeleteFunc(ctx, id)
+	}
+	return m.ArticleRepository.HardDelete(ctx, id)
+}
+
+func (m *ArticleRepository) ListAll(ctx context.Context) ([]db.Article, error) {
+	if m.ListAllFunc != nil {
+		return m.ListAllFunc(ctx)
+	}
+	return m.ArticleRepository.ListAll(ctx)
+}
+
+func (m *ArticleRepository) ExistingIDs(ctx context.Context, ids []int64) ([]int64, error) {
+	if m.ExistingIDsFunc != nil {
+		return m.ExistingIDsFunc(ctx, ids)
+	}
+	return m.ArticleRepository.ExistingIDs(ctx, ids)
+}
+
+func (m *ArticleRepository) ExistingSlugs(ctx context.Context, slugs []string) ([]string, error) {
+	if m.ExistingSlugsFunc != nil {
+		return m.ExistingSlugsFunc(ctx, slugs)
+	}
+	return m.ArticleRepository.ExistingSlugs(ctx, slugs)
+}
+
+func (m *ArticleRepository) Import(ctx context.Context, article db.Article, preserveID bool) (db.Article, error) {
+	if m.ImportFunc != nil {
+		return m.ImportFunc(ctx, article, preserveID)
+	}
+	return m.ArticleRepository.Import(ctx, article, preserveID)
+}
+
+func (m *ArticleRepository) SyncIDSequence(ctx context.Context) error {
+	if m.SyncIDSequenceFunc != nil {
+		return m.SyncIDSequenceFunc(ctx)
+	}
+	return m.ArticleRepository.SyncIDSequence(ctx)
+}
+
+func (m *ArticleRepository) ListWithoutPublicID(ctx context.Context, limit int32) ([]int64, error) {
+	if m.ListWithoutPublicIDFunc != nil {
+		return m.ListWithoutPublicIDFunc(ctx, limit)
+	}
+	return m.ArticleRepository.ListWithoutPublicID(ctx, limit)
+}
+
+func (m *ArticleRepository) SetPublicID(ctx context.Context, id int64, publicID string) error {
+	if m.SetPublicIDFunc != nil {
+		return m.SetPublicIDFunc(ctx, id, publicID)
+	}
+	return m.ArticleRepository.SetPublicID(ctx, id, publicID)
+}
+
+// ArticleRevisionRepository is a repository.ArticleRevisionRepository whose methods call the function field of the same name
+// and fall back to the embedded repository.ArticleRevisionRepository when it is nil.
+type ArticleRevisionRepository struct {
+	repository.ArticleRevisionRepository
+
+	SnapshotFunc func(ctx context.Context, articleID int64) error
+	GetByIDFunc  func(ctx context.Context, articleID, id int64) (db.ArticleRevision, error)
+	ListFunc     func(ctx context.Context, articleID int64) ([]db.ArticleRevision, error)
+}
+
+func (m *ArticleRevisionRepository) Snapshot(ctx context.Context, articleID int64) error {
+	if m.SnapshotFunc != nil {
+		return m.SnapshotFunc(ctx, articleID)
+	}
+	return m.ArticleRevisionRepository.Snapshot(ctx, articleID)
+}
+
+func (m *ArticleRevisionRepository) GetByID(ctx context.Context, articleID, id int64) (db.ArticleRevision, error) {
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(ctx, articleID, id)
+	}
+	return m.ArticleRevisionRepository.GetByID(ctx, articleID, id)
+}
+
+func (m *ArticleRevisionRepository) List(ctx context.Context, articleID int64) ([]db.ArticleRevision, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, articleID)
+	}
+	return m.ArticleRevisionRepository.List(ctx, articleID)
+}
+
+// ArticleTranslationRepository is a repository.ArticleTranslationRepository whose methods call the function field of the same name
+// and fall back to the embedded repository.ArticleTranslationRepository when it is nil.
+type ArticleTranslationRepository struct {
+	repository.ArticleTranslationRepository
+
+	UpsertFunc         func(ctx context.Context, articleID int64, locale, title, content string) (db.ArticleTranslation, error)
+	ListByArticlesFunc func(ctx context.Context, articleIDs []int64, locales []string) (map[int64]map[string]db.ArticleTranslation, error)
+}
+
+func (m *ArticleTranslationRepository) Upsert(ctx context.Context, articleID int64, locale, title, content string) (db.ArticleTranslation, error) {
+	if m.UpsertFunc != nil {
+		return m.UpsertFunc(ctx, articleID, locale, title, content)
+	}
+	return m.ArticleTranslationRepository.Upsert(ctx, articleID, locale, title, content)
+}
+
+func (m *ArticleTranslationRepository) ListByArticles(ctx context.Context, articleIDs []int64, locales []string) (map[int64]map[string]db.ArticleTranslation, error) {
+	if m.ListByArticlesFunc != nil {
+		return m.ListByArticlesFunc(ctx, articleIDs, locales)
+	}
+	return m.ArticleTranslationRepository.ListByArticles(ctx, articleIDs, locales)
+}
+
+// AuthRepository is a repository.AuthRepository whose methods call the function field of the same name
+// and fall back to the embedded repository.AuthRepository when it is nil.
+type AuthRepository struct {
+	repository.AuthRepository
+
+	CreateTokenFunc      func(ctx context.Context, userID int64, tokenHash string, expiresAt pgtype.Timestamp) (db.AccessToken, error)
+	GetUserByTokenFunc   func(ctx context.Context, tokenHash string) (db.User, error)
+	GetTokenFunc         func(ctx context.Context, tokenHash string) (db.AccessToken, error)
+	RefreshTokenFunc     func(ctx context.Context, oldTokenHash, newTokenHash string, expiresAt pgtype.Timestamp) (db.AccessToken, error)
+	ListTokensByUserFunc func(ctx context.Context, userID int64) ([]db.AccessToken, error)
+	DeleteTokenByIDFunc  func(ctx context.Context, userID, tokenID int64) error
+	TouchTokenFunc       func(ctx context.Context, tokenHash string) error
+	GetUserByIDFunc      func(ctx context.Context, id int64) (db.User, error)
+	GetUserByEmailFunc   func(ctx context.Context, email string) (db.User, error)
+	SetPasswordHashFunc  func(ctx context.Context, userID int64, passwordHash *string) error
+	SetEmailFunc         func(ctx context.Context, userID int64, email string) (db.User, error)
+}
+
+func (m *AuthRepository) CreateToken(ctx context.Context, userID int64, tokenHash string, expiresAt pgtype.Timestamp) (db.AccessToken, error) {
+	if m.CreateTokenFunc != nil {
+		return m.CreateTokenFunc(ctx, userID, tokenHash, expiresAt)
+	}
+	return m.AuthRepository.CreateToken(ctx, userID, tokenHash, expiresAt)
+}
+
+func (m *AuthRepository) GetUserByToken(ctx context.Context, tokenHash string) (db.User, error) {
+	if m.GetUserByTokenFunc != nil {
+		return m.GetUserByTokenFunc(ctx, tokenHash)
+	}
+	return m.AuthRepository.GetUserByToken(ctx, tokenHash)
+}
+
+func (m *AuthRepository) GetToken(ctx context.Context, tokenHash string) (db.AccessToken, error) {
+	if m.GetTokenFunc != nil {
+		return m.GetTokenFunc(ctx, tokenHash)
+	}
+	return m.AuthRepository.GetToken(ctx, tokenHash)
+}
+
+func (m *AuthRepository) RefreshToken(ctx context.Context, oldTokenHash, newTokenHash string, expiresAt pgtype.Timestamp) (db.AccessToken, error) {
+	if m.RefreshTokenFunc != nil {
+		return m.RefreshTokenFunc(ctx, oldTokenHash, newTokenHash, expiresAt)
+	}
+	return m.AuthRepository.RefreshToken(ctx, oldTokenHash, newTokenHash, expiresAt)
+}
+
+func (m *AuthRepository) ListTokensByUser(ctx context.Context, userID int64) ([]db.AccessToken, error) {
+	if m.ListTokensByUserFunc != nil {
+		return m.ListTokensByUserFunc(ctx, userID)
+	}
+	return m.AuthRepository.ListTokensByUser(ctx, userID)
+}
+
+func (m *AuthRepository) DeleteTokenByID(ctx context.Context, userID, tokenID int64) error {
+	if m.DeleteTokenByIDFunc != nil {
+		return m.DeleteTokenByIDFunc(ctx, userID, tokenID)
+	}
+	return m.AuthRepository.DeleteTokenByID(ctx, userID, tokenID)
+}
+
+func (m *AuthRepository) TouchToken(ctx context.Context, tokenHash string) error {
+	if m.TouchTokenFunc != nil {
+		return m.TouchTokenFunc(ctx, tokenHash)
+	}
+	return m.AuthRepository.TouchToken(ctx, tokenHash)
+}
+
+func (m *AuthRepository) GetUserByID(ctx context.Context, id int64) (db.User, error) {
+	if m.GetUserByIDFunc != nil {
+		return m.GetUserByIDFunc(ctx, id)
+	}
+	return m.AuthRepository.GetUserByID(ctx, id)
+}
+
+func (m *AuthRepository) GetUserByEmail(ctx context.Context, email string) (db.User, error) {
+	if m.GetUserByEmailFunc != nil {
+		return m.GetUserByEmailFunc(ctx, email)
+	}
+	return m.AuthRepository.GetUserByEmail(ctx, email)
+}
+
+func (m *AuthRepository) SetPasswordHash(ctx context.Context, userID int64, passwordHash *string) error {
+	if m.SetPasswordHashFunc != nil {
+		return m.SetPasswordHashFunc(ctx, userID, passwordHash)
+	}
+	return m.AuthRepository.SetPasswordHash(ctx, userID, passwordHash)
+}
+
+func (m *AuthRepository) SetEmail(ctx context.Context, userID int64, email string) (db.User, error) {
+	if m.SetEmailFunc != nil {
+		return m.SetEmailFunc(ctx, userID, email)
+	}
+	return m.AuthRepository.SetEmail(ctx, userID, email)
+}
+
+// CategoryRepository is a repository.CategoryRepository whose methods call the function field of the same name
+// and fall back to the embedded repository.CategoryRepository when it is nil.
+type CategoryRepository struct {
+	repository.CategoryRepository
+
+	CreateFunc  func(ctx context.Context, name, slug string, parentID *int64) (db.Category, error)
+	GetByIDFunc func(ctx context.Context, id int64) (db.Category, error)
+	ListFunc    func(ctx context.Context) ([]db.Category, error)
+	UpdateFunc  func(ctx context.Context, id int64, name, slug string, parentID *int64) (db.Category, error)
+	DeleteFunc  func(ctx context.Context, id int64) error
+}
+
+func (m *CategoryRepository) Create(ctx context.Context, name, slug string, parentID *int64) (db.Category, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, name, slug, parentID)
+	}
+	return m.CategoryRepository.Create(ctx, name, slug, parentID)
+}
+
+func (m *CategoryRepository) GetByID(ctx context.Context, id int64) (db.Category, error) {
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(ctx, id)
+	}
+	return m.CategoryRepository.GetByID(ctx, id)
+}
+
+func (m *CategoryRepository) List(ctx context.Context) ([]db.Category, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+	return m.CategoryRepository.List(ctx)
+}
+
+func (m *CategoryRepository) Update(ctx context.Context, id int64, name, slug string, parentID *int64) (db.Category, error) {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, id, name, slug, parentID)
+	}
+	return m.CategoryRepository.Update(ctx, id, name, slug, parentID)
+}
+
+func (m *CategoryRepository) Delete(ctx context.Context, id int64) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, id)
+	}
+	return m.CategoryRepository.Delete(ctx, id)
+}
+
+// CommentRepository is a repository.CommentRepository whose methods call the function field of the same name
+// and fall back to the embedded repository.CommentRepository when it is nil.
+type CommentRepository struct {
+	repository.CommentRepository
+
+	CreateFunc        func(ctx context.Context, articleID int64, userID *int64, authorName *string, content string) (db.Comment, error)
+	ListByArticleFunc func(ctx context.Context, articleID int64, limit int32, cursor int64) ([]db.Comment, error)
+}
+
+func (m *CommentRepository) Create(ctx context.Context, articleID int64, userID *int64, authorName *string, content string) (db.Comment, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, articleID, userID, authorName, content)
+	}
+	return m.CommentRepository.Create(ctx, articleID, userID, authorName, content)
+}
+
+func (m *CommentRepository) ListByArticle(ctx context.Context, articleID int64, limit int32, cursor int64) ([]db.Comment, error) {
+	if m.ListByArticleFunc != nil {
+		return m.ListByArticleFunc(ctx, articleID, limit, cursor)
+	}
+	return m.CommentRepository.ListByArticle(ctx, articleID, limit, cursor)
+}
+
+// IdempotencyRepository is a repository.IdempotencyRepository whose methods call the function field of the same name
+// and fall back to the embedded repository.IdempotencyRepository when it is nil.
+type IdempotencyRepository struct {
+	repository.IdempotencyRepository
+
+	ClaimFunc         func(ctx context.Context, key string) (bool, error)
+	GetFunc           func(ctx context.Context, key string) (db.IdempotencyKey, error)
+	CompleteFunc      func(ctx context.Context, key string, articleID int64) error
+	ReleaseFunc       func(ctx context.Context, key string) error
+	DeleteExpiredFunc func(ctx context.Context) (int64, error)
+}
+
+func (m *IdempotencyRepository) Claim(ctx context.Context, key string) (bool, error) {
+	if m.ClaimFunc != nil {
+		return m.ClaimFunc(ctx, key)
+	}
+	return m.IdempotencyRepository.Claim(ctx, key)
+}
+
+func (m *IdempotencyRepository) Get(ctx context.Context, key string) (db.IdempotencyKey, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, key)
+	}
+	return m.IdempotencyRepository.Get(ctx, key)
+}
+
+func (m *IdempotencyRepository) Complete(ctx context.Context, key string, articleID int64) error {
+	if m.CompleteFunc != nil {
+		return m.CompleteFunc(ctx, key, articleID)
+	}
+	return m.IdempotencyRepository.Complete(ctx, key, articleID)
+}
+
+func (m *IdempotencyRepository) Release(ctx context.Context, key string) error {
+	if m.ReleaseFunc != nil {
+		return m.ReleaseFunc(ctx, key)
+	}
+	return m.IdempotencyRepository.Release(ctx, key)
+}
+
+func (m *IdempotencyRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	if m.DeleteExpiredFunc != nil {
+		return m.DeleteExpiredFunc(ctx)
+	}
+	return m.IdempotencyRepository.DeleteExpired(ctx)
+}
+
+// LoginLinkRepository is a repository.LoginLinkRepository whose methods call the function field of the same name
+// and fall back to the embedded repository.LoginLinkRepository when it is nil.
+type LoginLinkRepository struct {
+	repository.LoginLinkRepository
+
+	CreateFunc             func(ctx context.Context, userID int64, tokenHash string, email *string, expiresAt pgtype.Timestamp) error
+	ConsumeFunc            func(ctx context.Context, tokenHash string) (int64, error)
+	ConsumeEmailChangeFunc func(ctx context.Context, tokenHash string) (int64, string, error)
+	DeleteExpiredFunc      func(ctx context.Context) (int64, error)
+}
+
+func (m *LoginLinkRepository) Create(ctx context.Context, userID int64, tokenHash string, email *string, expiresAt pgtype.Timestamp) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, userID, tokenHash, email, expiresAt)
+	}
+	return m.LoginLinkRepository.Create(ctx, userID, tokenHash, email, expiresAt)
+}
+
+func (m *LoginLinkRepository) Consume(ctx context.Context, tokenHash string) (int64, error) {
+	if m.ConsumeFunc != nil {
+		return m.ConsumeFunc(ctx, tokenHash)
+	}
+	return m.LoginLinkRepository.Consume(ctx, tokenHash)
+}
+
+func (m *LoginLinkRepository) ConsumeEmailChange(ctx context.Context, tokenHash string) (int64, string, error) {
+	if m.ConsumeEmailChangeFunc != nil {
+		return m.ConsumeEmailChangeFunc(ctx, tokenHash)
+	}
+	return m.LoginLinkRepository.ConsumeEmailChange(ctx, tokenHash)
+}
+
+func (m *LoginLinkRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	if m.DeleteExpiredFunc != nil {
+		return m.DeleteExpiredFunc(ctx)
+	}
+	return m.LoginLinkRepository.DeleteExpired(ctx)
+}
+
+// MediaRepository is a repository.MediaRepository whose methods call the function field of the same name
+// and fall back to the embedded repository.MediaRepository when it is nil.
+type MediaRepository struct {
+	repository.MediaRepository
+
+	CreateFunc    func(ctx context.Context, userID *int64, filename, objectKey, contentType string, size int64) (db.MediaFile, error)
+	GetByIDFunc   func(ctx context.Context, id int64) (db.MediaFile, error)
+	ListFunc      func(ctx context.Context, limit int32, cursor int64) ([]db.MediaFile, error)
+	ListByIDsFunc func(ctx context.Context, ids []int64) ([]db.MediaFile, error)
+	DeleteFunc    func(ctx context.Context, id int64) error
+}
+
+func (m *MediaRepository) Create(ctx context.Context, userID *int64, filename, objectKey, contentType string, size int64) (db.MediaFile, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, userID, filename, objectKey, contentType, size)
+	}
+	return m.MediaRepository.Create(ctx, userID, filename, objectKey, contentType, size)
+}
+
+func (m *MediaRepository) GetByID(ctx context.Context, id int64) (db.MediaFile, error) {
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(ctx, id)
+	}
+	return m.MediaRepository.GetByID(ctx, id)
+}
+
+func (m *MediaRepository) List(ctx context.Context, limit int32, cursor int64) ([]db.MediaFile, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, limit, cursor)
+	}
+	return m.MediaRepository.List(ctx, limit, cursor)
+}
+
+func (m *MediaRepository) ListByIDs(ctx context.Context, ids []int64) ([]db.MediaFile, error) {
+	if m.ListByIDsFunc != nil {
+		return m.ListByIDsFunc(ctx, ids)
+	}
+	return m.MediaRepository.ListByIDs(ctx, ids)
+}
+
+func (m *MediaRepository) Delete(ctx context.Context, id int64) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, id)
+	}
+	return m.MediaRepository.Delete(ctx, id)
+}
+
+// QuotaRepository is a repository.QuotaRepository whose methods call the function field of the same name
+// and fall back to the embedded repository.QuotaRepository when it is nil.
+type QuotaRepository struct {
+	repository.QuotaRepository
+
+	ConsumeFunc      func(ctx context.Context, userID int64, day time.Time, limit int32) (used int32, ok bool, err error)
+	DeleteBeforeFunc func(ctx context.Context, day time.Time) (int64, error)
+}
+
+func (m *QuotaRepository) Consume(ctx context.Context, userID int64, day time.Time, limit int32) (used int32, ok bool, err error) {
+	if m.ConsumeFunc != nil {
+		return m.ConsumeFunc(ctx, userID, day, limit)
+	}
+	return m.QuotaRepository.Consume(ctx, userID, day, limit)
+}
+
+func (m *QuotaRepository) DeleteBefore(ctx context.Context, day time.Time) (int64, error) {
+	if m.DeleteBeforeFunc != nil {
+		return m.DeleteBeforeFunc(ctx, day)
+	}
+	return m.QuotaRepository.DeleteBefore(ctx, day)
+}
+
+// SettingRepository is a repository.SettingRepository whose methods call the function field of the same name
+// and fall back to the embedded repository.SettingRepository when it is nil.
+type SettingRepository struct {
+	repository.SettingRepository
+
+	GetFunc func(ctx context.Context, key string) (string, error)
+	SetFunc func(ctx context.Context, key, value string) error
+}
+
+func (m *SettingRepository) Get(ctx context.Context, key string) (string, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, key)
+	}
+	return m.SettingRepository.Get(ctx, key)
+}
+
+func (m *SettingRepository) Set(ctx context.Context, key, value string) error {
+	if m.SetFunc != nil {
+		return m.SetFunc(ctx, key, value)
+	}
+	return m.SettingRepository.Set(ctx, key, value)
+}
+
+// TagRepository is a repository.TagRepository whose methods call the function field of the same name
+// and fall back to the embedded repository.TagRepository when it is nil.
+type TagRepository struct {
+	repository.TagRepository
+
+	UpsertFunc              func(ctx context.Context, name string) (db.Tag, error)
+	AttachFunc              func(ctx context.Context, articleID, tagID int64) error
+	DetachExceptFunc        func(ctx context.Context, articleID int64, keepTagIDs []int64) error
+	ListByArticleFunc       func(ctx context.Context, articleID int64) ([]db.Tag, error)
+	ListNamesByArticlesFunc func(ctx context.Context, articleIDs []int64) (map[int64][]string, error)
+}
+
+func (m *TagRepository) Upsert(ctx context.Context, name string) (db.Tag, error) {
+	if m.UpsertFunc != nil {
+		return m.UpsertFunc(ctx, name)
+	}
+	return m.TagRepository.Upsert(ctx, name)
+}
+
+func (m *TagRepository) Attach(ctx context.Context, articleID, tagID int64) error {
+	if m.AttachFunc != nil {
+		return m.AttachFunc(ctx, articleID, tagID)
+	}
+	return m.TagRepository.Attach(ctx, articleID, tagID)
+}
+
+func (m *TagRepository) DetachExcept(ctx context.Context, articleID int64, keepTagIDs []int64) error {
+	if m.DetachExceptFunc != nil {
+		return m.DetachExceptFunc(ctx, articleID, keepTagIDs)
+	}
+	return m.TagRepository.DetachExcept(ctx, articleID, keepTagIDs)
+}
+
+func (m *TagRepository) ListByArticle(ctx context.Context, articleID int64) ([]db.Tag, error) {
+	if m.ListByArticleFunc != nil {
+		return m.ListByArticleFunc(ctx, articleID)
+	}
+	return m.TagRepository.ListByArticle(ctx, articleID)
+}
+
+func (m *TagRepository) ListNamesByArticles(ctx context.Context, articleIDs []int64) (map[int64][]string, error) {
+	if m.ListNamesByArticlesFunc != nil {
+		return m.ListNamesByArticlesFunc(ctx, articleIDs)
+	}
+	return m.TagRepository.ListNamesByArticles(ctx, articleIDs)
+}
+
+// Transactor is a repository.Transactor whose methods call the function field of the same name
+// and fall back to the embedded repository.Transactor when it is nil.
+type Transactor struct {
+	repository.Transactor
+
+	WithTxFunc func(ctx context.Context, fn func(tx repository.Tx) error) error
+}
+
+func (m *Transactor) WithTx(ctx context.Context, fn func(tx repository.Tx) error) error {
+	if m.WithTxFunc != nil {
+		return m.WithTxFunc(ctx, fn)
+	}
+	return m.Transactor.WithTx(ctx, fn)
+}
+
+// UserRepository is a repository.UserRepository whose methods call the function field of the same name
+// and fall back to the embedded repository.UserRepository when it is nil.
+type UserRepository struct {
+	repository.UserRepository
+
+	CreateFunc         func(ctx context.Context, email, name, role string) (db.User, error)
+	GetByIDFunc        func(ctx context.Context, id int64) (db.User, error)
+	GetByEmailFunc     func(ctx context.Context, email string) (db.User, error)
+	ListFunc           func(ctx context.Context) ([]db.User, error)
+	ListByIDsFunc      func(ctx context.Context, ids []int64) ([]db.User, error)
+	ListPaginatedFunc  func(ctx context.Context, limit, offset int32) ([]db.User, error)
+	CountFunc          func(ctx context.Context) (int64, error)
+	SearchFunc         func(ctx context.Context, pattern string, limit, offset int32) ([]db.User, error)
+	CountSearchFunc    func(ctx context.Context, pattern string) (int64, error)
+	UpdateFunc         func(ctx context.Context, id int64, email, name string) (db.User, error)
+	UpsertByEmailFunc  func(ctx context.Context, email, name, role string) (db.User, bool, error)
+	DeleteFunc         func(ctx context.Context, id int64) error
+	RestoreFunc        func(ctx context.Context, id int64) (db.User, error)
+	ListAllFunc        func(ctx context.Context) ([]db.User, error)
+	ExistingEmailsFunc func(ctx context.Context, emails []string) ([]string, error)
+	ImportFunc         func(ctx context.Context, user db.User, preserveID bool) (db.User, error)
+	SyncIDSequenceFunc func(ctx context.Context) error
+}
+
+func (m *UserRepository) Create(ctx context.Context, email, name, role string) (db.User, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, email, name, role)
+	}
+	return m.UserRepository.Create(ctx, email, name, role)
+}
+
+func (m *UserRepository) GetByID(ctx context.Context, id int64) (db.User, error) {
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(ctx, id)
+	}
+	return m.UserRepository.GetByID(ctx, id)
+}
+
+func (m *UserRepository) GetByEmail(ctx context.Context, email string) (db.User, error) {
+	if m.GetByEmailFunc != nil {
+		return m.GetByEmailFunc(ctx, email)
+	}
+	return m.UserRepository.GetByEmail(ctx, email)
+}
+
+func (m *UserRepository) List(ctx context.Context) ([]db.User, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+	return m.UserRepository.List(ctx)
+}
+
+func (m *UserRepository) ListByIDs(ctx context.Context, ids []int64) ([]db.User, error) {
+	if m.ListByIDsFunc != nil {
+		return m.ListByIDsFunc(ctx, ids)
+	}
+	return m.UserRepository.ListByIDs(ctx, ids)
+}
+
+func (m *UserRepository) ListPaginated(ctx context.Context, limit, offset int32) ([]db.User, error) {
+	if m.ListPaginatedFunc != nil {
+		return m.ListPaginatedFunc(ctx, limit, offset)
+	}
+	return m.UserRepository.ListPaginated(ctx, limit, offset)
+}
+
+func (m *UserRepository) Count(ctx context.Context) (int64, error) {
+	if m.CountFunc != nil {
+		return m.CountFunc(ctx)
+	}
+	return m.UserRepository.Count(ctx)
+}
+
+func (m *UserRepository) Search(ctx context.Context, pattern string, limit, offset int32) ([]db.User, error) {
+	if m.SearchFunc != nil {
+		return m.SearchFunc(ctx, pattern, limit, offset)
+	}
+	return m.UserRepository.Search(ctx, pattern, limit, offset)
+}
+
+func (m *UserRepository) CountSearch(ctx context.Context, pattern string) (int64, error) {
+	if m.CountSearchFunc != nil {
+		return m.CountSearchFunc(ctx, pattern)
+	}
+	return m.UserRepository.CountSearch(ctx, pattern)
+}
+
+func (m *UserRepository) Update(ctx context.Context, id int64, email, name string) (db.User, error) {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, id, email, name)
+	}
+	return m.UserRepository.Update(ctx, id, email, name)
+}
+
+func (m *UserRepository) UpsertByEmail(ctx context.Context, email, name, role string) (db.User, bool, error) {
+	if m.UpsertByEmailFunc != nil {
+		return m.UpsertByEmailFunc(ctx, email, name, role)
+	}
+	return m.UserRepository.UpsertByEmail(ctx, email, name, role)
+}
+
+func (m *UserRepository) Delete(ctx context.Context, id int64) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, id)
+	}
+	return m.UserRepository.Delete(ctx, id)
+}
+
+func (m *UserRepository) Restore(ctx context.Context, id int64) (db.User, error) {
+	if m.RestoreFunc != nil {
+		return m.RestoreFunc(ctx, id)
+	}
+	return m.UserRepository.Restore(ctx, id)
+}
+
+func (m *UserRepository) ListAll(ctx context.Context) ([]db.User, error) {
+	if m.ListAllFunc != nil {
+		return m.ListAllFunc(ctx)
+	}
+	return m.UserRepository.ListAll(ctx)
+}
+
+func (m *UserRepository) ExistingEmails(ctx context.Context, emails []string) ([]string, error) {
+	if m.ExistingEmailsFunc != nil {
+		return m.ExistingEmailsFunc(ctx, emails)
+	}
+	return m.UserRepository.ExistingEmails(ctx, emails)
+}
+
+func (m *UserRepository) Import(ctx context.Context, user db.User, preserveID bool) (db.User, error) {
+	if m.ImportFunc != nil {
+		return m.ImportFunc(ctx, user, preserveID)
+	}
+	return m.UserRepository.Import(ctx, user, preserveID)
+}
+
+func (m *UserRepository) SyncIDSequence(ctx context.Context) error {
+	if m.SyncIDSequenceFunc != nil {
+		return m.SyncIDSequenceFunc(ctx)
+	}
+	return m.UserRepository.SyncIDSequence(ctx)
+}
+
+// WebhookRepository is a repository.WebhookRepository whose methods call the function field of the same name
+// and fall back to the embedded repository.WebhookRepository when it is nil.
+type WebhookRepository struct {
+	repository.WebhookRepository
+
+	CreateFunc      func(ctx context.Context, url, secret string, events []string) (db.Webhook, error)
+	GetByIDFunc     func(ctx context.Context, id int64) (db.Webhook, error)
+	ListFunc        func(ctx context.Context) ([]db.Webhook, error)
+	ListByEventFunc func(ctx context.Context, event string) ([]db.Webhook, error)
+	UpdateFunc      func(ctx context.Context, id int64, url string, secret *string, events []string) (db.Webhook, error)
+	DeleteFunc      func(ctx context.Context, id int64) error
+}
+
+func (m *WebhookRepository) Create(ctx context.Context, url, secret string, events []string) (db.Webhook, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, url, secret, events)
+	}
+	return m.WebhookRepository.Create(ctx, url, secret, events)
+}
+
+func (m *WebhookRepository) GetByID(ctx context.Context, id int64) (db.Webhook, error) {
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(ctx, id)
+	}
+	return m.WebhookRepository.GetByID(ctx, id)
+}
+
+func (m *WebhookRepository) List(ctx context.Context) ([]db.Webhook, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+	return m.WebhookRepository.List(ctx)
+}
+
+func (m *WebhookRepository) ListByEvent(ctx context.Context, event string) ([]db.Webhook, error) {
+	if m.ListByEventFunc != nil {
+		return m.ListByEventFunc(ctx, event)
+	}
+	return m.WebhookRepository.ListByEvent(ctx, event)
+}
+
+func (m *WebhookRepository) Update(ctx context.Context, id int64, url string, secret *string, events []string) (db.Webhook, error) {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, id, url, secret, events)
+	}
+	return m.WebhookRepository.Update(ctx, id, url, secret, events)
+}
+
+func (m *WebhookRepository) Delete(ctx context.Context, id int64) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, id)
+	}
+	return m.WebhookRepository.Delete(ctx, id)
+}
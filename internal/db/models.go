@@ -9,21 +9,64 @@ import (
 )
 
 type AccessToken struct {
+	ID         int64            `json:"id"`
+	UserID     int64            `json:"user_id"`
+	Token      string           `json:"token"`
+	ExpiresAt  pgtype.Timestamp `json:"expires_at"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+	LastUsedAt pgtype.Timestamp `json:"last_used_at"`
+}
+
+type Article struct {
+	ID              int64            `json:"id"`
+	UserID          int64            `json:"user_id"`
+	Title           string           `json:"title"`
+	Content         string           `json:"content"`
+	PublishedAt     pgtype.Timestamp `json:"published_at"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	UpdatedAt       pgtype.Timestamp `json:"updated_at"`
+	Status          string           `json:"status"`
+	Slug            *string          `json:"slug"`
+	DeletedAt       pgtype.Timestamp `json:"deleted_at"`
+	ViewCount       int64            `json:"view_count"`
+	FeaturedImageID *int64           `json:"featured_image_id"`
+	Version         int32            `json:"version"`
+	CategoryID      *int64           `json:"category_id"`
+	IsPinned        bool             `json:"is_pinned"`
+	PublicID        *string          `json:"public_id"`
+}
+
+type ArticleRevision struct {
 	ID        int64            `json:"id"`
-	UserID    int64            `json:"user_id"`
-	Token     string           `json:"token"`
-	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+	ArticleID int64            `json:"article_id"`
+	Version   int32            `json:"version"`
+	Title     string           `json:"title"`
+	Content   string           `json:"content"`
 	CreatedAt pgtype.Timestamp `json:"created_at"`
 }
 
-type Article struct {
-	ID          int64            `json:"id"`
-	UserID      int64            `json:"user_id"`
-	Title       string           `json:"title"`
-	Content     string           `json:"content"`
-	PublishedAt pgtype.Timestamp `json:"published_at"`
-	CreatedAt   pgtype.Timestamp `json:"created_at"`
-	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+type ArticleTag struct {
+	ArticleID int64            `json:"article_id"`
+	TagID     int64            `json:"tag_id"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type ArticleTranslation struct {
+	ArticleID int64            `json:"article_id"`
+	Locale    string           `json:"locale"`
+	Title     string           `json:"title"`
+	Content   string           `json:"content"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
+type Category struct {
+	ID        int64            `json:"id"`
+	Name      string           `json:"name"`
+	Slug      string           `json:"slug"`
+	ParentID  *int64           `json:"parent_id"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
 }
 
 type Comment struct {
@@ -36,10 +79,65 @@ type Comment struct {
 	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
 }
 
-type User struct {
+type IdempotencyKey struct {
+	Key       string           `json:"key"`
+	ArticleID *int64           `json:"article_id"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type LoginLink struct {
+	ID        int64            `json:"id"`
+	UserID    int64            `json:"user_id"`
+	Token     string           `json:"token"`
+	Email     *string          `json:"email"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type MediaFile struct {
+	ID          int64            `json:"id"`
+	UserID      *int64           `json:"user_id"`
+	Filename    string           `json:"filename"`
+	ObjectKey   string           `json:"object_key"`
+	ContentType string           `json:"content_type"`
+	Size        int64            `json:"size"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+}
+
+type Setting struct {
+	Key       string           `json:"key"`
+	Value     string           `json:"value"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
+type Tag struct {
 	ID        int64            `json:"id"`
 	Name      string           `json:"name"`
-	Email     string           `json:"email"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type User struct {
+	ID           int64            `json:"id"`
+	Name         string           `json:"name"`
+	Email        string           `json:"email"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+	Role         string           `json:"role"`
+	DeletedAt    pgtype.Timestamp `json:"deleted_at"`
+	PasswordHash *string          `json:"-"`
+}
+
+type UserQuotaUsage struct {
+	UserID int64       `json:"user_id"`
+	Day    pgtype.Date `json:"day"`
+	Count  int32       `json:"count"`
+}
+
+type Webhook struct {
+	ID        int64            `json:"id"`
+	Url       string           `json:"url"`
+	Secret    string           `json:"-"`
+	Events    []string         `json:"events"`
 	CreatedAt pgtype.Timestamp `json:"created_at"`
 	UpdatedAt pgtype.Timestamp `json:"updated_at"`
 }
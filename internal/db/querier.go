@@ -6,24 +6,162 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
+	ArticleSlugExists(ctx context.Context, slug *string) (bool, error)
+	AttachTag(ctx context.Context, arg AttachTagParams) error
+	// 未使用のキーに加え、期限切れ（24時間）のキーと放棄された処理中（1分）のキーを確保し直せる
+	ClaimIdempotencyKey(ctx context.Context, key string) (int64, error)
+	CompleteIdempotencyKey(ctx context.Context, arg CompleteIdempotencyKeyParams) error
+	// ConsumeLoginLink と同じく、削除できた1件の呼び出しだけが新しいメールアドレスを受け取る
+	ConsumeEmailChangeLink(ctx context.Context, token string) (ConsumeEmailChangeLinkRow, error)
+	// 削除できた1件の呼び出しだけがユーザーIDを受け取るため、同時に使われても一度しか成功しない
+	ConsumeLoginLink(ctx context.Context, token string) (int64, error)
+	// 上限未満のときだけ当日の件数を1増やして返す（上限に達していれば行を返さない）
+	ConsumeUserQuota(ctx context.Context, arg ConsumeUserQuotaParams) (int32, error)
+	CountArticles(ctx context.Context, arg CountArticlesParams) (int64, error)
+	// 有効なユーザーごとの記事数を多い順に集計する（記事のないユーザーは include_empty のときのみ含める）
+	CountArticlesByAuthor(ctx context.Context, arg CountArticlesByAuthorParams) ([]CountArticlesByAuthorRow, error)
+	CountPinnedArticles(ctx context.Context) (int64, error)
+	CountSearchUsers(ctx context.Context, pattern string) (int64, error)
+	CountUsers(ctx context.Context) (int64, error)
 	CreateAccessToken(ctx context.Context, arg CreateAccessTokenParams) (AccessToken, error)
 	CreateArticle(ctx context.Context, arg CreateArticleParams) (Article, error)
+	// 更新前の記事を保存する。行ロックを取るため、同じトランザクション内の更新と食い違わない
+	CreateArticleRevision(ctx context.Context, id int64) (int64, error)
+	CreateCategory(ctx context.Context, arg CreateCategoryParams) (Category, error)
+	CreateComment(ctx context.Context, arg CreateCommentParams) (Comment, error)
+	// email を指定するとメールアドレス変更の確認リンクになる
+	CreateLoginLink(ctx context.Context, arg CreateLoginLinkParams) error
+	CreateMediaFile(ctx context.Context, arg CreateMediaFileParams) (MediaFile, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateWebhook(ctx context.Context, arg CreateWebhookParams) (Webhook, error)
 	DeleteAccessToken(ctx context.Context, token string) error
-	DeleteArticle(ctx context.Context, id int64) error
-	DeleteUser(ctx context.Context, id int64) error
+	DeleteAccessTokenByID(ctx context.Context, arg DeleteAccessTokenByIDParams) (int64, error)
+	DeleteAccessTokensByUser(ctx context.Context, userID int64) error
+	DeleteCategory(ctx context.Context, id int64) (int64, error)
+	DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error)
+	DeleteExpiredLoginLinks(ctx context.Context) (int64, error)
+	DeleteMediaFile(ctx context.Context, id int64) (int64, error)
+	DeleteUserQuotaUsageBefore(ctx context.Context, day pgtype.Date) (int64, error)
+	DeleteWebhook(ctx context.Context, id int64) (int64, error)
+	DetachTagsExcept(ctx context.Context, arg DetachTagsExceptParams) error
+	GetAccessToken(ctx context.Context, token string) (AccessToken, error)
 	GetArticle(ctx context.Context, id int64) (Article, error)
+	GetArticleByPublicID(ctx context.Context, publicID *string) (Article, error)
+	GetArticleBySlug(ctx context.Context, slug *string) (Article, error)
+	GetArticleRevision(ctx context.Context, arg GetArticleRevisionParams) (ArticleRevision, error)
+	GetCategory(ctx context.Context, id int64) (Category, error)
+	GetIdempotencyKey(ctx context.Context, key string) (IdempotencyKey, error)
+	GetMediaFile(ctx context.Context, id int64) (MediaFile, error)
+	GetSetting(ctx context.Context, key string) (string, error)
 	GetUser(ctx context.Context, id int64) (User, error)
+	// 大文字小文字を区別せずに検索する（論理削除済みのユーザーも含む）
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByToken(ctx context.Context, token string) (User, error)
+	GetWebhook(ctx context.Context, id int64) (Webhook, error)
+	HardDeleteArticle(ctx context.Context, id int64) (int64, error)
+	// バックアップからの取り込み用。id が NULL なら採番し、日時や閲覧数はバックアップの値を使う
+	ImportArticle(ctx context.Context, arg ImportArticleParams) (Article, error)
+	// バックアップからの取り込み用。id が NULL なら採番し、日時はバックアップの値を使う
+	ImportUser(ctx context.Context, arg ImportUserParams) (User, error)
+	IncrementArticleViewCount(ctx context.Context, id int64) error
+	ListAccessTokensByUser(ctx context.Context, userID int64) ([]AccessToken, error)
+	// 論理削除済みの記事も含む（バックアップ用）
+	ListAllArticles(ctx context.Context) ([]Article, error)
+	// 論理削除済みのユーザーも含む（バックアップ用）
+	ListAllUsers(ctx context.Context) ([]User, error)
+	// 公開済み記事の件数を公開年月ごとに集計する（新しい月から）
+	ListArchiveMonths(ctx context.Context) ([]ListArchiveMonthsRow, error)
+	ListArticleRevisions(ctx context.Context, articleID int64) ([]ArticleRevision, error)
+	// 一覧表示用。記事ごとにどの言語を使うかはアプリケーション側で希望順に選ぶ
+	ListArticleTranslations(ctx context.Context, arg ListArticleTranslationsParams) ([]ArticleTranslation, error)
 	ListArticles(ctx context.Context) ([]Article, error)
+	// ListArticlesByID と同じく (ピン留め, 作成日時, ID) のキーセットページング
+	ListArticlesByCreatedAt(ctx context.Context, arg ListArticlesByCreatedAtParams) ([]Article, error)
+	// ListArticlesByID と同じく (ピン留め, 作成日時, ID) のキーセットページング
+	ListArticlesByCreatedAtDesc(ctx context.Context, arg ListArticlesByCreatedAtDescParams) ([]Article, error)
+	// 既定の並び順（ピン留めの後は ID の降順）。cursor_* に前ページ最後の記事を渡すキーセットページングで、cursor_id が NULL なら先頭ページ
+	ListArticlesByID(ctx context.Context, arg ListArticlesByIDParams) ([]Article, error)
+	// (ピン留め, 公開日時, ID) のキーセットページング。公開日時のない記事は最後で、cursor_time が NULL ならその中で続ける
+	ListArticlesByPublishedAt(ctx context.Context, arg ListArticlesByPublishedAtParams) ([]Article, error)
+	// (ピン留め, 公開日時, ID) のキーセットページング。公開日時のない記事は最後で、cursor_time が NULL ならその中で続ける
+	ListArticlesByPublishedAtDesc(ctx context.Context, arg ListArticlesByPublishedAtDescParams) ([]Article, error)
+	// ListArticlesByID と同じく (ピン留め, タイトル, ID) のキーセットページング
+	ListArticlesByTitle(ctx context.Context, arg ListArticlesByTitleParams) ([]Article, error)
 	ListArticlesByUser(ctx context.Context, userID int64) ([]Article, error)
+	// エクスポート用。ID順のキーセットページングのため、途中で記事が削除されても行が重複・欠落しない
+	ListArticlesForExport(ctx context.Context, arg ListArticlesForExportParams) ([]Article, error)
+	// 公開IDの導入前に作成された記事の補完用
+	ListArticlesWithoutPublicID(ctx context.Context, limit int32) ([]int64, error)
+	ListCategories(ctx context.Context) ([]Category, error)
+	ListCommentsByArticle(ctx context.Context, arg ListCommentsByArticleParams) ([]Comment, error)
+	// 論理削除済みの記事も含む
+	ListExistingArticleIDs(ctx context.Context, ids []int64) ([]int64, error)
+	// 論理削除済みの記事も含む
+	ListExistingArticleSlugs(ctx context.Context, slugs []string) ([]*string, error)
+	// 論理削除済みのユーザーも含む。emails は小文字で渡す
+	ListExistingUserEmails(ctx context.Context, emails []string) ([]string, error)
+	ListMediaFiles(ctx context.Context, arg ListMediaFilesParams) ([]MediaFile, error)
+	ListMediaFilesByIDs(ctx context.Context, ids []int64) ([]MediaFile, error)
+	// 同じ著者の公開済み記事を新しい順に返す（関連記事のタグがない場合の代替）
+	ListRecentArticlesByAuthor(ctx context.Context, arg ListRecentArticlesByAuthorParams) ([]Article, error)
+	// 指定記事とタグを共有する公開済み記事を、共有タグ数の多い順に返す
+	ListRelatedArticles(ctx context.Context, arg ListRelatedArticlesParams) ([]Article, error)
+	ListScheduledArticles(ctx context.Context) ([]Article, error)
+	ListTagNamesByArticles(ctx context.Context, articleIds []int64) ([]ListTagNamesByArticlesRow, error)
+	ListTagsByArticle(ctx context.Context, articleID int64) ([]Tag, error)
 	ListUsers(ctx context.Context) ([]User, error)
+	// 論理削除済みのユーザーも含む（記事の作成者表示用）
+	ListUsersByIDs(ctx context.Context, ids []int64) ([]User, error)
+	ListUsersPaginated(ctx context.Context, arg ListUsersPaginatedParams) ([]User, error)
+	ListWebhooks(ctx context.Context) ([]Webhook, error)
+	ListWebhooksByEvent(ctx context.Context, event string) ([]Webhook, error)
+	// ピン留め件数の確認と更新を直列化する（トランザクション終了まで保持）
+	LockPinnedArticles(ctx context.Context) error
+	// 公開中の記事（公開日時が未設定または経過済み）は対象外。呼び出し側で冪等に扱う
+	PublishArticle(ctx context.Context, id int64) (Article, error)
+	PublishScheduledArticle(ctx context.Context, id int64) (Article, error)
+	RefreshToken(ctx context.Context, arg RefreshTokenParams) (AccessToken, error)
+	ReleaseIdempotencyKey(ctx context.Context, key string) error
+	RestoreArticle(ctx context.Context, id int64) (Article, error)
+	RestoreUser(ctx context.Context, id int64) (User, error)
+	SearchArticles(ctx context.Context, arg SearchArticlesParams) ([]Article, error)
+	// pattern は ILIKE のパターン。呼び出し側でワイルドカードをエスケープする
+	SearchUsers(ctx context.Context, arg SearchUsersParams) ([]User, error)
+	SetArticlePinned(ctx context.Context, arg SetArticlePinnedParams) (Article, error)
+	SetArticlePublicID(ctx context.Context, arg SetArticlePublicIDParams) (int64, error)
+	SetSetting(ctx context.Context, arg SetSettingParams) error
+	// 確認済みの新しいメールアドレスに変更する
+	SetUserEmail(ctx context.Context, arg SetUserEmailParams) (User, error)
+	// password_hash が NULL ならパスワードログインを無効にする
+	SetUserPassword(ctx context.Context, arg SetUserPasswordParams) (int64, error)
+	SoftDeleteArticle(ctx context.Context, id int64) (int64, error)
+	SoftDeleteArticles(ctx context.Context, ids []int64) ([]int64, error)
+	SoftDeleteUser(ctx context.Context, id int64) (int64, error)
+	// ID を指定して取り込んだ後、採番が取り込んだ ID と衝突しないよう進める
+	SyncArticleIDSequence(ctx context.Context) error
+	// ID を指定して取り込んだ後、採番が取り込んだ ID と衝突しないよう進める
+	SyncUserIDSequence(ctx context.Context) error
+	// 書き込みを抑えるため、1分以内に記録済みなら更新しない
+	TouchAccessToken(ctx context.Context, token string) error
+	// 記事の作成者を変更する。すでに移動先のユーザーが作成者なら行を返さない
+	TransferArticle(ctx context.Context, arg TransferArticleParams) (Article, error)
+	// ユーザーの記事をすべて別のユーザーに移す（論理削除済みの記事も含む）
+	TransferArticlesByUser(ctx context.Context, arg TransferArticlesByUserParams) (int64, error)
+	// 公開日時のない下書きは対象外。呼び出し側で冪等に扱う
+	UnpublishArticle(ctx context.Context, id int64) (Article, error)
 	UpdateArticle(ctx context.Context, arg UpdateArticleParams) (Article, error)
+	UpdateCategory(ctx context.Context, arg UpdateCategoryParams) (Category, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	// secret が NULL なら現在のシークレットを維持する
+	UpdateWebhook(ctx context.Context, arg UpdateWebhookParams) (Webhook, error)
+	UpsertArticleTranslation(ctx context.Context, arg UpsertArticleTranslationParams) (ArticleTranslation, error)
+	UpsertTag(ctx context.Context, name string) (Tag, error)
+	UpsertUserByEmail(ctx context.Context, arg UpsertUserByEmailParams) (UpsertUserByEmailRow, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,41 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// TxBeginner starts transactions; *pgxpool.Pool and *pgx.Conn implement it
+type TxBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// WithTx runs fn with Queries bound to a new transaction on conn.
+// The transaction is committed if fn returns nil and rolled back otherwise,
+// including when fn panics. Nested calls are not supported: fn must use the
+// Queries it is given rather than starting another transaction.
+func WithTx(ctx context.Context, conn TxBeginner, fn func(q *Queries) error) (err error) {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// Roll back even if ctx was canceled, so the connection is released cleanly
+		if p := recover(); p != nil {
+			_ = tx.Rollback(context.WithoutCancel(ctx))
+			panic(p)
+		}
+		if err != nil {
+			if rbErr := tx.Rollback(context.WithoutCancel(ctx)); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+				err = errors.Join(err, rbErr)
+			}
+		}
+	}()
+
+	if err := fn(New(tx)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
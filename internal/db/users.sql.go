@@ -7,24 +7,52 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countSearchUsers = `-- name: CountSearchUsers :one
+SELECT COUNT(*) FROM users
+WHERE deleted_at IS NULL
+  AND (name ILIKE $1 OR email ILIKE $1)
+`
+
+func (q *Queries) CountSearchUsers(ctx context.Context, pattern string) (int64, error) {
+	row := q.db.QueryRow(ctx, countSearchUsers, pattern)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countUsers = `-- name: CountUsers :one
+SELECT COUNT(*) FROM users
+WHERE deleted_at IS NULL
+`
+
+func (q *Queries) CountUsers(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countUsers)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
-    email, name
+    email, name, role, created_at, updated_at
 ) VALUES (
-    $1, $2
+    $1, $2, $3, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
 )
-RETURNING id, name, email, created_at, updated_at
+RETURNING id, name, email, created_at, updated_at, role, deleted_at, password_hash
 `
 
 type CreateUserParams struct {
 	Email string `json:"email"`
 	Name  string `json:"name"`
+	Role  string `json:"role"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
-	row := q.db.QueryRow(ctx, createUser, arg.Email, arg.Name)
+	row := q.db.QueryRow(ctx, createUser, arg.Email, arg.Name, arg.Role)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -32,27 +60,66 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Email,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Role,
+		&i.DeletedAt,
+		&i.PasswordHash,
 	)
 	return i, err
 }
 
-const deleteUser = `-- name: DeleteUser :exec
-DELETE FROM users
-WHERE id = $1
+const getUser = `-- name: GetUser :one
+SELECT id, name, email, created_at, updated_at, role, deleted_at, password_hash FROM users
+WHERE id = $1 AND deleted_at IS NULL LIMIT 1
 `
 
-func (q *Queries) DeleteUser(ctx context.Context, id int64) error {
-	_, err := q.db.Exec(ctx, deleteUser, id)
-	return err
+func (q *Queries) GetUser(ctx context.Context, id int64) (User, error) {
+	row := q.db.QueryRow(ctx, getUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Role,
+		&i.DeletedAt,
+		&i.PasswordHash,
+	)
+	return i, err
 }
 
-const getUser = `-- name: GetUser :one
-SELECT id, name, email, created_at, updated_at FROM users
-WHERE id = $1 LIMIT 1
+const importUser = `-- name: ImportUser :one
+INSERT INTO users (
+    id, email, name, role, created_at, updated_at, deleted_at
+) VALUES (
+    COALESCE($1::bigint, nextval(pg_get_serial_sequence('users', 'id'))),
+    $2, $3, $4,
+    $5, $6, $7
+)
+RETURNING id, name, email, created_at, updated_at, role, deleted_at, password_hash
 `
 
-func (q *Queries) GetUser(ctx context.Context, id int64) (User, error) {
-	row := q.db.QueryRow(ctx, getUser, id)
+type ImportUserParams struct {
+	ID        *int64           `json:"id"`
+	Email     string           `json:"email"`
+	Name      string           `json:"name"`
+	Role      string           `json:"role"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+	DeletedAt pgtype.Timestamp `json:"deleted_at"`
+}
+
+// バックアップからの取り込み用。id が NULL なら採番し、日時はバックアップの値を使う
+func (q *Queries) ImportUser(ctx context.Context, arg ImportUserParams) (User, error) {
+	row := q.db.QueryRow(ctx, importUser,
+		arg.ID,
+		arg.Email,
+		arg.Name,
+		arg.Role,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.DeletedAt,
+	)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -60,12 +127,77 @@ func (q *Queries) GetUser(ctx context.Context, id int64) (User, error) {
 		&i.Email,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Role,
+		&i.DeletedAt,
+		&i.PasswordHash,
 	)
 	return i, err
 }
 
+const listAllUsers = `-- name: ListAllUsers :many
+SELECT id, name, email, created_at, updated_at, role, deleted_at, password_hash FROM users
+ORDER BY id
+`
+
+// 論理削除済みのユーザーも含む（バックアップ用）
+func (q *Queries) ListAllUsers(ctx context.Context) ([]User, error) {
+	rows, err := q.db.Query(ctx, listAllUsers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Role,
+			&i.DeletedAt,
+			&i.PasswordHash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExistingUserEmails = `-- name: ListExistingUserEmails :many
+SELECT email FROM users
+WHERE LOWER(email) = ANY($1::text[])
+`
+
+// 論理削除済みのユーザーも含む。emails は小文字で渡す
+func (q *Queries) ListExistingUserEmails(ctx context.Context, emails []string) ([]string, error) {
+	rows, err := q.db.Query(ctx, listExistingUserEmails, emails)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return nil, err
+		}
+		items = append(items, email)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listUsers = `-- name: ListUsers :many
-SELECT id, name, email, created_at, updated_at FROM users
+SELECT id, name, email, created_at, updated_at, role, deleted_at, password_hash FROM users
+WHERE deleted_at IS NULL
 ORDER BY id
 `
 
@@ -84,6 +216,45 @@ func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
 			&i.Email,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Role,
+			&i.DeletedAt,
+			&i.PasswordHash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersByIDs = `-- name: ListUsersByIDs :many
+SELECT id, name, email, created_at, updated_at, role, deleted_at, password_hash FROM users
+WHERE id = ANY($1::bigint[])
+ORDER BY id
+`
+
+// 論理削除済みのユーザーも含む（記事の作成者表示用）
+func (q *Queries) ListUsersByIDs(ctx context.Context, ids []int64) ([]User, error) {
+	rows, err := q.db.Query(ctx, listUsersByIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Role,
+			&i.DeletedAt,
+			&i.PasswordHash,
 		); err != nil {
 			return nil, err
 		}
@@ -95,11 +266,192 @@ func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
 	return items, nil
 }
 
+const listUsersPaginated = `-- name: ListUsersPaginated :many
+SELECT id, name, email, created_at, updated_at, role, deleted_at, password_hash FROM users
+WHERE deleted_at IS NULL
+ORDER BY id
+LIMIT $1 OFFSET $2
+`
+
+type ListUsersPaginatedParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+func (q *Queries) ListUsersPaginated(ctx context.Context, arg ListUsersPaginatedParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, listUsersPaginated, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Role,
+			&i.DeletedAt,
+			&i.PasswordHash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreUser = `-- name: RestoreUser :one
+UPDATE users
+SET deleted_at = NULL
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING id, name, email, created_at, updated_at, role, deleted_at, password_hash
+`
+
+func (q *Queries) RestoreUser(ctx context.Context, id int64) (User, error) {
+	row := q.db.QueryRow(ctx, restoreUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Role,
+		&i.DeletedAt,
+		&i.PasswordHash,
+	)
+	return i, err
+}
+
+const searchUsers = `-- name: SearchUsers :many
+SELECT id, name, email, created_at, updated_at, role, deleted_at, password_hash FROM users
+WHERE deleted_at IS NULL
+  AND (name ILIKE $1 OR email ILIKE $1)
+ORDER BY id
+LIMIT $2 OFFSET $3
+`
+
+type SearchUsersParams struct {
+	Pattern    string `json:"pattern"`
+	MaxResults int32  `json:"max_results"`
+	RowOffset  int32  `json:"row_offset"`
+}
+
+// pattern は ILIKE のパターン。呼び出し側でワイルドカードをエスケープする
+func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, searchUsers, arg.Pattern, arg.MaxResults, arg.RowOffset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Role,
+			&i.DeletedAt,
+			&i.PasswordHash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setUserEmail = `-- name: SetUserEmail :one
+UPDATE users
+SET email = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, name, email, created_at, updated_at, role, deleted_at, password_hash
+`
+
+type SetUserEmailParams struct {
+	ID    int64  `json:"id"`
+	Email string `json:"email"`
+}
+
+// 確認済みの新しいメールアドレスに変更する
+func (q *Queries) SetUserEmail(ctx context.Context, arg SetUserEmailParams) (User, error) {
+	row := q.db.QueryRow(ctx, setUserEmail, arg.ID, arg.Email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Role,
+		&i.DeletedAt,
+		&i.PasswordHash,
+	)
+	return i, err
+}
+
+const setUserPassword = `-- name: SetUserPassword :execrows
+UPDATE users
+SET password_hash = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+type SetUserPasswordParams struct {
+	ID           int64   `json:"id"`
+	PasswordHash *string `json:"password_hash"`
+}
+
+// password_hash が NULL ならパスワードログインを無効にする
+func (q *Queries) SetUserPassword(ctx context.Context, arg SetUserPasswordParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setUserPassword, arg.ID, arg.PasswordHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const softDeleteUser = `-- name: SoftDeleteUser :execrows
+UPDATE users
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) SoftDeleteUser(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, softDeleteUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const syncUserIDSequence = `-- name: SyncUserIDSequence :exec
+SELECT setval(pg_get_serial_sequence('users', 'id'), GREATEST((SELECT MAX(id) FROM users), 1))
+`
+
+// ID を指定して取り込んだ後、採番が取り込んだ ID と衝突しないよう進める
+func (q *Queries) SyncUserIDSequence(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, syncUserIDSequence)
+	return err
+}
+
 const updateUser = `-- name: UpdateUser :one
 UPDATE users
 SET email = $1, name = $2, updated_at = CURRENT_TIMESTAMP
-WHERE id = $3
-RETURNING id, name, email, created_at, updated_at
+WHERE id = $3 AND deleted_at IS NULL
+RETURNING id, name, email, created_at, updated_at, role, deleted_at, password_hash
 `
 
 type UpdateUserParams struct {
@@ -117,6 +469,56 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.Email,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Role,
+		&i.DeletedAt,
+		&i.PasswordHash,
+	)
+	return i, err
+}
+
+const upsertUserByEmail = `-- name: UpsertUserByEmail :one
+INSERT INTO users (
+    email, name, role, created_at, updated_at
+) VALUES (
+    $1, $2, $3, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+)
+ON CONFLICT ((LOWER(email))) DO UPDATE
+SET name = EXCLUDED.name, updated_at = CURRENT_TIMESTAMP
+WHERE users.deleted_at IS NULL
+RETURNING id, name, email, created_at, updated_at, role, deleted_at, password_hash, (xmax = 0) AS inserted
+`
+
+type UpsertUserByEmailParams struct {
+	Email string `json:"email"`
+	Name  string `json:"name"`
+	Role  string `json:"role"`
+}
+
+type UpsertUserByEmailRow struct {
+	ID           int64            `json:"id"`
+	Name         string           `json:"name"`
+	Email        string           `json:"email"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+	Role         string           `json:"role"`
+	DeletedAt    pgtype.Timestamp `json:"deleted_at"`
+	PasswordHash *string          `json:"-"`
+	Inserted     bool             `json:"inserted"`
+}
+
+func (q *Queries) UpsertUserByEmail(ctx context.Context, arg UpsertUserByEmailParams) (UpsertUserByEmailRow, error) {
+	row := q.db.QueryRow(ctx, upsertUserByEmail, arg.Email, arg.Name, arg.Role)
+	var i UpsertUserByEmailRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Email,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Role,
+		&i.DeletedAt,
+		&i.PasswordHash,
+		&i.Inserted,
 	)
 	return i, err
 }
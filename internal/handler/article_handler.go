@@ -1,124 +1,686 @@
 package handler
 
 import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/para7/nanaket-cms/internal/background"
+	"github.com/para7/nanaket-cms/internal/markdown"
+	"github.com/para7/nanaket-cms/internal/middleware"
 	"github.com/para7/nanaket-cms/internal/usecase"
+	"golang.org/x/text/language"
+)
+
+const (
+	// maxBulkDeleteIDs is the largest number of articles deleted in one request
+	maxBulkDeleteIDs = 100
+	// viewCountTimeout bounds the background view-count update
+	viewCountTimeout = 5 * time.Second
+	// idempotencyKeyHeader makes article creation safe to retry
+	idempotencyKeyHeader = "Idempotency-Key"
+	// idempotentReplayedHeader marks a response replayed for a repeated idempotency key
+	idempotentReplayedHeader = "Idempotent-Replayed"
 )
 
 // ArticleHandler handles HTTP requests for article operations
 type ArticleHandler struct {
-	usecase usecase.ArticleUsecase
+	usecase  usecase.ArticleUsecase
+	webhooks usecase.WebhookUsecase
+	pages    PageSizeConfig
+	// tasks runs view counts and webhook deliveries after the response
+	tasks *background.Tasks
 }
 
-// NewArticleHandler creates a new instance of ArticleHandler
-func NewArticleHandler(usecase usecase.ArticleUsecase) *ArticleHandler {
+// NewArticleHandler creates a new instance of ArticleHandler.
+// webhooks is notified after articles are created, updated or deleted.
+func NewArticleHandler(usecase usecase.ArticleUsecase, webhooks usecase.WebhookUsecase, pages PageSizeConfig, tasks *background.Tasks) *ArticleHandler {
 	return &ArticleHandler{
-		usecase: usecase,
+		usecase:  usecase,
+		webhooks: webhooks,
+		pages:    pages,
+		tasks:    tasks,
 	}
 }
 
 // CreateArticleRequest represents the request body for creating an article
 type CreateArticleRequest struct {
-	UserID      int64  `json:"user_id"`
-	Title       string `json:"title"`
-	Content     string `json:"content"`
-	PublishedAt *int64 `json:"published_at,omitempty"` // Unix timestamp (nullable)
+	UserID          int64    `json:"user_id"`
+	Title           string   `json:"title"`
+	Content         string   `json:"content"`
+	Status          string   `json:"status,omitempty"`       // draft, published or archived
+	PublishedAt     *int64   `json:"published_at,omitempty"` // Unix timestamp (nullable)
+	Tags            []string `json:"tags,omitempty"`
+	FeaturedImageID *int64   `json:"featured_image_id,omitempty"` // media ID (nullable)
+	CategoryID      *int64   `json:"category_id,omitempty"`       // category ID (nullable)
 }
 
 // UpdateArticleRequest represents the request body for updating an article
 type UpdateArticleRequest struct {
-	UserID      int64  `json:"user_id"`
-	Title       string `json:"title"`
-	Content     string `json:"content"`
-	PublishedAt *int64 `json:"published_at,omitempty"` // Unix timestamp (nullable)
+	UserID          int64    `json:"user_id"`
+	Title           string   `json:"title"`
+	Content         string   `json:"content"`
+	Status          string   `json:"status,omitempty"`       // draft, published or archived
+	PublishedAt     *int64   `json:"published_at,omitempty"` // Unix timestamp (nullable)
+	Tags            []string `json:"tags,omitempty"`
+	FeaturedImageID *int64   `json:"featured_image_id,omitempty"` // media ID (nullable)
+	CategoryID      *int64   `json:"category_id,omitempty"`       // category ID (nullable)
+	Version         *int32   `json:"version,omitempty"`           // expected current version; 409 if stale
+}
+
+// TranslationRequest represents the request body for creating or updating a translation
+type TranslationRequest struct {
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+// PatchArticleRequest represents the request body for partially updating an article.
+// Omitted fields are left unchanged.
+type PatchArticleRequest struct {
+	UserID          *int64    `json:"user_id,omitempty"`
+	Title           *string   `json:"title,omitempty"`
+	Content         *string   `json:"content,omitempty"`
+	Status          *string   `json:"status,omitempty"`            // draft, published or archived
+	PublishedAt     *int64    `json:"published_at,omitempty"`      // Unix timestamp
+	Tags            *[]string `json:"tags,omitempty"`              // [] removes all tags
+	FeaturedImageID *int64    `json:"featured_image_id,omitempty"` // 0 removes the featured image
+	CategoryID      *int64    `json:"category_id,omitempty"`       // 0 removes the category
+	Version         *int32    `json:"version,omitempty"`           // expected current version; 409 if stale
+}
+
+// ArticleHTMLResponse is an article with its Markdown content rendered as sanitized HTML
+type ArticleHTMLResponse struct {
+	usecase.Article
+	ContentHTML string `json:"content_html"`
+}
+
+// CountArticlesResponse represents the response body for counting articles
+type CountArticlesResponse struct {
+	Count int64 `json:"count"`
+}
+
+// TransferArticlesRequest represents the request body for transferring articles to another author
+type TransferArticlesRequest struct {
+	NewUserID int64 `json:"new_user_id"`
+}
+
+// TransferArticlesResponse represents the response body for transferring articles
+type TransferArticlesResponse struct {
+	Transferred int64 `json:"transferred"`
+}
+
+// BulkDeleteArticlesRequest represents the request body for deleting several articles
+type BulkDeleteArticlesRequest struct {
+	IDs []int64 `json:"ids"`
+}
+
+// ListArticlesResponse represents the response body for listing articles
+type ListArticlesResponse struct {
+	Items      []usecase.Article `json:"items"`
+	NextCursor string            `json:"next_cursor,omitempty"`
 }
 
 // CreateArticle handles POST /api/v1/articles
+// With an Idempotency-Key header, a repeated request within 24 hours returns the
+// article created by the first one instead of creating another.
 func (h *ArticleHandler) CreateArticle(w http.ResponseWriter, r *http.Request) {
 	var req CreateArticleRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "Invalid request body"})
-		return
-	}
-
-	if req.UserID == 0 || req.Title == "" || req.Content == "" {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "UserID, title, and content are required"})
+		writeDecodeError(w, err)
 		return
 	}
 
-	// Convert publishedAt to pgtype.Timestamp
-	var publishedAt pgtype.Timestamp
+	var publishedAt *time.Time
 	if req.PublishedAt != nil {
-		publishedAt = pgtype.Timestamp{
-			Time:  time.Unix(*req.PublishedAt, 0),
-			Valid: true,
+		t, ok := unixTimestamp(*req.PublishedAt)
+		if !ok {
+			writeError(w, http.StatusBadRequest, CodeInvalidRequest, "published_at is out of range")
+			return
 		}
+		publishedAt = &t
+	}
+
+	in := usecase.ArticleInput{
+		UserID:          req.UserID,
+		Title:           req.Title,
+		Content:         req.Content,
+		Status:          req.Status,
+		PublishedAt:     publishedAt,
+		Tags:            req.Tags,
+		FeaturedImageID: req.FeaturedImageID,
+		CategoryID:      req.CategoryID,
+	}
+	var article usecase.Article
+	var replayed bool
+	var err error
+	if key := r.Header.Get(idempotencyKeyHeader); key != "" {
+		article, replayed, err = h.usecase.CreateArticleIdempotent(r.Context(), key, in)
 	} else {
-		publishedAt = pgtype.Timestamp{
-			Valid: false,
-		}
+		article, err = h.usecase.CreateArticle(r.Context(), in)
+	}
+	if errors.Is(err, usecase.ErrInvalidArticleStatus) {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid status")
+		return
+	}
+	if errors.Is(err, usecase.ErrIdempotencyKeyInUse) {
+		writeError(w, http.StatusConflict, CodeIdempotencyKeyInUse, "A request with this Idempotency-Key is still being processed")
+		return
+	}
+	if errors.Is(err, usecase.ErrArticleNotFound) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "The article created with this Idempotency-Key no longer exists")
+		return
+	}
+	var validationErr *usecase.ValidationError
+	if errors.As(err, &validationErr) {
+		writeValidationError(w, validationErr)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to create article: %v", err))
+		return
+	}
+
+	if replayed {
+		w.Header().Set(idempotentReplayedHeader, "true")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(article)
+	if !replayed {
+		h.notify(r, usecase.EventArticleCreated, article)
+	}
+}
+
+// DuplicateArticle handles POST /api/v1/articles/{id}/duplicate
+// Creates a draft owned by the requesting user with the title (prefixed "Copy of "), content and tags of the article.
+func (h *ArticleHandler) DuplicateArticle(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid article ID")
+		return
+	}
+
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "Authentication required")
+		return
 	}
 
-	article, err := h.usecase.CreateArticle(r.Context(), req.UserID, req.Title, req.Content, publishedAt)
+	article, err := h.usecase.DuplicateArticle(r.Context(), id, user.ID)
+	if errors.Is(err, usecase.ErrArticleNotFound) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "Article not found")
+		return
+	}
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: fmt.Sprintf("Failed to create article: %v", err)})
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to duplicate article: %v", err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	_ = json.NewEncoder(w).Encode(article)
+	h.notify(r, usecase.EventArticleCreated, article)
 }
 
-// GetArticle handles GET /api/v1/articles/{id}
+// GetArticle handles GET and HEAD /api/v1/articles/{id}, where {id} may also be the public ID
+// Responds with an ETag and honors If-None-Match with 304 Not Modified.
+// HEAD answers with the same headers, including Content-Length, but no body and does not count a view.
+// ?format=html adds the content rendered from Markdown as content_html.
+// ?expand=author embeds the author's ID and name.
+// ?locale= or Accept-Language selects a translation.
+// word_count and reading_time_minutes are computed from the content returned.
 func (h *ArticleHandler) GetArticle(w http.ResponseWriter, r *http.Request) {
+	withHTML, ok := articleFormat(w, r)
+	if !ok {
+		return
+	}
+	withAuthor, ok := articleExpand(w, r)
+	if !ok {
+		return
+	}
+	locales, ok := articleLocales(w, r)
+	if !ok {
+		return
+	}
+
+	// {id} is either the numeric ID or the public ID (ULID)
 	idStr := r.PathValue("id")
-	id, err := strconv.ParseInt(idStr, 10, 64)
+	var article usecase.Article
+	var err error
+	if id, parseErr := strconv.ParseInt(idStr, 10, 64); parseErr == nil {
+		article, err = h.usecase.GetArticle(r.Context(), id)
+	} else if usecase.IsPublicID(idStr) {
+		article, err = h.usecase.GetArticleByPublicID(r.Context(), idStr)
+	} else {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid article ID")
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "Article not found")
+		return
+	}
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "Invalid article ID"})
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to get article: %v", err))
+		return
+	}
+	items := []usecase.Article{article}
+	if withAuthor {
+		if err := h.usecase.ExpandAuthors(r.Context(), items); err != nil {
+			writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to load author: %v", err))
+			return
+		}
+	}
+	if err := h.usecase.LocalizeArticles(r.Context(), items, locales); err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to load translation: %v", err))
+		return
+	}
+	usecase.AddReadingTimes(items)
+	article = items[0]
+
+	if writeNotModifiedIfMatch(w, r, articleETag(article)) {
+		return
+	}
+
+	// Encode up front so HEAD can report the Content-Length of the GET body
+	var body bytes.Buffer
+	if withHTML {
+		_ = json.NewEncoder(&body).Encode(ArticleHTMLResponse{Article: article, ContentHTML: markdown.ToHTML(article.Content)})
+	} else {
+		_ = json.NewEncoder(&body).Encode(article)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", strconv.Itoa(body.Len()))
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodHead {
+		return
+	}
+	_, _ = w.Write(body.Bytes())
+	h.recordView(r, article.ID)
+}
+
+// GetArticleBySlug handles GET /api/v1/articles/by-slug?slug={slug}
+// Responds with an ETag and honors If-None-Match with 304 Not Modified.
+// ?format=html adds the content rendered from Markdown as content_html.
+// ?expand=author embeds the author's ID and name.
+// ?locale= or Accept-Language selects a translation.
+// word_count and reading_time_minutes are computed from the content returned.
+func (h *ArticleHandler) GetArticleBySlug(w http.ResponseWriter, r *http.Request) {
+	withHTML, ok := articleFormat(w, r)
+	if !ok {
+		return
+	}
+	withAuthor, ok := articleExpand(w, r)
+	if !ok {
+		return
+	}
+	locales, ok := articleLocales(w, r)
+	if !ok {
+		return
+	}
+
+	slug := r.URL.Query().Get("slug")
+	if slug == "" {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Slug is required")
 		return
 	}
 
-	article, err := h.usecase.GetArticle(r.Context(), id)
+	article, err := h.usecase.GetArticleBySlug(r.Context(), slug)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "Article not found")
+		return
+	}
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "Article not found"})
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to get article: %v", err))
+		return
+	}
+	items := []usecase.Article{article}
+	if withAuthor {
+		if err := h.usecase.ExpandAuthors(r.Context(), items); err != nil {
+			writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to load author: %v", err))
+			return
+		}
+	}
+	if err := h.usecase.LocalizeArticles(r.Context(), items, locales); err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to load translation: %v", err))
+		return
+	}
+	usecase.AddReadingTimes(items)
+	article = items[0]
+
+	if writeNotModifiedIfMatch(w, r, articleETag(article)) {
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	_ = json.NewEncoder(w).Encode(article)
+	if withHTML {
+		_ = json.NewEncoder(w).Encode(ArticleHTMLResponse{Article: article, ContentHTML: markdown.ToHTML(article.Content)})
+	} else {
+		_ = json.NewEncoder(w).Encode(article)
+	}
+	h.recordView(r, article.ID)
 }
 
 // ListArticles handles GET /api/v1/articles
+// Supports cursor-based pagination via ?limit=20&cursor=<opaque>.
+// Only published articles are listed unless an authenticated caller passes ?status=.
+// ?tag=name restricts the list to articles carrying that tag.
+// ?from= and ?to= (Unix seconds, inclusive) restrict it to articles published in that range.
+// ?sort= accepts -id (default, newest first), created_at, -created_at, published_at, -published_at and title.
+// ?expand=author embeds each article's author ID and name.
+// ?locale= or Accept-Language selects translations.
 func (h *ArticleHandler) ListArticles(w http.ResponseWriter, r *http.Request) {
-	articles, err := h.usecase.ListArticles(r.Context())
+	status, ok := listStatus(w, r)
+	if !ok {
+		return
+	}
+	withAuthor, ok := articleExpand(w, r)
+	if !ok {
+		return
+	}
+	locales, ok := articleLocales(w, r)
+	if !ok {
+		return
+	}
+	from, to, ok := listPublishedRange(w, r)
+	if !ok {
+		return
+	}
+
+	sort := r.URL.Query().Get("sort")
+	if sort == "" {
+		sort = usecase.DefaultArticleSort
+	}
+	if !usecase.IsValidArticleSort(sort) {
+		writeError(w, http.StatusBadRequest, CodeInvalidSort, "Invalid sort")
+		return
+	}
+
+	limit, after, ok := parseArticlePage(w, r, h.pages, sort)
+	if !ok {
+		return
+	}
+
+	page, err := h.usecase.ListArticlesPaginated(r.Context(), usecase.ArticleListQuery{
+		Status:        status,
+		Tag:           r.URL.Query().Get("tag"),
+		PublishedFrom: from,
+		PublishedTo:   to,
+		Sort:          sort,
+		Limit:         limit,
+		After:         after,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to list articles: %v", err))
+		return
+	}
+	if withAuthor {
+		if err := h.usecase.ExpandAuthors(r.Context(), page.Items); err != nil {
+			writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to load authors: %v", err))
+			return
+		}
+	}
+	if err := h.usecase.LocalizeArticles(r.Context(), page.Items, locales); err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to load translations: %v", err))
+		return
+	}
+
+	resp := ListArticlesResponse{Items: page.Items}
+	if page.Next != nil {
+		resp.NextCursor = encodeArticleCursor(sort, *page.Next)
+	}
+
+	setPaginationHeaders(w, r, page.Total, articleCursorLinks(sort, page.Next)...)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// CountArticles handles GET /api/v1/articles/count
+// Accepts the same ?status= as ListArticles and an optional ?user_id= author filter.
+func (h *ArticleHandler) CountArticles(w http.ResponseWriter, r *http.Request) {
+	status, ok := listStatus(w, r)
+	if !ok {
+		return
+	}
+
+	userID, ok := listUserID(w, r)
+	if !ok {
+		return
+	}
+
+	count, err := h.usecase.CountArticles(r.Context(), status, userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to count articles: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(CountArticlesResponse{Count: count})
+}
+
+// ListArchiveMonths handles GET /api/v1/articles/archive
+// Returns the number of published articles per year and month of published_at, newest month first.
+func (h *ArticleHandler) ListArchiveMonths(w http.ResponseWriter, r *http.Request) {
+	months, err := h.usecase.ListArchiveMonths(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to list archive: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(months)
+}
+
+// CountArticlesByAuthor handles GET /api/v1/users/article-counts
+// Returns [{user_id, name, count}] for every active user with articles, most articles first.
+// ?status= counts only articles with that status (default: all statuses), and
+// ?include_empty=true also lists users without any.
+func (h *ArticleHandler) CountArticlesByAuthor(w http.ResponseWriter, r *http.Request) {
+	includeEmpty := r.URL.Query().Get("include_empty") == "true"
+	counts, err := h.usecase.CountArticlesByAuthor(r.Context(), r.URL.Query().Get("status"), includeEmpty)
+	if errors.Is(err, usecase.ErrInvalidArticleStatus) {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid status")
+		return
+	}
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: fmt.Sprintf("Failed to list articles: %v", err)})
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to count articles: %v", err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	_ = json.NewEncoder(w).Encode(articles)
+	_ = json.NewEncoder(w).Encode(counts)
+}
+
+// listUserID reads the optional ?user_id= author filter; 0 means no filter.
+// On invalid input it writes an error response and returns ok == false.
+func listUserID(w http.ResponseWriter, r *http.Request) (userID int64, ok bool) {
+	userIDStr := r.URL.Query().Get("user_id")
+	if userIDStr == "" {
+		return 0, true
+	}
+	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if err != nil || userID < 1 {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid user_id")
+		return 0, false
+	}
+	return userID, true
+}
+
+// listPublishedRange reads the optional ?from= and ?to= published_at bounds as Unix seconds;
+// a missing bound is returned as nil. On invalid input or a range with from after to
+// it writes an error response and returns ok == false.
+func listPublishedRange(w http.ResponseWriter, r *http.Request) (from, to *time.Time, ok bool) {
+	from, err := unixQueryParam(r, "from")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid from: must be a Unix timestamp in seconds")
+		return nil, nil, false
+	}
+	to, err = unixQueryParam(r, "to")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid to: must be a Unix timestamp in seconds")
+		return nil, nil, false
+	}
+	if from != nil && to != nil && from.After(*to) {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid range: from must not be after to")
+		return nil, nil, false
+	}
+	return from, to, true
+}
+
+// unixQueryParam parses the query parameter name as Unix seconds, returning nil when it is absent.
+// Times outside years 1-9999 are rejected, as the database cannot store all of them.
+func unixQueryParam(r *http.Request, name string) (*time.Time, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return nil, nil
+	}
+	sec, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	t := time.Unix(sec, 0).UTC()
+	if t.Year() < 1 || t.Year() > 9999 {
+		return nil, fmt.Errorf("%s out of range", name)
+	}
+	return &t, nil
+}
+
+// ExportArticlesCSV handles GET /api/v1/articles/export.csv
+// Streams the articles matching ?status=, ?tag= and ?user_id= as CSV in ID order.
+// Rows are written as they are loaded, so an error after the first batch truncates the file
+// instead of producing an error response; such errors are logged.
+func (h *ArticleHandler) ExportArticlesCSV(w http.ResponseWriter, r *http.Request) {
+	status, ok := listStatus(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := listUserID(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="articles.csv"`)
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"id", "title", "author", "status", "published_at", "created_at"})
+
+	started := false
+	err := h.usecase.ExportArticles(r.Context(), usecase.ArticleExportQuery{
+		Status: status,
+		Tag:    r.URL.Query().Get("tag"),
+		UserID: userID,
+	}, func(rows []usecase.ArticleExportRow) error {
+		for _, row := range rows {
+			_ = cw.Write([]string{
+				strconv.FormatInt(row.Article.ID, 10),
+				row.Article.Title,
+				row.AuthorName,
+				row.Article.Status,
+				csvTimestamp(row.Article.PublishedAt),
+				csvTimestamp(row.Article.CreatedAt),
+			})
+		}
+		// Send each batch as soon as it is written
+		started = true
+		cw.Flush()
+		return cw.Error()
+	})
+	if err != nil {
+		if !started {
+			w.Header().Del("Content-Disposition")
+			writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to export articles: %v", err))
+			return
+		}
+		slog.ErrorContext(r.Context(), "Article export aborted", "error", err)
+		return
+	}
+	cw.Flush()
+}
+
+// csvTimestamp formats a timestamp for CSV export as RFC 3339; NULL becomes an empty field
+func csvTimestamp(ts pgtype.Timestamp) string {
+	if !ts.Valid {
+		return ""
+	}
+	return ts.Time.Format(time.RFC3339)
+}
+
+// listStatus reads the ?status= filter shared by ListArticles and CountArticles.
+// It defaults to published, and other statuses require an authenticated caller.
+// On invalid input it writes an error response and returns ok == false.
+func listStatus(w http.ResponseWriter, r *http.Request) (status string, ok bool) {
+	status = r.URL.Query().Get("status")
+	if status == "" {
+		status = usecase.ArticleStatusPublished
+	}
+	if !usecase.IsValidArticleStatus(status) {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid status")
+		return "", false
+	}
+	if status != usecase.ArticleStatusPublished {
+		if _, ok := middleware.GetUserFromContext(r.Context()); !ok {
+			writeError(w, http.StatusUnauthorized, CodeUnauthorized, "Authentication required to list unpublished articles")
+			return "", false
+		}
+	}
+	return status, true
+}
+
+// SearchArticles handles GET /api/v1/articles/search?q=term
+func (h *ArticleHandler) SearchArticles(w http.ResponseWriter, r *http.Request) {
+	articles, err := h.usecase.SearchArticles(r.Context(), r.URL.Query().Get("q"), int32(h.pages.Max))
+	if errors.Is(err, usecase.ErrEmptySearchQuery) {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Search query is required")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to search articles: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(ListArticlesResponse{Items: articles})
+}
+
+// ListRelatedArticles handles GET /api/v1/articles/{id}/related
+// Up to 5 published articles sharing the most tags are returned; without any, the author's latest articles.
+func (h *ArticleHandler) ListRelatedArticles(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid article ID")
+		return
+	}
+
+	articles, err := h.usecase.ListRelatedArticles(r.Context(), id)
+	if errors.Is(err, usecase.ErrArticleNotFound) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "Article not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to list related articles: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(ListArticlesResponse{Items: articles})
 }
 
 // UpdateArticle handles PUT /api/v1/articles/{id}
@@ -126,51 +688,196 @@ func (h *ArticleHandler) UpdateArticle(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "Invalid article ID"})
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid article ID")
 		return
 	}
 
 	var req UpdateArticleRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "Invalid request body"})
+		writeDecodeError(w, err)
+		return
+	}
+
+	var publishedAt *time.Time
+	if req.PublishedAt != nil {
+		t, ok := unixTimestamp(*req.PublishedAt)
+		if !ok {
+			writeError(w, http.StatusBadRequest, CodeInvalidRequest, "published_at is out of range")
+			return
+		}
+		publishedAt = &t
+	}
+
+	article, err := h.usecase.UpdateArticle(r.Context(), id, usecase.ArticleInput{
+		UserID:          req.UserID,
+		Title:           req.Title,
+		Content:         req.Content,
+		Status:          req.Status,
+		PublishedAt:     publishedAt,
+		Tags:            req.Tags,
+		FeaturedImageID: req.FeaturedImageID,
+		CategoryID:      req.CategoryID,
+		Version:         req.Version,
+	})
+	if errors.Is(err, usecase.ErrInvalidArticleStatus) {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid status")
+		return
+	}
+	if errors.Is(err, usecase.ErrVersionConflict) {
+		writeError(w, http.StatusConflict, CodeVersionConflict, "Article was modified by another request")
+		return
+	}
+	var validationErr *usecase.ValidationError
+	if errors.As(err, &validationErr) {
+		writeValidationError(w, validationErr)
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "Article not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, "Failed to update article")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(article)
+	h.notify(r, usecase.EventArticleUpdated, article)
+}
+
+// PatchArticle handles PATCH /api/v1/articles/{id}
+func (h *ArticleHandler) PatchArticle(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid article ID")
+		return
+	}
+
+	var req PatchArticleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeDecodeError(w, err)
 		return
 	}
 
-	if req.UserID == 0 || req.Title == "" || req.Content == "" {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "UserID, title, and content are required"})
+	if req.UserID == nil && req.Title == nil && req.Content == nil &&
+		req.Status == nil && req.PublishedAt == nil && req.Tags == nil && req.FeaturedImageID == nil && req.CategoryID == nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "At least one field is required")
 		return
 	}
 
-	// Convert publishedAt to pgtype.Timestamp
-	var publishedAt pgtype.Timestamp
+	patch := usecase.ArticlePatch{
+		UserID:          req.UserID,
+		Title:           req.Title,
+		Content:         req.Content,
+		Status:          req.Status,
+		Tags:            req.Tags,
+		FeaturedImageID: req.FeaturedImageID,
+		CategoryID:      req.CategoryID,
+		Version:         req.Version,
+	}
 	if req.PublishedAt != nil {
-		publishedAt = pgtype.Timestamp{
-			Time:  time.Unix(*req.PublishedAt, 0),
-			Valid: true,
-		}
-	} else {
-		publishedAt = pgtype.Timestamp{
-			Valid: false,
+		publishedAt, ok := unixTimestamp(*req.PublishedAt)
+		if !ok {
+			writeError(w, http.StatusBadRequest, CodeInvalidRequest, "published_at is out of range")
+			return
 		}
+		patch.PublishedAt = &publishedAt
+	}
+
+	article, err := h.usecase.UpdateArticlePartial(r.Context(), id, patch)
+	if errors.Is(err, usecase.ErrArticleNotFound) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "Article not found")
+		return
+	}
+	if errors.Is(err, usecase.ErrVersionConflict) {
+		writeError(w, http.StatusConflict, CodeVersionConflict, "Article was modified by another request")
+		return
+	}
+	if errors.Is(err, usecase.ErrInvalidArticleStatus) {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid status")
+		return
+	}
+	var validationErr *usecase.ValidationError
+	if errors.As(err, &validationErr) {
+		writeValidationError(w, validationErr)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to update article: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(article)
+	h.notify(r, usecase.EventArticleUpdated, article)
+}
+
+// ListArticleRevisions handles GET /api/v1/articles/{id}/revisions
+// Returns the saved title and content of every earlier version, newest first.
+func (h *ArticleHandler) ListArticleRevisions(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid article ID")
+		return
+	}
+
+	revisions, err := h.usecase.ListArticleRevisions(r.Context(), id)
+	if errors.Is(err, usecase.ErrArticleNotFound) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "Article not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to list revisions: %v", err))
+		return
 	}
 
-	article, err := h.usecase.UpdateArticle(r.Context(), id, req.UserID, req.Title, req.Content, publishedAt)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(revisions)
+}
+
+// RestoreArticleRevision handles POST /api/v1/articles/{id}/revisions/{revId}/restore
+// Sets the article's title and content back to the revision; the replaced content is saved as a new revision.
+func (h *ArticleHandler) RestoreArticleRevision(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid article ID")
+		return
+	}
+	revisionID, err := strconv.ParseInt(r.PathValue("revId"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid revision ID")
+		return
+	}
+
+	article, err := h.usecase.RestoreArticleRevision(r.Context(), id, revisionID)
+	if errors.Is(err, usecase.ErrRevisionNotFound) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "Revision not found")
+		return
+	}
+	if errors.Is(err, usecase.ErrArticleNotFound) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "Article not found")
+		return
+	}
+	if errors.Is(err, usecase.ErrVersionConflict) {
+		writeError(w, http.StatusConflict, CodeVersionConflict, "Article was modified by another request")
+		return
+	}
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: fmt.Sprintf("Failed to update article: %v", err)})
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to restore revision: %v", err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(article)
+	h.notify(r, usecase.EventArticleUpdated, article)
 }
 
 // DeleteArticle handles DELETE /api/v1/articles/{id}
@@ -178,19 +885,402 @@ func (h *ArticleHandler) DeleteArticle(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "Invalid article ID"})
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid article ID")
 		return
 	}
 
-	if err := h.usecase.DeleteArticle(r.Context(), id); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "Article not found"})
+	err = h.usecase.DeleteArticle(r.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "Article not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to delete article: %v", err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusNoContent)
+	h.notify(r, usecase.EventArticleDeleted, usecase.DeletedArticle{ID: id})
+}
+
+// BulkDeleteArticles handles POST /api/v1/articles/bulk-delete
+// Soft-deletes up to maxBulkDeleteIDs articles at once and reports which IDs were not found.
+func (h *ArticleHandler) BulkDeleteArticles(w http.ResponseWriter, r *http.Request) {
+	var req BulkDeleteArticlesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	if len(req.IDs) == 0 {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "ids is required")
+		return
+	}
+	if len(req.IDs) > maxBulkDeleteIDs {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, fmt.Sprintf("At most %d ids can be deleted at once", maxBulkDeleteIDs))
+		return
+	}
+
+	result, err := h.usecase.BulkDeleteArticles(r.Context(), req.IDs)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to delete articles: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(result)
+	for _, id := range result.Deleted {
+		h.notify(r, usecase.EventArticleDeleted, usecase.DeletedArticle{ID: id})
+	}
+}
+
+// RestoreArticle handles POST /api/v1/articles/{id}/restore
+func (h *ArticleHandler) RestoreArticle(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid article ID")
+		return
+	}
+
+	article, err := h.usecase.RestoreArticle(r.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "Deleted article not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to restore article: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(article)
+	h.notify(r, usecase.EventArticleUpdated, article)
+}
+
+// PublishArticle handles POST /api/v1/articles/{id}/publish
+// The article is published with published_at set to now; an article already published is returned unchanged.
+func (h *ArticleHandler) PublishArticle(w http.ResponseWriter, r *http.Request) {
+	h.setPublished(w, r, h.usecase.PublishArticle, "publish")
+}
+
+// UnpublishArticle handles POST /api/v1/articles/{id}/unpublish
+// The article becomes a draft without published_at; a draft without one is returned unchanged.
+func (h *ArticleHandler) UnpublishArticle(w http.ResponseWriter, r *http.Request) {
+	h.setPublished(w, r, h.usecase.UnpublishArticle, "unpublish")
+}
+
+// setPublished runs a publish or unpublish action and notifies webhooks when the article changed
+func (h *ArticleHandler) setPublished(w http.ResponseWriter, r *http.Request, action func(context.Context, int64) (usecase.Article, bool, error), verb string) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid article ID")
+		return
+	}
+
+	article, changed, err := action(r.Context(), id)
+	if errors.Is(err, usecase.ErrArticleNotFound) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "Article not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to %s article: %v", verb, err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(article)
+	if changed {
+		h.notify(r, usecase.EventArticleUpdated, article)
+	}
+}
+
+// TransferArticle handles POST /api/v1/articles/{id}/transfer
+// Makes new_user_id the author of the article and returns the number of articles transferred,
+// which is 0 when new_user_id already is the author.
+func (h *ArticleHandler) TransferArticle(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid article ID")
+		return
+	}
+
+	var req TransferArticlesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	article, changed, err := h.usecase.TransferArticle(r.Context(), id, req.NewUserID)
+	if errors.Is(err, usecase.ErrArticleNotFound) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "Article not found")
+		return
+	}
+	var validationErr *usecase.ValidationError
+	if errors.As(err, &validationErr) {
+		writeValidationError(w, validationErr)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to transfer article: %v", err))
+		return
+	}
+
+	resp := TransferArticlesResponse{}
+	if changed {
+		resp.Transferred = 1
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+	if changed {
+		h.notify(r, usecase.EventArticleUpdated, article)
+	}
+}
+
+// TransferUserArticles handles POST /api/v1/users/{id}/transfer-articles
+// Makes new_user_id the author of all articles of the user, including soft-deleted ones,
+// and returns how many were transferred. No webhooks are sent for the individual articles.
+func (h *ArticleHandler) TransferUserArticles(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	userID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid user ID")
+		return
+	}
+
+	var req TransferArticlesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	count, err := h.usecase.TransferUserArticles(r.Context(), userID, req.NewUserID)
+	var validationErr *usecase.ValidationError
+	if errors.As(err, &validationErr) {
+		writeValidationError(w, validationErr)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to transfer articles: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(TransferArticlesResponse{Transferred: count})
+}
+
+// PinArticle handles POST /api/v1/articles/{id}/pin
+func (h *ArticleHandler) PinArticle(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid article ID")
+		return
+	}
+
+	article, err := h.usecase.PinArticle(r.Context(), id)
+	if errors.Is(err, usecase.ErrArticleNotFound) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "Article not found")
+		return
+	}
+	if errors.Is(err, usecase.ErrPinLimitReached) {
+		writeError(w, http.StatusConflict, CodePinLimitReached, "Too many articles are pinned; unpin one first")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to pin article: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(article)
+	h.notify(r, usecase.EventArticleUpdated, article)
+}
+
+// UnpinArticle handles DELETE /api/v1/articles/{id}/pin
+func (h *ArticleHandler) UnpinArticle(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid article ID")
+		return
+	}
+
+	article, err := h.usecase.UnpinArticle(r.Context(), id)
+	if errors.Is(err, usecase.ErrArticleNotFound) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "Article not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to unpin article: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(article)
+	h.notify(r, usecase.EventArticleUpdated, article)
+}
+
+// UpsertTranslation handles PUT /api/v1/articles/{id}/translations/{locale}
+// It creates the translation of the article into locale, or replaces an existing one.
+func (h *ArticleHandler) UpsertTranslation(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid article ID")
+		return
+	}
+
+	var req TranslationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	translation, err := h.usecase.UpsertTranslation(r.Context(), id, r.PathValue("locale"), usecase.TranslationInput{
+		Title:   req.Title,
+		Content: req.Content,
+	})
+	var validationErr *usecase.ValidationError
+	if errors.As(err, &validationErr) {
+		writeValidationError(w, validationErr)
+		return
+	}
+	if errors.Is(err, usecase.ErrArticleNotFound) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "Article not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to save translation: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(translation)
+}
+
+// HardDeleteArticle handles DELETE /api/v1/articles/{id}/permanent
+func (h *ArticleHandler) HardDeleteArticle(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid article ID")
+		return
+	}
+
+	err = h.usecase.HardDeleteArticle(r.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "Article not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to delete article: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNoContent)
+	h.notify(r, usecase.EventArticleDeleted, usecase.DeletedArticle{ID: id})
+}
+
+// recordView increments the article's view count in the background once the response
+// has been written, so the reader never waits on it. The update is fire-and-forget:
+// failures are logged and the view is lost, which is acceptable for analytics.
+func (h *ArticleHandler) recordView(r *http.Request, id int64) {
+	ctx := context.WithoutCancel(r.Context())
+	h.tasks.Go(func() {
+		ctx, cancel := context.WithTimeout(ctx, viewCountTimeout)
+		defer cancel()
+		if err := h.usecase.RecordArticleView(ctx, id); err != nil {
+			slog.WarnContext(ctx, "Failed to record article view", "article_id", id, "error", err)
+		}
+	})
+}
+
+// notify sends a webhook event in the background once the response has been written,
+// so the client never waits on subscribers. Delivery is best-effort and bounded by the
+// webhook timeout.
+func (h *ArticleHandler) notify(r *http.Request, event string, data any) {
+	ctx := context.WithoutCancel(r.Context())
+	h.tasks.Go(func() { h.webhooks.Dispatch(ctx, event, data) })
+}
+
+// articleFormat reads ?format= and reports whether content_html was requested.
+// On an unknown format it writes a 400 response and returns ok=false.
+func articleFormat(w http.ResponseWriter, r *http.Request) (withHTML, ok bool) {
+	switch r.URL.Query().Get("format") {
+	case "":
+		return false, true
+	case "html":
+		return true, true
+	}
+	writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid format")
+	return false, false
+}
+
+// articleExpand reads ?expand=, a comma-separated list of related resources to embed,
+// and reports whether the author was requested.
+// On an unknown value it writes a 400 response and returns ok=false.
+func articleExpand(w http.ResponseWriter, r *http.Request) (withAuthor, ok bool) {
+	expand := r.URL.Query().Get("expand")
+	if expand == "" {
+		return false, true
+	}
+	for name := range strings.SplitSeq(expand, ",") {
+		if strings.TrimSpace(name) != "author" {
+			writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid expand")
+			return false, false
+		}
+		withAuthor = true
+	}
+	return withAuthor, true
+}
+
+// articleLocales returns the locales the client prefers, most preferred first: the one given
+// by ?locale=, or else the languages listed in Accept-Language. Since the response then depends
+// on Accept-Language, it is added to Vary.
+// On an invalid ?locale= it writes a 400 response and returns ok=false; an invalid
+// Accept-Language header is ignored.
+func articleLocales(w http.ResponseWriter, r *http.Request) (locales []string, ok bool) {
+	w.Header().Add("Vary", "Accept-Language")
+	if param := r.URL.Query().Get("locale"); param != "" {
+		locale, err := usecase.ParseLocale(param)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid locale")
+			return nil, false
+		}
+		return []string{locale}, true
+	}
+
+	tags, _, err := language.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+	if err != nil {
+		return nil, true
+	}
+	for _, tag := range tags {
+		locales = append(locales, tag.String())
+	}
+	return locales, true
+}
+
+// maxUnixTimestamp is 9999-12-31T23:59:59Z, the latest timestamp accepted from clients
+const maxUnixTimestamp = 253402300799
+
+// unixTimestamp converts a Unix timestamp from a request body.
+// It reports false for values outside 1970..9999, which also keeps time.Unix from overflowing.
+func unixTimestamp(sec int64) (time.Time, bool) {
+	if sec < 0 || sec > maxUnixTimestamp {
+		return time.Time{}, false
+	}
+	return time.Unix(sec, 0), true
 }
@@ -0,0 +1,386 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/para7/nanaket-cms/internal/background"
+	"github.com/para7/nanaket-cms/internal/db"
+	"github.com/para7/nanaket-cms/internal/db/mock"
+	"github.com/para7/nanaket-cms/internal/middleware"
+	"github.com/para7/nanaket-cms/internal/usecase"
+)
+
+// articleFixture holds the mocks behind an ArticleHandler.
+// Tests adjust the function fields before calling handler.
+type articleFixture struct {
+	articles     *mock.ArticleRepository
+	tags         *mock.TagRepository
+	translations *mock.ArticleTranslationRepository
+	tx           *mock.Transactor
+	tasks        *background.Tasks
+}
+
+// newArticleFixture returns mocks that serve articles by ID, without tags or translations
+func newArticleFixture(articles ...db.Article) *articleFixture {
+	return &articleFixture{
+		articles: &mock.ArticleRepository{
+			GetByIDFunc: func(ctx context.Context, id int64) (db.Article, error) {
+				for _, a := range articles {
+					if a.ID == id {
+						return a, nil
+					}
+				}
+				return db.Article{}, sql.ErrNoRows
+			},
+			IncrementViewCountFunc: func(ctx context.Context, id int64) error {
+				return nil
+			},
+		},
+		tags: &mock.TagRepository{
+			ListByArticleFunc: func(ctx context.Context, articleID int64) ([]db.Tag, error) {
+				return nil, nil
+			},
+		},
+		translations: &mock.ArticleTranslationRepository{
+			ListByArticlesFunc: func(ctx context.Context, articleIDs []int64, locales []string) (map[int64]map[string]db.ArticleTranslation, error) {
+				return nil, nil
+			},
+		},
+		tx:    &mock.Transactor{},
+		tasks: background.New(),
+	}
+}
+
+func (f *articleFixture) handler() *ArticleHandler {
+	u := usecase.NewArticleUsecase(f.articles, f.tags, nil, nil, nil, nil, nil, nil, f.translations, f.tx, time.Hour, 5, "en", false)
+	return NewArticleHandler(u, noWebhooks{}, PageSizeConfig{Default: 20, Max: 100}, f.tasks)
+}
+
+// wait blocks until the background work started by the handler is done
+func (f *articleFixture) wait(t *testing.T) {
+	t.Helper()
+	if err := f.tasks.Wait(context.Background()); err != nil {
+		t.Fatalf("wait for background tasks: %v", err)
+	}
+}
+
+// noWebhooks drops every event
+type noWebhooks struct {
+	usecase.WebhookUsecase
+}
+
+func (noWebhooks) Dispatch(ctx context.Context, event string, data any) {}
+
+// testArticle returns a published article last updated at updatedAt
+func testArticle(id int64, updatedAt time.Time) db.Article {
+	slug, publicID := "article", "01HZX3C5R6K9T1V2W3X4Y5Z6A7"
+	return db.Article{
+		ID:        id,
+		UserID:    1,
+		Title:     "Title",
+		Content:   "Content",
+		Status:    usecase.ArticleStatusPublished,
+		Slug:      &slug,
+		PublicID:  &publicID,
+		CreatedAt: pgtype.Timestamp{Time: updatedAt, Valid: true},
+		UpdatedAt: pgtype.Timestamp{Time: updatedAt, Valid: true},
+	}
+}
+
+// getArticle serves a GET or HEAD for article id through h
+func getArticle(h *ArticleHandler, method, id string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/api/v1/articles/"+id, nil)
+	req.SetPathValue("id", id)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	h.GetArticle(rec, req)
+	return rec
+}
+
+func TestGetArticleNotModified(t *testing.T) {
+	updatedAt := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	f := newArticleFixture(testArticle(1, updatedAt))
+	h := f.handler()
+
+	first := getArticle(h, http.MethodGet, "1", nil)
+	if first.Code != http.StatusOK {
+		t.Fatalf("first status = %d, want %d", first.Code, http.StatusOK)
+	}
+	etag := first.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("first response has no ETag")
+	}
+
+	second := getArticle(h, http.MethodGet, "1", http.Header{"If-None-Match": {etag}})
+	if second.Code != http.StatusNotModified {
+		t.Fatalf("second status = %d, want %d", second.Code, http.StatusNotModified)
+	}
+	if second.Body.Len() != 0 {
+		t.Errorf("304 response has a body: %q", second.Body.String())
+	}
+	if got := second.Header().Get("ETag"); got != etag {
+		t.Errorf("304 ETag = %q, want %q", got, etag)
+	}
+
+	// An edit bumps updated_at, so the old ETag no longer matches
+	f.articles.GetByIDFunc = func(ctx context.Context, id int64) (db.Article, error) {
+		return testArticle(1, updatedAt.Add(time.Minute)), nil
+	}
+	third := getArticle(h, http.MethodGet, "1", http.Header{"If-None-Match": {etag}})
+	if third.Code != http.StatusOK {
+		t.Errorf("status after an edit = %d, want %d", third.Code, http.StatusOK)
+	}
+	f.wait(t)
+}
+
+func TestETagMatches(t *testing.T) {
+	etag := `W/"abc"`
+	tests := []struct {
+		ifNoneMatch string
+		want        bool
+	}{
+		{"", false},
+		{`W/"abc"`, true},
+		{`"abc"`, true},
+		{`"xyz", W/"abc"`, true},
+		{`"xyz"`, false},
+		{"*", true},
+	}
+	for _, tt := range tests {
+		if got := etagMatches(tt.ifNoneMatch, etag); got != tt.want {
+			t.Errorf("etagMatches(%q) = %v, want %v", tt.ifNoneMatch, got, tt.want)
+		}
+	}
+}
+
+func TestUnixTimestamp(t *testing.T) {
+	tests := []struct {
+		sec int64
+		ok  bool
+	}{
+		{-1, false},
+		{0, true},
+		{946684800, true},
+		{maxUnixTimestamp, true},
+		{maxUnixTimestamp + 1, false},
+		{math.MaxInt64, false},
+		{math.MinInt64, false},
+	}
+	for _, tt := range tests {
+		got, ok := unixTimestamp(tt.sec)
+		if ok != tt.ok {
+			t.Errorf("unixTimestamp(%d) ok = %v, want %v", tt.sec, ok, tt.ok)
+		}
+		if ok && got.Unix() != tt.sec {
+			t.Errorf("unixTimestamp(%d) = %v", tt.sec, got)
+		}
+	}
+}
+
+func TestCreateArticleBodyTooLarge(t *testing.T) {
+	const limit = 1024
+	// The usecase is never reached, so the fixture serves no articles
+	h := middleware.MaxBodySize(limit)(http.HandlerFunc(newArticleFixture().handler().CreateArticle))
+	body := `{"user_id":1,"title":"Title","content":"` + strings.Repeat("a", 2*limit) + `"}`
+
+	tests := []struct {
+		name          string
+		contentLength int64
+	}{
+		// Rejected by the middleware before the handler runs
+		{"declared length", int64(len(body))},
+		// Rejected by the handler once decoding reads past the limit
+		{"unknown length", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/articles", strings.NewReader(body))
+			req.ContentLength = tt.contentLength
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusRequestEntityTooLarge {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusRequestEntityTooLarge)
+			}
+			if code := errorCode(t, rec); code != CodeRequestTooLarge {
+				t.Errorf("code = %q, want %q", code, CodeRequestTooLarge)
+			}
+		})
+	}
+}
+
+func TestCreateArticleEmptyBody(t *testing.T) {
+	h := newArticleFixture().handler()
+
+	tests := []struct {
+		name string
+		body io.Reader
+	}{
+		// httptest, like the server, gives a request without a body http.NoBody
+		{"nil body", nil},
+		{"zero length", strings.NewReader("")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.CreateArticle(rec, httptest.NewRequest(http.MethodPost, "/api/v1/articles", tt.body))
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+			}
+			if code := errorCode(t, rec); code != CodeEmptyBody {
+				t.Errorf("code = %q, want %q", code, CodeEmptyBody)
+			}
+		})
+	}
+}
+
+func TestCreateArticleDecodeErrors(t *testing.T) {
+	h := newArticleFixture().handler()
+
+	tests := []struct {
+		name    string
+		body    string
+		code    string
+		message string
+	}{
+		{"trailing comma", `{"title":"Title",}`, CodeMalformedJSON, "Malformed JSON at byte 18"},
+		{"cut off", `{"title":"Title"`, CodeMalformedJSON, "Malformed JSON: request body ends unexpectedly"},
+		{"wrong field type", `{"user_id":"1","title":"Title"}`, CodeInvalidType, `Field "user_id" must be an integer, got string`},
+		{"not an object", `["Title"]`, CodeInvalidType, "Request body must be an object, got array"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.CreateArticle(rec, httptest.NewRequest(http.MethodPost, "/api/v1/articles", strings.NewReader(tt.body)))
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+			}
+			var body ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("decode error response: %v", err)
+			}
+			if body.Code != tt.code {
+				t.Errorf("code = %q, want %q", body.Code, tt.code)
+			}
+			if !strings.HasPrefix(body.Error, tt.message) {
+				t.Errorf("error = %q, want it to start with %q", body.Error, tt.message)
+			}
+		})
+	}
+}
+
+func TestHeadArticle(t *testing.T) {
+	f := newArticleFixture(testArticle(1, time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)))
+	views := 0
+	f.articles.IncrementViewCountFunc = func(ctx context.Context, id int64) error {
+		views++
+		return nil
+	}
+	h := f.handler()
+
+	head := getArticle(h, http.MethodHead, "1", nil)
+	f.wait(t)
+	if head.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", head.Code, http.StatusOK)
+	}
+	if head.Body.Len() != 0 {
+		t.Errorf("HEAD response has a body: %q", head.Body.String())
+	}
+	if views != 0 {
+		t.Errorf("HEAD counted %d views, want 0", views)
+	}
+
+	// The headers describe the body a GET returns
+	get := getArticle(h, http.MethodGet, "1", nil)
+	f.wait(t)
+	if got, want := head.Header().Get("Content-Length"), strconv.Itoa(get.Body.Len()); got != want {
+		t.Errorf("Content-Length = %q, want %q", got, want)
+	}
+	if got, want := head.Header().Get("ETag"), get.Header().Get("ETag"); got == "" || got != want {
+		t.Errorf("ETag = %q, want %q", got, want)
+	}
+
+	// net/http drops the error body of a HEAD response, so only the status matters
+	if missing := getArticle(h, http.MethodHead, "2", nil); missing.Code != http.StatusNotFound {
+		t.Errorf("missing article status = %d, want %d", missing.Code, http.StatusNotFound)
+	}
+}
+
+func TestArticleTimeout(t *testing.T) {
+	// Every repository call sleeps past the deadline, then fails like a canceled query
+	slow := func(ctx context.Context) error {
+		time.Sleep(50 * time.Millisecond)
+		return ctx.Err()
+	}
+	f := newArticleFixture()
+	f.articles.GetByIDFunc = func(ctx context.Context, id int64) (db.Article, error) {
+		return db.Article{}, slow(ctx)
+	}
+	f.articles.DeleteFunc = func(ctx context.Context, id int64) error {
+		return slow(ctx)
+	}
+	f.articles.RestoreFunc = func(ctx context.Context, id int64) (db.Article, error) {
+		return db.Article{}, slow(ctx)
+	}
+	h := f.handler()
+
+	tests := []struct {
+		name    string
+		method  string
+		handler http.HandlerFunc
+	}{
+		{"get", http.MethodGet, h.GetArticle},
+		{"delete", http.MethodDelete, h.DeleteArticle},
+		{"restore", http.MethodPost, h.RestoreArticle},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v1/articles/1", nil)
+			req.SetPathValue("id", "1")
+			rec := httptest.NewRecorder()
+			middleware.Timeout(10*time.Millisecond)(tt.handler).ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusGatewayTimeout {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusGatewayTimeout)
+			}
+			if code := errorCode(t, rec); code != "timeout" {
+				t.Errorf("code = %q, want timeout", code)
+			}
+		})
+	}
+}
+
+func TestHardDeleteMissingArticle(t *testing.T) {
+	f := newArticleFixture()
+	f.articles.HardDeleteFunc = func(ctx context.Context, id int64) error {
+		return sql.ErrNoRows
+	}
+	h := f.handler()
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/articles/1/permanent", nil)
+	req.SetPathValue("id", "1")
+	rec := httptest.NewRecorder()
+	h.HardDeleteArticle(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if code := errorCode(t, rec); code != CodeNotFound {
+		t.Errorf("code = %q, want %q", code, CodeNotFound)
+	}
+}
@@ -1,108 +1,478 @@
 package handler
 
 import (
-	"database/sql"
 	"encoding/json"
 	"errors"
-	"log"
+	"io"
+	"log/slog"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/para7/nanaket-cms/internal/db"
 	"github.com/para7/nanaket-cms/internal/middleware"
+	"github.com/para7/nanaket-cms/internal/usecase"
 )
 
 // AuthHandler handles HTTP requests for authentication operations
 type AuthHandler struct {
-	queries db.Querier
+	usecase usecase.AuthUsecase
+	cookies CookieConfig
 }
 
-// NewAuthHandler creates a new instance of AuthHandler
-func NewAuthHandler(queries db.Querier) *AuthHandler {
+// NewAuthHandler creates a new instance of AuthHandler.
+// cookies sets the attributes of the auth cookie.
+func NewAuthHandler(usecase usecase.AuthUsecase, cookies CookieConfig) *AuthHandler {
 	return &AuthHandler{
-		queries: queries,
+		usecase: usecase,
+		cookies: cookies,
 	}
 }
 
 // LoginRequest represents the request body for login
 type LoginRequest struct {
 	Token string `json:"token"`
+	// Signed requests a short-lived signed token, verified without a database lookup
+	Signed bool `json:"signed,omitempty"`
 }
 
 // LoginResponse represents the response body for successful login
 type LoginResponse struct {
 	Message string  `json:"message"`
 	User    db.User `json:"user"`
+	// Token and ExpiresAt are only set for signed and password logins
+	Token     string     `json:"token,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// PasswordLoginRequest represents the request body for logging in with a password
+type PasswordLoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// SetPasswordRequest represents the request body for setting a user's password
+type SetPasswordRequest struct {
+	Password string `json:"password"`
+	// CurrentPassword is required when users change their own existing password
+	CurrentPassword string `json:"current_password,omitempty"`
+}
+
+// RefreshRequest represents the request body for refreshing a token
+type RefreshRequest struct {
+	Token string `json:"token"`
+}
+
+// RefreshResponse represents the response body for a successful token refresh
+type RefreshResponse struct {
+	Message   string    `json:"message"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// CreateTokenRequest represents the request body for creating an access token
+type CreateTokenRequest struct {
+	ExpiresAt *int64 `json:"expires_at,omitempty"` // Unix timestamp (omit for a non-expiring token)
+}
+
+// TokenResponse represents access token metadata; the token itself is never included
+type TokenResponse struct {
+	ID         int64      `json:"id"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
+
+// CreateTokenResponse represents the response body for a newly created access token.
+// Token is the only time the plaintext token is revealed.
+type CreateTokenResponse struct {
+	TokenResponse
+	Token string `json:"token"`
+}
+
+// ListTokensResponse represents the response body for listing access tokens
+type ListTokensResponse struct {
+	Items []TokenResponse `json:"items"`
 }
 
 // Login handles POST /api/v1/auth/login
-// It validates the provided token and sets it as a secure cookie
+// It validates the provided token and sets it as a secure cookie.
+// With "signed": true it instead issues a signed token, returned in the body and set as the cookie.
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var req LoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "Invalid request body"})
+		writeDecodeError(w, err)
 		return
 	}
 
 	if req.Token == "" {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "Token is required"})
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Token is required")
+		return
+	}
+	if req.Signed {
+		h.loginSigned(w, r, req.Token)
 		return
 	}
 
 	// Validate token
-	user, err := h.queries.GetUserByToken(r.Context(), req.Token)
+	session, err := h.usecase.Login(r.Context(), req.Token)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusUnauthorized)
-			_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "Invalid or expired token"})
+		if errors.Is(err, usecase.ErrInvalidToken) {
+			writeError(w, http.StatusUnauthorized, CodeUnauthorized, "Invalid or expired token")
 			return
 		}
-		log.Printf("Error validating token: %v", err)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "Internal server error"})
+		slog.ErrorContext(r.Context(), "Error validating token", "error", err)
+		writeError(w, http.StatusInternalServerError, CodeInternal, "Internal server error")
 		return
 	}
 
-	// Set secure cookie with the token
-	cookie := &http.Cookie{
-		Name:     middleware.CookieName,
-		Value:    req.Token,
-		Path:     "/",
-		MaxAge:   60 * 60 * 24 * 7, // 7 days
-		HttpOnly: true,              // Prevent JavaScript access (XSS protection)
-		Secure:   true,              // Only send over HTTPS
-		SameSite: http.SameSiteStrictMode, // CSRF protection
-	}
-	http.SetCookie(w, cookie)
+	// Set the auth cookie with the token, matching its remaining lifetime
+	http.SetCookie(w, h.cookies.authCookie(req.Token, h.cookies.maxAgeFor(session.Token.ExpiresAt)))
 
 	// Return success response with user info
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(LoginResponse{
 		Message: "Login successful",
-		User:    user,
+		User:    session.User,
+	})
+}
+
+// loginSigned exchanges a stored token for a signed token.
+// Signed tokens cannot be refreshed or revoked; clients log in again once they expire.
+func (h *AuthHandler) loginSigned(w http.ResponseWriter, r *http.Request, token string) {
+	session, err := h.usecase.LoginSigned(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, usecase.ErrSignedTokensDisabled) {
+			writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Signed tokens are not enabled")
+			return
+		}
+		if errors.Is(err, usecase.ErrInvalidToken) {
+			writeError(w, http.StatusUnauthorized, CodeUnauthorized, "Invalid or expired token")
+			return
+		}
+		slog.ErrorContext(r.Context(), "Error issuing signed token", "error", err)
+		writeError(w, http.StatusInternalServerError, CodeInternal, "Internal server error")
+		return
+	}
+
+	http.SetCookie(w, h.cookies.authCookie(session.Token, h.cookies.maxAgeFor(pgtype.Timestamp{Time: session.ExpiresAt, Valid: true})))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(LoginResponse{
+		Message:   "Login successful",
+		User:      session.User,
+		Token:     session.Token,
+		ExpiresAt: &session.ExpiresAt,
+	})
+}
+
+// PasswordLogin handles POST /api/v1/auth/password-login
+// It checks the email and password, issues a new token and sets it as the auth cookie.
+// The token is also returned in the body for clients that use the Authorization header.
+func (h *AuthHandler) PasswordLogin(w http.ResponseWriter, r *http.Request) {
+	var req PasswordLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	if req.Email == "" || req.Password == "" {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Email and password are required")
+		return
+	}
+
+	session, err := h.usecase.PasswordLogin(r.Context(), req.Email, req.Password)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidCredentials) {
+			writeError(w, http.StatusUnauthorized, CodeUnauthorized, "Invalid email or password")
+			return
+		}
+		var lockedErr *usecase.AccountLockedError
+		if errors.As(err, &lockedErr) {
+			w.Header().Set("Retry-After", strconv.Itoa(max(int(math.Ceil(lockedErr.RetryAfter.Seconds())), 1)))
+			writeError(w, http.StatusTooManyRequests, CodeAccountLocked, "Too many failed logins; try again later")
+			return
+		}
+		slog.ErrorContext(r.Context(), "Error logging in with password", "error", err)
+		writeError(w, http.StatusInternalServerError, CodeInternal, "Internal server error")
+		return
+	}
+
+	http.SetCookie(w, h.cookies.authCookie(session.Token.Secret, h.cookies.maxAgeFor(session.Token.Token.ExpiresAt)))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(LoginResponse{
+		Message:   "Login successful",
+		User:      session.User,
+		Token:     session.Token.Secret,
+		ExpiresAt: &session.Token.Token.ExpiresAt.Time,
+	})
+}
+
+// Refresh handles POST /api/v1/auth/refresh
+// It exchanges a valid token for a new one with a fresh expiry and invalidates the old token.
+// The token is read from the request body, falling back to the Authorization header or cookie.
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req RefreshRequest
+	// The body is optional
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeDecodeError(w, err)
+		return
+	}
+	if req.Token == "" {
+		req.Token = middleware.ExtractToken(r)
+	}
+
+	if req.Token == "" {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Token is required")
+		return
+	}
+
+	issued, err := h.usecase.Refresh(r.Context(), req.Token)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidToken) {
+			writeError(w, http.StatusUnauthorized, CodeUnauthorized, "Invalid or expired token")
+			return
+		}
+		slog.ErrorContext(r.Context(), "Error refreshing token", "error", err)
+		writeError(w, http.StatusInternalServerError, CodeInternal, "Internal server error")
+		return
+	}
+
+	// Replace the cookie with the new token
+	http.SetCookie(w, h.cookies.authCookie(issued.Secret, h.cookies.maxAgeFor(issued.Token.ExpiresAt)))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(RefreshResponse{
+		Message:   "Token refreshed",
+		Token:     issued.Secret,
+		ExpiresAt: issued.Token.ExpiresAt.Time,
+	})
+}
+
+// Me handles GET /api/v1/me
+// Returns the authenticated user resolved by the auth middleware.
+// For a signed token only the ID, name, email and role are set, as of login.
+func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
+	user, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(user)
+}
+
+// CreateToken handles POST /api/v1/users/{id}/tokens
+// Only the user themselves or an admin may create tokens for a user.
+// The plaintext token is returned once; only its hash is stored.
+func (h *AuthHandler) CreateToken(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid user ID")
+		return
+	}
+
+	if !canManageTokens(r, userID) {
+		writeError(w, http.StatusForbidden, CodeForbidden, "Cannot manage tokens of another user")
+		return
+	}
+
+	var req CreateTokenRequest
+	// The body is optional
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeDecodeError(w, err)
+		return
+	}
+
+	var expiresAt pgtype.Timestamp
+	if req.ExpiresAt != nil {
+		expiresAt = pgtype.Timestamp{Time: time.Unix(*req.ExpiresAt, 0), Valid: true}
+		if !expiresAt.Time.After(time.Now()) {
+			writeError(w, http.StatusBadRequest, CodeInvalidRequest, "expires_at must be in the future")
+			return
+		}
+	}
+
+	issued, err := h.usecase.CreateToken(r.Context(), userID, expiresAt)
+	if errors.Is(err, usecase.ErrUserNotFound) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "User not found")
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error creating token", "error", err)
+		writeError(w, http.StatusInternalServerError, CodeInternal, "Internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(CreateTokenResponse{
+		TokenResponse: toTokenResponse(issued.Token),
+		Token:         issued.Secret,
 	})
 }
 
+// SetPassword handles POST /api/v1/users/{id}/password
+// Only the user themselves or an admin may set a user's password. Users changing their own
+// existing password must send it as current_password; admins resetting another user's do not.
+func (h *AuthHandler) SetPassword(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid user ID")
+		return
+	}
+
+	caller, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || (caller.ID != userID && !usecase.HasRole(caller.Role, usecase.UserRoleAdmin)) {
+		writeError(w, http.StatusForbidden, CodeForbidden, "Cannot set the password of another user")
+		return
+	}
+
+	var req SetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	var currentPassword *string
+	if caller.ID == userID {
+		currentPassword = &req.CurrentPassword
+	}
+	err = h.usecase.SetPassword(r.Context(), userID, req.Password, currentPassword)
+	var validationErr *usecase.ValidationError
+	if errors.As(err, &validationErr) {
+		writeValidationError(w, validationErr)
+		return
+	}
+	if errors.Is(err, usecase.ErrInvalidCredentials) {
+		writeError(w, http.StatusForbidden, CodeForbidden, "Current password is incorrect")
+		return
+	}
+	if errors.Is(err, usecase.ErrUserNotFound) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "User not found")
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error setting password", "error", err)
+		writeError(w, http.StatusInternalServerError, CodeInternal, "Internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListTokens handles GET /api/v1/users/{id}/tokens
+// Only the user themselves or an admin may list a user's tokens.
+func (h *AuthHandler) ListTokens(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid user ID")
+		return
+	}
+
+	if !canManageTokens(r, userID) {
+		writeError(w, http.StatusForbidden, CodeForbidden, "Cannot manage tokens of another user")
+		return
+	}
+
+	tokens, err := h.usecase.ListTokens(r.Context(), userID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error listing tokens", "error", err)
+		writeError(w, http.StatusInternalServerError, CodeInternal, "Internal server error")
+		return
+	}
+
+	items := make([]TokenResponse, 0, len(tokens))
+	for _, token := range tokens {
+		items = append(items, toTokenResponse(token))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(ListTokensResponse{Items: items})
+}
+
+// RevokeToken handles DELETE /api/v1/users/{id}/tokens/{tokenId}
+// Only the user themselves or an admin may revoke a user's tokens.
+func (h *AuthHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid user ID")
+		return
+	}
+	tokenID, err := strconv.ParseInt(r.PathValue("tokenId"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid token ID")
+		return
+	}
+
+	if !canManageTokens(r, userID) {
+		writeError(w, http.StatusForbidden, CodeForbidden, "Cannot manage tokens of another user")
+		return
+	}
+
+	err = h.usecase.RevokeToken(r.Context(), userID, tokenID)
+	if errors.Is(err, usecase.ErrTokenNotFound) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "Token not found")
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error revoking token", "error", err)
+		writeError(w, http.StatusInternalServerError, CodeInternal, "Internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// toTokenResponse converts a stored token into its public metadata
+func toTokenResponse(token db.AccessToken) TokenResponse {
+	resp := TokenResponse{
+		ID:        token.ID,
+		CreatedAt: token.CreatedAt.Time,
+	}
+	if token.ExpiresAt.Valid {
+		resp.ExpiresAt = &token.ExpiresAt.Time
+	}
+	if token.LastUsedAt.Valid {
+		resp.LastUsedAt = &token.LastUsedAt.Time
+	}
+	return resp
+}
+
+// canManageTokens reports whether the authenticated caller may manage the tokens of userID
+func canManageTokens(r *http.Request, userID int64) bool {
+	caller, ok := middleware.GetUserFromContext(r.Context())
+	if !ok {
+		return false
+	}
+	return caller.ID == userID || usecase.HasRole(caller.Role, usecase.UserRoleAdmin)
+}
+
+// maxAgeFor returns the cookie MaxAge in seconds for a token expiring at expiresAt.
+// Tokens without an expiry fall back to c.MaxAge.
+func (c CookieConfig) maxAgeFor(expiresAt pgtype.Timestamp) int {
+	if !expiresAt.Valid {
+		return c.MaxAge
+	}
+	// MaxAge 0 would mean a session cookie, so keep at least one second
+	return max(int(time.Until(expiresAt.Time).Seconds()), 1)
+}
+
 // Logout handles POST /api/v1/auth/logout
 // It clears the auth cookie
 func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	// Clear the cookie by setting MaxAge to -1
-	cookie := &http.Cookie{
-		Name:     middleware.CookieName,
-		Value:    "",
-		Path:     "/",
-		MaxAge:   -1,
-		HttpOnly: true,
-		Secure:   true,
-		SameSite: http.SameSiteStrictMode,
-	}
-	http.SetCookie(w, cookie)
+	http.SetCookie(w, h.cookies.authCookie("", -1))
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/para7/nanaket-cms/internal/usecase"
+)
+
+// BackupHandler handles HTTP requests for exporting and importing content
+type BackupHandler struct {
+	usecase usecase.BackupUsecase
+}
+
+// NewBackupHandler creates a new instance of BackupHandler
+func NewBackupHandler(usecase usecase.BackupUsecase) *BackupHandler {
+	return &BackupHandler{
+		usecase: usecase,
+	}
+}
+
+// Export handles GET /api/v1/admin/export
+// Returns every user and article, including soft-deleted ones, as one JSON document.
+func (h *BackupHandler) Export(w http.ResponseWriter, r *http.Request) {
+	doc, err := h.usecase.Export(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to export: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(doc)
+}
+
+// Import handles POST /api/v1/admin/import
+// Imports a document produced by Export in a single transaction. With ?preserve_ids=true
+// every record keeps its ID; otherwise new IDs are assigned and returned as a mapping.
+// Conflicts with existing data and missing references are all reported at once (409).
+// The body has already been checked against api/backup.schema.json by middleware.ValidateJSONBody.
+func (h *BackupHandler) Import(w http.ResponseWriter, r *http.Request) {
+	var doc usecase.BackupDocument
+	if err := json.NewDecoder(r.Body).Decode(&doc); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	preserveIDs := r.URL.Query().Get("preserve_ids") == "true"
+	result, err := h.usecase.Import(r.Context(), doc, preserveIDs)
+	var validationErr *usecase.ValidationError
+	if errors.As(err, &validationErr) {
+		writeValidationError(w, validationErr)
+		return
+	}
+	var conflictErr *usecase.ImportConflictError
+	if errors.As(err, &conflictErr) {
+		writeImportConflictError(w, conflictErr)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to import: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/para7/nanaket-cms/internal/usecase"
+)
+
+// CategoryHandler handles HTTP requests for category operations
+type CategoryHandler struct {
+	usecase        usecase.CategoryUsecase
+	articleUsecase usecase.ArticleUsecase
+	pages          PageSizeConfig
+}
+
+// NewCategoryHandler creates a new instance of CategoryHandler.
+// articleUsecase lists the articles in a category.
+func NewCategoryHandler(usecase usecase.CategoryUsecase, articleUsecase usecase.ArticleUsecase, pages PageSizeConfig) *CategoryHandler {
+	return &CategoryHandler{
+		usecase:        usecase,
+		articleUsecase: articleUsecase,
+		pages:          pages,
+	}
+}
+
+// CategoryRequest represents the request body for creating or updating a category
+type CategoryRequest struct {
+	Name     string `json:"name"`
+	Slug     string `json:"slug,omitempty"`      // derived from the name when omitted
+	ParentID *int64 `json:"parent_id,omitempty"` // parent category ID (nullable)
+}
+
+// CreateCategory handles POST /api/v1/categories
+func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	var req CategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	category, err := h.usecase.CreateCategory(r.Context(), usecase.CategoryInput{
+		Name:     req.Name,
+		Slug:     req.Slug,
+		ParentID: req.ParentID,
+	})
+	if writeCategoryError(w, err) {
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to create category: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(category)
+}
+
+// GetCategory handles GET /api/v1/categories/{id}
+func (h *CategoryHandler) GetCategory(w http.ResponseWriter, r *http.Request) {
+	id, ok := categoryID(w, r)
+	if !ok {
+		return
+	}
+
+	category, err := h.usecase.GetCategory(r.Context(), id)
+	if writeCategoryError(w, err) {
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to get category: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(category)
+}
+
+// ListCategories handles GET /api/v1/categories
+// Returns every category ordered by ID; clients build the tree from parent_id.
+func (h *CategoryHandler) ListCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.usecase.ListCategories(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to list categories: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(categories)
+}
+
+// UpdateCategory handles PUT /api/v1/categories/{id}
+// An omitted slug keeps the current one; an omitted parent_id makes the category top-level.
+func (h *CategoryHandler) UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	id, ok := categoryID(w, r)
+	if !ok {
+		return
+	}
+
+	var req CategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	category, err := h.usecase.UpdateCategory(r.Context(), id, usecase.CategoryInput{
+		Name:     req.Name,
+		Slug:     req.Slug,
+		ParentID: req.ParentID,
+	})
+	if writeCategoryError(w, err) {
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to update category: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(category)
+}
+
+// DeleteCategory handles DELETE /api/v1/categories/{id}
+// A category with subcategories or articles cannot be deleted (409).
+func (h *CategoryHandler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	id, ok := categoryID(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.usecase.DeleteCategory(r.Context(), id)
+	if writeCategoryError(w, err) {
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to delete category: %v", err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListCategoryArticles handles GET /api/v1/categories/{id}/articles
+// Lists the published articles in the category and all of its subcategories.
+// Accepts the same ?limit=, ?cursor= and ?sort= as ListArticles.
+func (h *CategoryHandler) ListCategoryArticles(w http.ResponseWriter, r *http.Request) {
+	id, ok := categoryID(w, r)
+	if !ok {
+		return
+	}
+
+	sort := r.URL.Query().Get("sort")
+	if sort == "" {
+		sort = usecase.DefaultArticleSort
+	}
+	if !usecase.IsValidArticleSort(sort) {
+		writeError(w, http.StatusBadRequest, CodeInvalidSort, "Invalid sort")
+		return
+	}
+
+	limit, after, ok := parseArticlePage(w, r, h.pages, sort)
+	if !ok {
+		return
+	}
+
+	page, err := h.articleUsecase.ListArticlesPaginated(r.Context(), usecase.ArticleListQuery{
+		Status:     usecase.ArticleStatusPublished,
+		CategoryID: id,
+		Sort:       sort,
+		Limit:      limit,
+		After:      after,
+	})
+	if writeCategoryError(w, err) {
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to list articles: %v", err))
+		return
+	}
+
+	resp := ListArticlesResponse{Items: page.Items}
+	if page.Next != nil {
+		resp.NextCursor = encodeArticleCursor(sort, *page.Next)
+	}
+
+	setPaginationHeaders(w, r, page.Total, articleCursorLinks(sort, page.Next)...)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// categoryID parses the {id} path value.
+// On invalid input it writes an error response and returns ok == false.
+func categoryID(w http.ResponseWriter, r *http.Request) (id int64, ok bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid category ID")
+		return 0, false
+	}
+	return id, true
+}
+
+// writeCategoryError writes the response for the category errors of the usecase layer
+// and reports whether err was one of them
+func writeCategoryError(w http.ResponseWriter, err error) bool {
+	var validationErr *usecase.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		writeValidationError(w, validationErr)
+	case errors.Is(err, usecase.ErrCategoryNotFound):
+		writeError(w, http.StatusNotFound, CodeNotFound, "Category not found")
+	case errors.Is(err, usecase.ErrCategorySlugTaken):
+		writeError(w, http.StatusConflict, CodeSlugTaken, "Slug is already taken")
+	case errors.Is(err, usecase.ErrCategoryInUse):
+		writeError(w, http.StatusConflict, CodeCategoryInUse, "Category still has subcategories or articles")
+	default:
+		return false
+	}
+	return true
+}
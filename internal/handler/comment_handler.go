@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/para7/nanaket-cms/internal/db"
+	"github.com/para7/nanaket-cms/internal/middleware"
+	"github.com/para7/nanaket-cms/internal/usecase"
+)
+
+// CommentHandler handles HTTP requests for comment operations
+type CommentHandler struct {
+	usecase usecase.CommentUsecase
+	pages   PageSizeConfig
+}
+
+// NewCommentHandler creates a new instance of CommentHandler
+func NewCommentHandler(usecase usecase.CommentUsecase, pages PageSizeConfig) *CommentHandler {
+	return &CommentHandler{
+		usecase: usecase,
+		pages:   pages,
+	}
+}
+
+// CreateCommentRequest represents the request body for creating a comment
+type CreateCommentRequest struct {
+	AuthorName string `json:"author_name,omitempty"` // required unless authenticated
+	Content    string `json:"content"`
+}
+
+// ListCommentsResponse represents the response body for listing comments
+type ListCommentsResponse struct {
+	Items      []db.Comment `json:"items"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+}
+
+// CreateComment handles POST /api/v1/articles/{id}/comments
+// Authenticated callers comment as themselves; guests must give author_name.
+func (h *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	articleID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid article ID")
+		return
+	}
+
+	var req CreateCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	in := usecase.CommentInput{
+		ArticleID:  articleID,
+		AuthorName: req.AuthorName,
+		Content:    req.Content,
+	}
+	if user, ok := middleware.GetUserFromContext(r.Context()); ok {
+		in.UserID = &user.ID
+	}
+
+	comment, err := h.usecase.CreateComment(r.Context(), in)
+	var validationErr *usecase.ValidationError
+	if errors.As(err, &validationErr) {
+		writeValidationError(w, validationErr)
+		return
+	}
+	if errors.Is(err, usecase.ErrArticleNotFound) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "Article not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to create comment: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(comment)
+}
+
+// ListComments handles GET /api/v1/articles/{id}/comments
+// Supports cursor-based pagination via ?limit=20&cursor=<opaque>, newest first.
+func (h *CommentHandler) ListComments(w http.ResponseWriter, r *http.Request) {
+	articleID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid article ID")
+		return
+	}
+
+	limit, cursor, ok := parseCursorPage(w, r, h.pages)
+	if !ok {
+		return
+	}
+
+	page, err := h.usecase.ListComments(r.Context(), articleID, limit, cursor)
+	if errors.Is(err, usecase.ErrArticleNotFound) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "Article not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to list comments: %v", err))
+		return
+	}
+
+	resp := ListCommentsResponse{Items: page.Items}
+	if page.NextCursor != 0 {
+		resp.NextCursor = encodeCursor(page.NextCursor)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
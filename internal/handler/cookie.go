@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/para7/nanaket-cms/internal/config"
+	"github.com/para7/nanaket-cms/internal/middleware"
+)
+
+// ErrSameSiteNoneInsecure is returned when SameSite=None is configured without Secure,
+// which browsers reject
+var ErrSameSiteNoneInsecure = errors.New("cookie: SameSite=None requires Secure")
+
+// defaultCookieMaxAge is the cookie lifetime in seconds for tokens without an expiry (7 days)
+const defaultCookieMaxAge = 60 * 60 * 24 * 7
+
+// CookieConfig holds the attributes of the auth cookie
+type CookieConfig struct {
+	Secure   bool
+	SameSite http.SameSite
+	// Domain is empty for a host-only cookie
+	Domain string
+	// MaxAge is the lifetime in seconds of cookies holding tokens without an expiry
+	MaxAge int
+}
+
+// DefaultCookieConfig returns the production settings: Secure, SameSite=Strict, host-only and 7 days
+func DefaultCookieConfig() CookieConfig {
+	return CookieConfig{
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+		MaxAge:   defaultCookieMaxAge,
+	}
+}
+
+// NewCookieConfig builds a CookieConfig from the cookie settings of the configuration.
+// SameSite is "strict", "lax" or "none" (case-insensitive); empty means strict.
+func NewCookieConfig(c config.Cookie) (CookieConfig, error) {
+	cfg := DefaultCookieConfig()
+	cfg.Secure = c.Secure
+	cfg.Domain = c.Domain
+	if c.MaxAge > 0 {
+		cfg.MaxAge = int(c.MaxAge.Seconds())
+	}
+
+	switch strings.ToLower(c.SameSite) {
+	case "", "strict":
+		cfg.SameSite = http.SameSiteStrictMode
+	case "lax":
+		cfg.SameSite = http.SameSiteLaxMode
+	case "none":
+		if !c.Secure {
+			return CookieConfig{}, ErrSameSiteNoneInsecure
+		}
+		cfg.SameSite = http.SameSiteNoneMode
+	default:
+		return CookieConfig{}, fmt.Errorf("cookie: unknown SameSite mode %q", c.SameSite)
+	}
+	return cfg, nil
+}
+
+// authCookie builds the auth cookie holding value.
+// Login, refresh and logout all use it: a clearing cookie only replaces the
+// original when its name, path and domain match.
+func (c CookieConfig) authCookie(value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     middleware.CookieName,
+		Value:    value,
+		Path:     "/",
+		Domain:   c.Domain,
+		MaxAge:   maxAge,
+		HttpOnly: true, // Prevent JavaScript access (XSS protection)
+		Secure:   c.Secure,
+		SameSite: c.SameSite, // CSRF protection
+	}
+}
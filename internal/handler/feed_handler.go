@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/para7/nanaket-cms/internal/repository"
+	"github.com/para7/nanaket-cms/internal/usecase"
+)
+
+const (
+	// feedItemLimit is the number of articles included in the feed
+	feedItemLimit = 20
+	// feedDescriptionLength is the maximum length (in runes) of an item description
+	feedDescriptionLength = 200
+	// feedTitle is the channel title
+	feedTitle = "Nanaket CMS"
+)
+
+// FeedHandler handles HTTP requests for syndication feeds
+type FeedHandler struct {
+	usecase usecase.ArticleUsecase
+	baseURL string
+}
+
+// NewFeedHandler creates a new instance of FeedHandler.
+// baseURL is the public site URL that article links are built from.
+func NewFeedHandler(usecase usecase.ArticleUsecase, baseURL string) *FeedHandler {
+	return &FeedHandler{
+		usecase: usecase,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// rss is the root element of an RSS 2.0 document
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+// rssChannel describes the feed
+type rssChannel struct {
+	Title       string    `xml:"title"`
+	Link        string    `xml:"link"`
+	Description string    `xml:"description"`
+	Items       []rssItem `xml:"item"`
+}
+
+// rssItem describes a single article in the feed
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description"`
+}
+
+// rssGUID is the unique identifier of an item
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+// ArticlesFeed handles GET /api/v1/articles/feed.xml
+// Returns an RSS 2.0 feed of the latest published articles.
+func (h *FeedHandler) ArticlesFeed(w http.ResponseWriter, r *http.Request) {
+	page, err := h.usecase.ListArticlesPaginated(r.Context(), usecase.ArticleListQuery{
+		Status: usecase.ArticleStatusPublished,
+		Sort:   repository.ArticleSortPublishedAtDesc,
+		Limit:  feedItemLimit,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to list articles: %v", err))
+		return
+	}
+
+	feed := rss{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:       feedTitle,
+			Link:        h.baseURL + "/",
+			Description: "Latest articles from " + feedTitle,
+			Items:       make([]rssItem, 0, len(page.Items)),
+		},
+	}
+	for _, article := range page.Items {
+		link := h.articleURL(article)
+		pubDate := article.CreatedAt.Time
+		if article.PublishedAt.Valid {
+			pubDate = article.PublishedAt.Time
+		}
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:       article.Title,
+			Link:        link,
+			GUID:        rssGUID{Value: link, IsPermaLink: true},
+			PubDate:     pubDate.UTC().Format(time.RFC1123Z),
+			Description: excerpt(article.Content, feedDescriptionLength),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(xml.Header))
+	_ = xml.NewEncoder(w).Encode(feed)
+}
+
+// articleURL returns the public URL of an article, preferring its slug
+func (h *FeedHandler) articleURL(article usecase.Article) string {
+	if article.Slug != nil {
+		return h.baseURL + "/articles/" + *article.Slug
+	}
+	return h.baseURL + "/articles/" + strconv.FormatInt(article.ID, 10)
+}
+
+// excerpt returns s cut to at most n runes, with "..." appended when truncated
+func excerpt(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:n]) + "..."
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/para7/nanaket-cms/internal/usecase"
+)
+
+// MagicLinkHandler handles HTTP requests for passwordless login by emailed link
+type MagicLinkHandler struct {
+	usecase usecase.MagicLinkUsecase
+	cookies CookieConfig
+}
+
+// NewMagicLinkHandler creates a new instance of MagicLinkHandler.
+// cookies sets the attributes of the auth cookie.
+func NewMagicLinkHandler(usecase usecase.MagicLinkUsecase, cookies CookieConfig) *MagicLinkHandler {
+	return &MagicLinkHandler{
+		usecase: usecase,
+		cookies: cookies,
+	}
+}
+
+// MagicLinkRequest represents the request body for requesting a login link
+type MagicLinkRequest struct {
+	Email string `json:"email"`
+}
+
+// RequestLink handles POST /api/v1/auth/magic-link
+// It emails a single-use login link. The response is the same whether or not
+// the email is registered, so it cannot be used to discover accounts.
+func (h *MagicLinkHandler) RequestLink(w http.ResponseWriter, r *http.Request) {
+	var req MagicLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	if req.Email == "" {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Email is required")
+		return
+	}
+
+	if err := h.usecase.RequestLink(r.Context(), req.Email); err != nil {
+		slog.ErrorContext(r.Context(), "Error sending login link", "error", err)
+		writeError(w, http.StatusInternalServerError, CodeInternal, "Internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"message": "If the email is registered, a login link has been sent",
+	})
+}
+
+// Verify handles GET /api/v1/auth/magic-link/verify?token=...
+// It consumes the link, issues a new token and sets it as the auth cookie.
+func (h *MagicLinkHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Token is required")
+		return
+	}
+
+	session, err := h.usecase.Verify(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidToken) {
+			writeError(w, http.StatusUnauthorized, CodeUnauthorized, "Invalid, expired or already used link")
+			return
+		}
+		slog.ErrorContext(r.Context(), "Error verifying login link", "error", err)
+		writeError(w, http.StatusInternalServerError, CodeInternal, "Internal server error")
+		return
+	}
+
+	http.SetCookie(w, h.cookies.authCookie(session.Token.Secret, h.cookies.maxAgeFor(session.Token.Token.ExpiresAt)))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(LoginResponse{
+		Message:   "Login successful",
+		User:      session.User,
+		Token:     session.Token.Secret,
+		ExpiresAt: &session.Token.Token.ExpiresAt.Time,
+	})
+}
+
+// ConfirmEmailChange handles GET /api/v1/auth/email-change/verify?token=...
+// It consumes the link sent to a new email address and makes it the user's email.
+func (h *MagicLinkHandler) ConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Token is required")
+		return
+	}
+
+	user, err := h.usecase.ConfirmEmailChange(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidToken) {
+			writeError(w, http.StatusUnauthorized, CodeUnauthorized, "Invalid, expired or already used link")
+			return
+		}
+		if errors.Is(err, usecase.ErrEmailTaken) {
+			writeError(w, http.StatusConflict, CodeEmailTaken, "Email is already taken")
+			return
+		}
+		slog.ErrorContext(r.Context(), "Error confirming email change", "error", err)
+		writeError(w, http.StatusInternalServerError, CodeInternal, "Internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(user)
+}
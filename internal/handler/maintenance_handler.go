@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/para7/nanaket-cms/internal/usecase"
+)
+
+// MaintenanceHandler handles HTTP requests for the maintenance mode
+type MaintenanceHandler struct {
+	usecase usecase.MaintenanceUsecase
+}
+
+// NewMaintenanceHandler creates a new instance of MaintenanceHandler
+func NewMaintenanceHandler(usecase usecase.MaintenanceUsecase) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		usecase: usecase,
+	}
+}
+
+// MaintenanceRequest represents the request body for switching the maintenance mode
+// and the response of both maintenance endpoints
+type MaintenanceRequest struct {
+	Mode string `json:"mode"` // off, read_only or down
+}
+
+// GetMaintenance handles GET /api/v1/admin/maintenance
+// The mode is read from the database, not from the cache the middleware uses.
+func (h *MaintenanceHandler) GetMaintenance(w http.ResponseWriter, r *http.Request) {
+	mode, err := h.usecase.Mode(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to get maintenance mode: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(MaintenanceRequest{Mode: mode})
+}
+
+// SetMaintenance handles PUT /api/v1/admin/maintenance
+// Other server instances pick up the new mode once their cached value expires.
+func (h *MaintenanceHandler) SetMaintenance(w http.ResponseWriter, r *http.Request) {
+	var req MaintenanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	mode, err := h.usecase.SetMode(r.Context(), req.Mode)
+	var validationErr *usecase.ValidationError
+	if errors.As(err, &validationErr) {
+		writeValidationError(w, validationErr)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to set maintenance mode: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(MaintenanceRequest{Mode: mode})
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/para7/nanaket-cms/internal/middleware"
+	"github.com/para7/nanaket-cms/internal/usecase"
+)
+
+const (
+	// mediaFormMaxMemory is how much of a multipart upload is kept in memory before spilling to disk
+	mediaFormMaxMemory = 1 << 20
+)
+
+// MediaHandler handles HTTP requests for media operations
+type MediaHandler struct {
+	usecase usecase.MediaUsecase
+	pages   PageSizeConfig
+}
+
+// NewMediaHandler creates a new instance of MediaHandler
+func NewMediaHandler(usecase usecase.MediaUsecase, pages PageSizeConfig) *MediaHandler {
+	return &MediaHandler{
+		usecase: usecase,
+		pages:   pages,
+	}
+}
+
+// ListMediaResponse represents the response body for listing media files
+type ListMediaResponse struct {
+	Items      []usecase.Media `json:"items"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+// UploadMedia handles POST /api/v1/media
+// Expects a multipart/form-data body with the image in the "file" field.
+func (h *MediaHandler) UploadMedia(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(mediaFormMaxMemory); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	defer func() { _ = r.MultipartForm.RemoveAll() }()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "File is required")
+		return
+	}
+	defer file.Close()
+
+	upload := usecase.MediaUpload{Filename: header.Filename, Content: file}
+	if user, ok := middleware.GetUserFromContext(r.Context()); ok {
+		upload.UserID = &user.ID
+	}
+
+	media, err := h.usecase.UploadMedia(r.Context(), upload)
+	var validationErr *usecase.ValidationError
+	if errors.As(err, &validationErr) {
+		writeValidationError(w, validationErr)
+		return
+	}
+	if errors.Is(err, usecase.ErrMediaTooLarge) {
+		writeError(w, http.StatusRequestEntityTooLarge, CodeRequestTooLarge, "File is too large")
+		return
+	}
+	if errors.Is(err, usecase.ErrUnsupportedMediaType) {
+		writeError(w, http.StatusUnsupportedMediaType, CodeUnsupportedMediaType, "Only JPEG, PNG, GIF and WebP images are allowed")
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error uploading media", "error", err)
+		writeError(w, http.StatusInternalServerError, CodeInternal, "Failed to upload media")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(media)
+}
+
+// ListMedia handles GET /api/v1/media
+// Supports cursor-based pagination via ?limit=20&cursor=<opaque>, newest first.
+func (h *MediaHandler) ListMedia(w http.ResponseWriter, r *http.Request) {
+	limit, cursor, ok := parseCursorPage(w, r, h.pages)
+	if !ok {
+		return
+	}
+
+	page, err := h.usecase.ListMedia(r.Context(), limit, cursor)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to list media: %v", err))
+		return
+	}
+
+	resp := ListMediaResponse{Items: page.Items}
+	if page.NextCursor != 0 {
+		resp.NextCursor = encodeCursor(page.NextCursor)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// DeleteMedia handles DELETE /api/v1/media/{id}
+// Removes both the stored file and its record.
+// Media still used as an article's featured image is not deleted (409).
+func (h *MediaHandler) DeleteMedia(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid media ID")
+		return
+	}
+
+	err = h.usecase.DeleteMedia(r.Context(), id)
+	if errors.Is(err, usecase.ErrMediaNotFound) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "Media not found")
+		return
+	}
+	if errors.Is(err, usecase.ErrMediaInUse) {
+		writeError(w, http.StatusConflict, CodeMediaInUse, "Media is used as a featured image")
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error deleting media", "error", err)
+		writeError(w, http.StatusInternalServerError, CodeInternal, "Failed to delete media")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/para7/nanaket-cms/internal/db"
+	"github.com/para7/nanaket-cms/internal/db/mock"
+	"github.com/para7/nanaket-cms/internal/middleware"
+	"github.com/para7/nanaket-cms/internal/repository"
+	"github.com/para7/nanaket-cms/internal/usecase"
+)
+
+// objectStore records the keys it is asked to delete
+type objectStore struct {
+	deleted []string
+}
+
+func (s *objectStore) Put(ctx context.Context, key, contentType string, r io.Reader) error {
+	return nil
+}
+
+func (s *objectStore) Delete(ctx context.Context, key string) error {
+	s.deleted = append(s.deleted, key)
+	return nil
+}
+
+func (s *objectStore) URL(key string) string {
+	return "https://media.example.com/" + key
+}
+
+func TestDeleteMediaReferenced(t *testing.T) {
+	media := db.MediaFile{ID: 1, Filename: "cover.png", ObjectKey: "cover.png", ContentType: "image/png"}
+
+	tests := []struct {
+		name       string
+		referenced bool
+		status     int
+		deleted    []string
+	}{
+		{"featured image", true, http.StatusConflict, nil},
+		{"unreferenced", false, http.StatusNoContent, []string{"cover.png"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &mock.Querier{
+				GetMediaFileFunc: func(ctx context.Context, id int64) (db.MediaFile, error) {
+					if id != media.ID {
+						return db.MediaFile{}, pgx.ErrNoRows
+					}
+					return media, nil
+				},
+				DeleteMediaFileFunc: func(ctx context.Context, id int64) (int64, error) {
+					if tt.referenced {
+						// articles.featured_image_id references the file without ON DELETE
+						return 0, &pgconn.PgError{Code: "23503"}
+					}
+					return 1, nil
+				},
+			}
+			store := &objectStore{}
+			h := NewMediaHandler(usecase.NewMediaUsecase(repository.NewMediaRepository(q), store, 1<<20), PageSizeConfig{})
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/media/1", nil)
+			req.SetPathValue("id", "1")
+			rec := httptest.NewRecorder()
+			h.DeleteMedia(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if tt.referenced {
+				if code := errorCode(t, rec); code != CodeMediaInUse {
+					t.Errorf("code = %q, want %q", code, CodeMediaInUse)
+				}
+			}
+			if !slices.Equal(store.deleted, tt.deleted) {
+				t.Errorf("deleted objects %v, want %v", store.deleted, tt.deleted)
+			}
+		})
+	}
+}
+
+func TestDeleteMediaTimeout(t *testing.T) {
+	// The repository sleeps past the deadline, then fails like a canceled query
+	repo := &mock.MediaRepository{
+		GetByIDFunc: func(ctx context.Context, id int64) (db.MediaFile, error) {
+			time.Sleep(50 * time.Millisecond)
+			return db.MediaFile{}, ctx.Err()
+		},
+	}
+	h := NewMediaHandler(usecase.NewMediaUsecase(repo, &objectStore{}, 1<<20), PageSizeConfig{})
+	handler := middleware.Timeout(10 * time.Millisecond)(http.HandlerFunc(h.DeleteMedia))
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/media/1", nil)
+	req.SetPathValue("id", "1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusGatewayTimeout)
+	}
+	if code := errorCode(t, rec); code != "timeout" {
+		t.Errorf("code = %q, want timeout", code)
+	}
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/para7/nanaket-cms/internal/metrics"
+)
+
+// MetricsHandler handles HTTP requests for request metrics
+type MetricsHandler struct {
+	registry *metrics.Registry
+}
+
+// NewMetricsHandler creates a new instance of MetricsHandler
+func NewMetricsHandler(registry *metrics.Registry) *MetricsHandler {
+	return &MetricsHandler{
+		registry: registry,
+	}
+}
+
+// GetMetrics handles GET /api/v1/admin/metrics
+// Returns request and error counts and latency percentiles per route since the server started.
+// The counters live in memory, so they are reset on restart and are per server instance.
+func (h *MetricsHandler) GetMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(h.registry.Snapshot())
+}
@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/para7/nanaket-cms/internal/repository"
+)
+
+// Page sizes used when none are configured
+const (
+	// DefaultPageSize is the page size used when the client gives none
+	DefaultPageSize = 20
+	// DefaultMaxPageSize is the largest page size a client may request
+	DefaultMaxPageSize = 100
+)
+
+// maxPageSizeLimit bounds the configurable maximum page size
+const maxPageSizeLimit = 1000
+
+// PageSizeConfig holds the page sizes of the list endpoints
+type PageSizeConfig struct {
+	// Default is used when the client gives no limit or per_page
+	Default int
+	// Max caps the limit or per_page a client may request
+	Max int
+}
+
+// DefaultPageSizeConfig returns the page sizes used when none are configured
+func DefaultPageSizeConfig() PageSizeConfig {
+	return PageSizeConfig{
+		Default: DefaultPageSize,
+		Max:     DefaultMaxPageSize,
+	}
+}
+
+// NewPageSizeConfig builds a PageSizeConfig, checking that both sizes are positive,
+// that defaultSize does not exceed maxSize and that maxSize is at most 1000
+func NewPageSizeConfig(defaultSize, maxSize int) (PageSizeConfig, error) {
+	if defaultSize < 1 || maxSize < 1 {
+		return PageSizeConfig{}, errors.New("page size: sizes must be positive")
+	}
+	if maxSize > maxPageSizeLimit {
+		return PageSizeConfig{}, fmt.Errorf("page size: maximum must be at most %d", maxPageSizeLimit)
+	}
+	if defaultSize > maxSize {
+		return PageSizeConfig{}, fmt.Errorf("page size: default %d exceeds maximum %d", defaultSize, maxSize)
+	}
+	return PageSizeConfig{Default: defaultSize, Max: maxSize}, nil
+}
+
+// parseCursorPage reads ?limit= and ?cursor= from the request.
+// The limit defaults to pages.Default and is capped at pages.Max.
+// On invalid input it writes a 400 response and returns ok == false.
+func parseCursorPage(w http.ResponseWriter, r *http.Request, pages PageSizeConfig) (limit int32, cursor int64, ok bool) {
+	limit, ok = parseLimit(w, r, pages)
+	if !ok {
+		return 0, 0, false
+	}
+
+	cursor, err := decodeCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid cursor")
+		return 0, 0, false
+	}
+	return limit, cursor, true
+}
+
+// parseArticlePage reads ?limit= and ?cursor= like parseCursorPage, but decodes the cursor
+// as a position in the article sort order sort.
+// On invalid input it writes a 400 response and returns ok == false.
+func parseArticlePage(w http.ResponseWriter, r *http.Request, pages PageSizeConfig, sort string) (limit int32, after *repository.ArticleCursor, ok bool) {
+	limit, ok = parseLimit(w, r, pages)
+	if !ok {
+		return 0, nil, false
+	}
+
+	after, err := decodeArticleCursor(r.URL.Query().Get("cursor"), sort)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid cursor")
+		return 0, nil, false
+	}
+	return limit, after, true
+}
+
+// parseLimit reads ?limit=, defaulting to pages.Default and capped at pages.Max.
+// On invalid input it writes a 400 response and returns ok == false.
+func parseLimit(w http.ResponseWriter, r *http.Request, pages PageSizeConfig) (int32, bool) {
+	n := pages.Default
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 {
+			writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid limit")
+			return 0, false
+		}
+		n = min(parsed, pages.Max)
+	}
+	return int32(n), true
+}
+
+// pageLink is one entry of the Link header: the query parameters to change on the
+// current request URL, where an empty value removes the parameter
+type pageLink struct {
+	rel    string
+	params map[string]string
+}
+
+// setPaginationHeaders sets X-Total-Count and an RFC 5988 Link header built from the request URL.
+// Must be called before WriteHeader.
+func setPaginationHeaders(w http.ResponseWriter, r *http.Request, total int64, links ...pageLink) {
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+	if len(links) == 0 {
+		return
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	entries := make([]string, len(links))
+	for i, link := range links {
+		query := r.URL.Query()
+		for key, value := range link.params {
+			if value == "" {
+				query.Del(key)
+			} else {
+				query.Set(key, value)
+			}
+		}
+		u := url.URL{Scheme: scheme, Host: r.Host, Path: r.URL.Path, RawQuery: query.Encode()}
+		entries[i] = "<" + u.String() + `>; rel="` + link.rel + `"`
+	}
+	w.Header().Set("Link", strings.Join(entries, ", "))
+}
+
+// articleCursorLinks returns the next link for an article page. Keyset cursors only lead
+// forward, so there is no prev link; clients go back with a cursor they already have.
+func articleCursorLinks(sort string, next *repository.ArticleCursor) []pageLink {
+	if next == nil {
+		return nil
+	}
+	return []pageLink{{rel: "next", params: map[string]string{"cursor": encodeArticleCursor(sort, *next)}}}
+}
+
+// encodeCursor converts a row ID into an opaque pagination cursor
+func encodeCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+// decodeCursor converts an opaque pagination cursor back into a row ID.
+// An empty cursor decodes to 0, meaning the first page.
+func decodeCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if id <= 0 {
+		return 0, errors.New("cursor out of range")
+	}
+	return id, nil
+}
+
+// articleCursor is the encoded form of a repository.ArticleCursor. It records the sort
+// order it was made for, since the position means nothing in any other order.
+type articleCursor struct {
+	Sort   string `json:"s"`
+	ID     int64  `json:"i"`
+	Pinned bool   `json:"p,omitempty"`
+	// Time is in Unix microseconds, the precision of PostgreSQL timestamps
+	Time  *int64 `json:"t,omitempty"`
+	Title string `json:"k,omitempty"`
+}
+
+// encodeArticleCursor converts a position in the article sort order sort into an opaque cursor
+func encodeArticleCursor(sort string, c repository.ArticleCursor) string {
+	ac := articleCursor{Sort: sort, ID: c.ID, Pinned: c.Pinned, Title: c.Title}
+	if c.Time != nil {
+		micros := c.Time.UnixMicro()
+		ac.Time = &micros
+	}
+	raw, _ := json.Marshal(ac)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeArticleCursor converts an opaque article cursor back into a position in the sort
+// order sort. An empty cursor decodes to nil, meaning the first page; a cursor made for
+// another sort order is rejected.
+func decodeArticleCursor(cursor, sort string) (*repository.ArticleCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+	var ac articleCursor
+	if err := json.Unmarshal(raw, &ac); err != nil {
+		return nil, err
+	}
+	if ac.Sort != sort {
+		return nil, errors.New("cursor belongs to another sort order")
+	}
+	if ac.ID <= 0 {
+		return nil, errors.New("cursor out of range")
+	}
+
+	c := &repository.ArticleCursor{ID: ac.ID, Pinned: ac.Pinned, Title: ac.Title}
+	if ac.Time != nil {
+		t := time.UnixMicro(*ac.Time).UTC()
+		c.Time = &t
+	}
+	return c, nil
+}
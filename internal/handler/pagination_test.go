@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/para7/nanaket-cms/internal/repository"
+)
+
+func TestArticleCursorRoundTrip(t *testing.T) {
+	published := time.Date(2025, 3, 1, 9, 30, 15, 123456000, time.UTC)
+
+	tests := []struct {
+		name string
+		sort string
+		in   repository.ArticleCursor
+	}{
+		{"default order", repository.ArticleSortIDDesc, repository.ArticleCursor{ID: 42}},
+		{"pinned", repository.ArticleSortIDDesc, repository.ArticleCursor{ID: 7, Pinned: true}},
+		{"time key", repository.ArticleSortPublishedAtDesc, repository.ArticleCursor{ID: 42, Time: &published}},
+		{"null time key", repository.ArticleSortPublishedAt, repository.ArticleCursor{ID: 42}},
+		{"title key", repository.ArticleSortTitle, repository.ArticleCursor{ID: 42, Title: "Hello, 世界"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeArticleCursor(encodeArticleCursor(tt.sort, tt.in), tt.sort)
+			if err != nil {
+				t.Fatalf("decodeArticleCursor: %v", err)
+			}
+			if got.ID != tt.in.ID || got.Pinned != tt.in.Pinned || got.Title != tt.in.Title {
+				t.Errorf("got %+v, want %+v", *got, tt.in)
+			}
+			if (got.Time == nil) != (tt.in.Time == nil) || (got.Time != nil && !got.Time.Equal(*tt.in.Time)) {
+				t.Errorf("Time = %v, want %v", got.Time, tt.in.Time)
+			}
+		})
+	}
+}
+
+func TestDecodeArticleCursorRejectsOtherSort(t *testing.T) {
+	cursor := encodeArticleCursor(repository.ArticleSortTitle, repository.ArticleCursor{ID: 42, Title: "a"})
+	if _, err := decodeArticleCursor(cursor, repository.ArticleSortIDDesc); err == nil {
+		t.Error("expected an error for a cursor of another sort order")
+	}
+}
+
+func TestDecodeArticleCursorEmpty(t *testing.T) {
+	got, err := decodeArticleCursor("", repository.ArticleSortIDDesc)
+	if err != nil || got != nil {
+		t.Errorf("got %v, %v; want nil, nil", got, err)
+	}
+}
+
+func TestDecodeArticleCursorInvalid(t *testing.T) {
+	for _, cursor := range []string{"!!!", "bm90IGpzb24", encodeCursor(42)} {
+		if _, err := decodeArticleCursor(cursor, repository.ArticleSortIDDesc); err == nil {
+			t.Errorf("decodeArticleCursor(%q): expected an error", cursor)
+		}
+	}
+}
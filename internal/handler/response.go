@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+
+	"github.com/para7/nanaket-cms/internal/middleware"
+	"github.com/para7/nanaket-cms/internal/usecase"
+)
+
+// Error codes returned in ErrorResponse.Code
+// Clients can branch on these values, so they must stay stable once published.
+const (
+	// CodeInvalidRequest indicates a malformed request or invalid parameters
+	CodeInvalidRequest = "invalid_request"
+	// CodeValidation indicates well-formed input that failed validation; see ErrorResponse.Fields
+	CodeValidation = "validation"
+	// CodeUnauthorized indicates missing or invalid credentials
+	CodeUnauthorized = "unauthorized"
+	// CodeForbidden indicates the caller lacks permission for the operation
+	CodeForbidden = "forbidden"
+	// CodeNotFound indicates the requested resource does not exist
+	CodeNotFound = "not_found"
+	// CodeMethodNotAllowed indicates the path exists but not for the request method
+	CodeMethodNotAllowed = "method_not_allowed"
+	// CodeInvalidSort indicates an unknown sort order
+	CodeInvalidSort = "invalid_sort"
+	// CodeEmptyBody indicates a request without the body the endpoint requires
+	CodeEmptyBody = "empty_body"
+	// CodeMalformedJSON indicates a request body that is not valid JSON
+	CodeMalformedJSON = "malformed_json"
+	// CodeInvalidType indicates a JSON value of the wrong type for its field
+	CodeInvalidType = "invalid_type"
+	// CodeRequestTooLarge indicates the request body exceeds the configured size limit
+	CodeRequestTooLarge = "request_too_large"
+	// CodeUnsupportedMediaType indicates an upload of a file type that is not accepted
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	// CodeMediaInUse indicates the media file is still referenced by an article
+	CodeMediaInUse = "media_in_use"
+	// CodeCategoryInUse indicates the category still has subcategories or articles
+	CodeCategoryInUse = "category_in_use"
+	// CodeSlugTaken indicates the slug is already used by another resource of the same kind
+	CodeSlugTaken = "slug_taken"
+	// CodeVersionConflict indicates the resource was modified since the version the update was based on
+	CodeVersionConflict = "version_conflict"
+	// CodeIdempotencyKeyInUse indicates an earlier request with the same Idempotency-Key is still in progress
+	CodeIdempotencyKeyInUse = "idempotency_key_in_use"
+	// CodeEmailTaken indicates the email is already used by another user
+	CodeEmailTaken = "email_taken"
+	// CodeImportConflict indicates a backup that clashes with existing data; see ErrorResponse.Conflicts
+	CodeImportConflict = "import_conflict"
+	// CodePinLimitReached indicates the maximum number of articles are already pinned
+	CodePinLimitReached = "pin_limit_reached"
+	// CodeAccountLocked indicates logins for the account are locked after repeated failures; see Retry-After
+	CodeAccountLocked = "account_locked"
+	// CodeInternal indicates an unexpected server-side failure
+	CodeInternal = "internal_error"
+)
+
+// ErrorResponse represents an error response
+type ErrorResponse struct {
+	Error     string                   `json:"error"`
+	Code      string                   `json:"code"`
+	Fields    []usecase.FieldError     `json:"fields,omitempty"`     // Every offending field, for validation errors
+	Conflicts []usecase.ImportConflict `json:"conflicts,omitempty"`  // Every conflicting record, for import conflicts
+	RequestID string                   `json:"request_id,omitempty"` // Correlation ID, also sent as X-Request-ID
+}
+
+// writeError writes an ErrorResponse with the given status, code and message
+func writeError(w http.ResponseWriter, status int, code, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(ErrorResponse{Error: msg, Code: code, RequestID: requestID(w)})
+}
+
+// writeDecodeError writes the response for a request body that could not be decoded:
+// 413 when it exceeded the body size limit, 400 with code empty_body when there was no body
+// (the decoder reports io.EOF before reading any value), 400 with code malformed_json and the
+// byte offset for invalid JSON, 400 with code invalid_type naming the field for a value of
+// the wrong type, and 400 otherwise
+func writeDecodeError(w http.ResponseWriter, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		writeError(w, http.StatusRequestEntityTooLarge, CodeRequestTooLarge, fmt.Sprintf("Request body exceeds %d bytes", maxBytesErr.Limit))
+		return
+	}
+	if errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, CodeEmptyBody, "Request body is required")
+		return
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		writeError(w, http.StatusBadRequest, CodeMalformedJSON, fmt.Sprintf("Malformed JSON at byte %d: %s", syntaxErr.Offset, syntaxErr.Error()))
+		return
+	}
+	// The decoder reports a body cut off in the middle of a value as io.ErrUnexpectedEOF
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		writeError(w, http.StatusBadRequest, CodeMalformedJSON, "Malformed JSON: request body ends unexpectedly")
+		return
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		if typeErr.Field == "" {
+			writeError(w, http.StatusBadRequest, CodeInvalidType, fmt.Sprintf("Request body must be %s, got %s", jsonTypeName(typeErr.Type), typeErr.Value))
+			return
+		}
+		writeError(w, http.StatusBadRequest, CodeInvalidType, fmt.Sprintf("Field %q must be %s, got %s", typeErr.Field, jsonTypeName(typeErr.Type), typeErr.Value))
+		return
+	}
+	writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid request body")
+}
+
+// jsonTypeName describes the JSON value a Go type is decoded from, e.g. "an integer"
+func jsonTypeName(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	default:
+		return "a " + t.String()
+	}
+}
+
+// writeValidationError writes a 422 ErrorResponse listing every field that failed validation
+func writeValidationError(w http.ResponseWriter, err *usecase.ValidationError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	_ = json.NewEncoder(w).Encode(ErrorResponse{
+		Error:     "validation failed",
+		Code:      CodeValidation,
+		Fields:    err.Fields,
+		RequestID: requestID(w),
+	})
+}
+
+// writeImportConflictError writes a 409 ErrorResponse listing every conflicting record of an import
+func writeImportConflictError(w http.ResponseWriter, err *usecase.ImportConflictError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusConflict)
+	_ = json.NewEncoder(w).Encode(ErrorResponse{
+		Error:     "import conflicts with existing data",
+		Code:      CodeImportConflict,
+		Conflicts: err.Conflicts,
+		RequestID: requestID(w),
+	})
+}
+
+// requestID returns the request ID set by middleware.RequestID.
+// It is read back from the response header so error helpers need no *http.Request.
+func requestID(w http.ResponseWriter) string {
+	return w.Header().Get(middleware.RequestIDHeader)
+}
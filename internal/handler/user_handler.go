@@ -1,23 +1,35 @@
 package handler
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 
+	"github.com/para7/nanaket-cms/internal/db"
+	"github.com/para7/nanaket-cms/internal/middleware"
 	"github.com/para7/nanaket-cms/internal/usecase"
 )
 
+// maxBatchUserIDs is the largest number of users fetched by ?ids= in one request
+const maxBatchUserIDs = 100
+
 // UserHandler handles HTTP requests for user operations
 type UserHandler struct {
 	usecase usecase.UserUsecase
+	pages   PageSizeConfig
 }
 
 // NewUserHandler creates a new instance of UserHandler
-func NewUserHandler(usecase usecase.UserUsecase) *UserHandler {
+func NewUserHandler(usecase usecase.UserUsecase, pages PageSizeConfig) *UserHandler {
 	return &UserHandler{
 		usecase: usecase,
+		pages:   pages,
 	}
 }
 
@@ -25,6 +37,7 @@ func NewUserHandler(usecase usecase.UserUsecase) *UserHandler {
 type CreateUserRequest struct {
 	Email string `json:"email"`
 	Name  string `json:"name"`
+	Role  string `json:"role,omitempty"` // admin, editor or viewer (default: viewer)
 }
 
 // UpdateUserRequest represents the request body for updating a user
@@ -33,33 +46,56 @@ type UpdateUserRequest struct {
 	Name  string `json:"name"`
 }
 
-// ErrorResponse represents an error response
-type ErrorResponse struct {
-	Error string `json:"error"`
+// UpdateUserResponse represents the response of updating a user.
+// PendingEmail is set when the request changed the email: the user keeps the old
+// email until the link sent to the new one is opened.
+type UpdateUserResponse struct {
+	db.User
+	PendingEmail string `json:"pending_email,omitempty"`
+}
+
+// UpsertUserRequest represents the request body for creating or updating a user by email
+type UpsertUserRequest struct {
+	Name string `json:"name"`
+}
+
+// ListUsersResponse represents the response body for listing users
+type ListUsersResponse struct {
+	Items   []db.User `json:"items"`
+	Total   int64     `json:"total"`
+	Page    int       `json:"page"`
+	PerPage int       `json:"per_page"`
+}
+
+// ListUsersByIDsResponse represents the response body for fetching users by ID
+type ListUsersByIDsResponse struct {
+	Items []db.User `json:"items"`
 }
 
 // CreateUser handles POST /api/v1/users
 func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	var req CreateUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "Invalid request body"})
+		writeDecodeError(w, err)
 		return
 	}
 
-	if req.Email == "" || req.Name == "" {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "Email and name are required"})
+	user, err := h.usecase.CreateUser(r.Context(), req.Email, req.Name, req.Role)
+	var validationErr *usecase.ValidationError
+	if errors.As(err, &validationErr) {
+		writeValidationError(w, validationErr)
+		return
+	}
+	if errors.Is(err, usecase.ErrEmailTaken) {
+		writeError(w, http.StatusConflict, CodeEmailTaken, "Email is already taken")
+		return
+	}
+	if errors.Is(err, usecase.ErrInvalidUserRole) {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid role")
 		return
 	}
-
-	user, err := h.usecase.CreateUser(r.Context(), req.Email, req.Name)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: fmt.Sprintf("Failed to create user: %v", err)})
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to create user: %v", err))
 		return
 	}
 
@@ -73,17 +109,13 @@ func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "Invalid user ID"})
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid user ID")
 		return
 	}
 
 	user, err := h.usecase.GetUser(r.Context(), id)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "User not found"})
+		writeError(w, http.StatusNotFound, CodeNotFound, "User not found")
 		return
 	}
 
@@ -93,77 +125,250 @@ func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 }
 
 // ListUsers handles GET /api/v1/users
+// Supports offset pagination via ?page=1&per_page=20; invalid values fall back to defaults.
+// With ?ids=1,2,3 it instead returns just those users (see listUsersByIDs).
 func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
-	users, err := h.usecase.ListUsers(r.Context())
+	if r.URL.Query().Has("ids") {
+		h.listUsersByIDs(w, r)
+		return
+	}
+
+	page, perPage := h.userPage(r)
+	users, total, err := h.usecase.ListUsersPaginated(r.Context(), int32(perPage), int32((page-1)*perPage))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to list users: %v", err))
+		return
+	}
+
+	writeUserPage(w, r, users, total, page, perPage)
+}
+
+// SearchUsers handles GET /api/v1/users/search?q=term
+// Matches name or email case-insensitively and is paginated like ListUsers.
+// % and _ in q are matched literally unless ?wildcards=true is given.
+func (h *UserHandler) SearchUsers(w http.ResponseWriter, r *http.Request) {
+	page, perPage := h.userPage(r)
+	wildcards := r.URL.Query().Get("wildcards") == "true"
+	users, total, err := h.usecase.SearchUsers(r.Context(), r.URL.Query().Get("q"), wildcards, int32(perPage), int32((page-1)*perPage))
+	if errors.Is(err, usecase.ErrEmptySearchQuery) {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Search query is required")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to search users: %v", err))
+		return
+	}
+
+	writeUserPage(w, r, users, total, page, perPage)
+}
+
+// userPage reads ?page= and ?per_page=, falling back to defaults for missing or invalid values
+func (h *UserHandler) userPage(r *http.Request) (page, perPage int) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	perPage, err = strconv.Atoi(r.URL.Query().Get("per_page"))
+	if err != nil || perPage < 1 {
+		perPage = h.pages.Default
+	}
+	perPage = min(perPage, h.pages.Max)
+	// Keep the offset within int32 range
+	page = min(page, math.MaxInt32/perPage)
+	return page, perPage
+}
+
+// writeUserPage writes a page of users with its pagination headers
+func writeUserPage(w http.ResponseWriter, r *http.Request, users []db.User, total int64, page, perPage int) {
+	var links []pageLink
+	if int64(page)*int64(perPage) < total {
+		links = append(links, pageLink{rel: "next", params: map[string]string{"page": strconv.Itoa(page + 1), "per_page": strconv.Itoa(perPage)}})
+	}
+	if page > 1 {
+		links = append(links, pageLink{rel: "prev", params: map[string]string{"page": strconv.Itoa(page - 1), "per_page": strconv.Itoa(perPage)}})
+	}
+	setPaginationHeaders(w, r, total, links...)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(ListUsersResponse{
+		Items:   users,
+		Total:   total,
+		Page:    page,
+		PerPage: perPage,
+	})
+}
+
+// listUsersByIDs handles GET /api/v1/users?ids=1,2,3
+// Returns up to maxBatchUserIDs users in ID order with a single query; unknown IDs are skipped.
+// Duplicate IDs are ignored, and a malformed list yields 400.
+func (h *UserHandler) listUsersByIDs(w http.ResponseWriter, r *http.Request) {
+	ids, err := parseIDList(r.URL.Query().Get("ids"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "ids must be a comma-separated list of user IDs")
+		return
+	}
+	if len(ids) > maxBatchUserIDs {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, fmt.Sprintf("At most %d ids can be requested at once", maxBatchUserIDs))
+		return
+	}
+
+	users, err := h.usecase.ListUsersByIDs(r.Context(), ids)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: fmt.Sprintf("Failed to list users: %v", err)})
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to list users: %v", err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	_ = json.NewEncoder(w).Encode(users)
+	_ = json.NewEncoder(w).Encode(ListUsersByIDsResponse{Items: users})
+}
+
+// parseIDList parses a non-empty comma-separated list of positive IDs and returns them sorted without duplicates
+func parseIDList(s string) ([]int64, error) {
+	var ids []int64
+	for part := range strings.SplitSeq(s, ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		if id < 1 {
+			return nil, fmt.Errorf("invalid ID %d", id)
+		}
+		ids = append(ids, id)
+	}
+	return slices.Compact(slices.Sorted(slices.Values(ids))), nil
 }
 
 // UpdateUser handles PUT /api/v1/users/{id}
+// Only the user themselves or an admin may update a user; the email is a login credential.
+// A new email takes effect once confirmed by the link sent to it, and is returned as pending_email.
 func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "Invalid user ID"})
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid user ID")
+		return
+	}
+
+	caller, ok := middleware.GetUserFromContext(r.Context())
+	if !ok || (caller.ID != id && !usecase.HasRole(caller.Role, usecase.UserRoleAdmin)) {
+		writeError(w, http.StatusForbidden, CodeForbidden, "Cannot update another user")
 		return
 	}
 
 	var req UpdateUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "Invalid request body"})
+		writeDecodeError(w, err)
 		return
 	}
 
-	if req.Email == "" || req.Name == "" {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "Email and name are required"})
+	user, pendingEmail, err := h.usecase.UpdateUser(r.Context(), id, req.Email, req.Name)
+	var validationErr *usecase.ValidationError
+	if errors.As(err, &validationErr) {
+		writeValidationError(w, validationErr)
+		return
+	}
+	if errors.Is(err, usecase.ErrEmailTaken) {
+		writeError(w, http.StatusConflict, CodeEmailTaken, "Email is already taken")
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "User not found")
 		return
 	}
-
-	user, err := h.usecase.UpdateUser(r.Context(), id, req.Email, req.Name)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "User not found"})
+		writeError(w, http.StatusInternalServerError, CodeInternal, "Failed to update user")
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(UpdateUserResponse{User: user, PendingEmail: pendingEmail})
+}
+
+// UpsertUserByEmail handles PUT /api/v1/users/by-email/{email}
+// Creates the user (201) if the email is new, otherwise updates its name (200).
+// The email may be URL-encoded; the path value is already unescaped.
+func (h *UserHandler) UpsertUserByEmail(w http.ResponseWriter, r *http.Request) {
+	email := r.PathValue("email")
+
+	var req UpsertUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	user, created, err := h.usecase.UpsertUserByEmail(r.Context(), email, req.Name)
+	var validationErr *usecase.ValidationError
+	if errors.As(err, &validationErr) {
+		writeValidationError(w, validationErr)
+		return
+	}
+	if errors.Is(err, usecase.ErrEmailTaken) {
+		writeError(w, http.StatusConflict, CodeEmailTaken, "Email belongs to a deleted user")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, "Failed to save user")
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
 	_ = json.NewEncoder(w).Encode(user)
 }
 
 // DeleteUser handles DELETE /api/v1/users/{id}
+// The user is soft-deleted and their tokens are revoked; their articles stay visible.
 func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	idStr := r.PathValue("id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "Invalid user ID"})
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid user ID")
 		return
 	}
 
-	if err := h.usecase.DeleteUser(r.Context(), id); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		_ = json.NewEncoder(w).Encode(ErrorResponse{Error: "User not found"})
+	err = h.usecase.DeleteUser(r.Context(), id)
+	if errors.Is(err, usecase.ErrUserNotFound) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "User not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to delete user: %v", err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// RestoreUser handles POST /api/v1/users/{id}/restore
+// Undoes a soft delete; tokens revoked by the delete stay revoked.
+func (h *UserHandler) RestoreUser(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid user ID")
+		return
+	}
+
+	user, err := h.usecase.RestoreUser(r.Context(), id)
+	if errors.Is(err, usecase.ErrUserNotFound) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "Deleted user not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to restore user: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(user)
+}
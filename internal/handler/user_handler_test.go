@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/para7/nanaket-cms/internal/db"
+	"github.com/para7/nanaket-cms/internal/db/mock"
+	"github.com/para7/nanaket-cms/internal/middleware"
+	"github.com/para7/nanaket-cms/internal/repository"
+	"github.com/para7/nanaket-cms/internal/usecase"
+)
+
+// userStore returns a mock.UserRepository that finds users by ID, and by email
+// case-insensitively as GetUserByEmail does
+func userStore(users ...db.User) *mock.UserRepository {
+	byID := func(id int64) (db.User, error) {
+		for _, u := range users {
+			if u.ID == id {
+				return u, nil
+			}
+		}
+		return db.User{}, sql.ErrNoRows
+	}
+	return &mock.UserRepository{
+		GetByIDFunc: func(ctx context.Context, id int64) (db.User, error) {
+			return byID(id)
+		},
+		GetByEmailFunc: func(ctx context.Context, email string) (db.User, error) {
+			for _, u := range users {
+				if strings.EqualFold(u.Email, email) {
+					return u, nil
+				}
+			}
+			return db.User{}, sql.ErrNoRows
+		},
+	}
+}
+
+// errorCode decodes the code of an error response
+func errorCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("Content-Type = %q, want application/json", ct)
+	}
+	var body ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode error response: %v", err)
+	}
+	return body.Code
+}
+
+// withUser returns r with user stored in its context, as the auth middleware does
+func withUser(r *http.Request, user db.User) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), middleware.UserContextKey, user))
+}
+
+func TestCreateUserEmailTaken(t *testing.T) {
+	existing := db.User{ID: 1, Email: "taken@example.com", Name: "Taken", Role: usecase.UserRoleViewer}
+
+	tests := []struct {
+		name string
+		repo *mock.UserRepository
+	}{
+		{"found by the pre-check", userStore(existing)},
+		// A row stored before emails were lowercased
+		{"stored in another case", userStore(db.User{ID: 1, Email: "Taken@Example.com", Name: "Taken", Role: usecase.UserRoleViewer})},
+		{"inserted concurrently", func() *mock.UserRepository {
+			repo := userStore()
+			repo.CreateFunc = func(ctx context.Context, email, name, role string) (db.User, error) {
+				return db.User{}, repository.ErrDuplicateKey
+			}
+			return repo
+		}()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewUserHandler(usecase.NewUserUsecase(tt.repo, nil), PageSizeConfig{})
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/users", strings.NewReader(`{"email":"taken@example.com","name":"Another"}`))
+			rec := httptest.NewRecorder()
+			h.CreateUser(rec, req)
+
+			if rec.Code != http.StatusConflict {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusConflict)
+			}
+			if code := errorCode(t, rec); code != CodeEmailTaken {
+				t.Errorf("code = %q, want %q", code, CodeEmailTaken)
+			}
+		})
+	}
+}
+
+func TestUpdateUserEmailTaken(t *testing.T) {
+	first := db.User{ID: 1, Email: "first@example.com", Name: "First", Role: usecase.UserRoleViewer}
+	second := db.User{ID: 2, Email: "second@example.com", Name: "Second", Role: usecase.UserRoleViewer}
+	repo := userStore(first, second)
+	repo.UpdateFunc = func(ctx context.Context, id int64, email, name string) (db.User, error) {
+		t.Errorf("Update called for user %d", id)
+		return db.User{}, nil
+	}
+	h := NewUserHandler(usecase.NewUserUsecase(repo, nil), PageSizeConfig{})
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/users/2", strings.NewReader(`{"email":"first@example.com","name":"Second"}`))
+	req.SetPathValue("id", "2")
+	rec := httptest.NewRecorder()
+	h.UpdateUser(rec, withUser(req, second))
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	if code := errorCode(t, rec); code != CodeEmailTaken {
+		t.Errorf("code = %q, want %q", code, CodeEmailTaken)
+	}
+}
+
+func TestCreateUserValidationFields(t *testing.T) {
+	// Validation fails before the repository is reached, so it has no functions
+	h := NewUserHandler(usecase.NewUserUsecase(&mock.UserRepository{}, nil), PageSizeConfig{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users", strings.NewReader(`{"email":"not-an-email","name":" "}`))
+	rec := httptest.NewRecorder()
+	h.CreateUser(rec, req)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnprocessableEntity)
+	}
+	var body ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.Code != CodeValidation || body.Error != "validation failed" {
+		t.Errorf("error = %q, code = %q", body.Error, body.Code)
+	}
+	want := []usecase.FieldError{
+		{Field: "name", Message: "is required"},
+		{Field: "email", Message: "invalid email format"},
+	}
+	if !slices.Equal(body.Fields, want) {
+		t.Errorf("fields = %+v, want %+v", body.Fields, want)
+	}
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/para7/nanaket-cms/internal/usecase"
+)
+
+// WebhookHandler handles HTTP requests for webhook subscriptions
+type WebhookHandler struct {
+	usecase usecase.WebhookUsecase
+}
+
+// NewWebhookHandler creates a new instance of WebhookHandler
+func NewWebhookHandler(usecase usecase.WebhookUsecase) *WebhookHandler {
+	return &WebhookHandler{
+		usecase: usecase,
+	}
+}
+
+// WebhookRequest represents the request body for creating or updating a webhook
+type WebhookRequest struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret,omitempty"` // generated on create and kept on update when omitted
+	Events []string `json:"events"`           // article.created, article.updated and/or article.deleted
+}
+
+// CreateWebhook handles POST /api/v1/admin/webhooks
+// The response is the only one that includes the secret.
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req WebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	hook, err := h.usecase.CreateWebhook(r.Context(), usecase.WebhookInput{
+		URL:    req.URL,
+		Secret: req.Secret,
+		Events: req.Events,
+	})
+	if writeWebhookError(w, err) {
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to create webhook: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(hook)
+}
+
+// GetWebhook handles GET /api/v1/admin/webhooks/{id}
+func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	id, ok := webhookID(w, r)
+	if !ok {
+		return
+	}
+
+	hook, err := h.usecase.GetWebhook(r.Context(), id)
+	if writeWebhookError(w, err) {
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to get webhook: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(hook)
+}
+
+// ListWebhooks handles GET /api/v1/admin/webhooks
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	hooks, err := h.usecase.ListWebhooks(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to list webhooks: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(hooks)
+}
+
+// UpdateWebhook handles PUT /api/v1/admin/webhooks/{id}
+func (h *WebhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	id, ok := webhookID(w, r)
+	if !ok {
+		return
+	}
+
+	var req WebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	hook, err := h.usecase.UpdateWebhook(r.Context(), id, usecase.WebhookInput{
+		URL:    req.URL,
+		Secret: req.Secret,
+		Events: req.Events,
+	})
+	if writeWebhookError(w, err) {
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to update webhook: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(hook)
+}
+
+// DeleteWebhook handles DELETE /api/v1/admin/webhooks/{id}
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, ok := webhookID(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.usecase.DeleteWebhook(r.Context(), id)
+	if writeWebhookError(w, err) {
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, fmt.Sprintf("Failed to delete webhook: %v", err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// webhookID parses the {id} path value.
+// On invalid input it writes an error response and returns ok == false.
+func webhookID(w http.ResponseWriter, r *http.Request) (id int64, ok bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "Invalid webhook ID")
+		return 0, false
+	}
+	return id, true
+}
+
+// writeWebhookError writes the response for the webhook errors of the usecase layer
+// and reports whether err was one of them
+func writeWebhookError(w http.ResponseWriter, err error) bool {
+	var validationErr *usecase.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		writeValidationError(w, validationErr)
+	case errors.Is(err, usecase.ErrWebhookNotFound):
+		writeError(w, http.StatusNotFound, CodeNotFound, "Webhook not found")
+	default:
+		return false
+	}
+	return true
+}
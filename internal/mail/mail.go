@@ -0,0 +1,81 @@
+// Package mail sends email through a swappable transport.
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+// Message is a plain-text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends email.
+// Implementations must be safe for concurrent use.
+type Mailer interface {
+	// Send delivers msg, returning once the transport has accepted it
+	Send(ctx context.Context, msg Message) error
+}
+
+// LogMailer is a Mailer for development that writes messages to the log instead of sending them.
+// Messages may contain login links, so it must not be used in production.
+type LogMailer struct{}
+
+// Send logs msg at info level
+func (LogMailer) Send(ctx context.Context, msg Message) error {
+	slog.InfoContext(ctx, "Email not sent (no mail transport configured)", "to", msg.To, "subject", msg.Subject, "body", msg.Body)
+	return nil
+}
+
+// SMTPMailer is a Mailer that sends through an SMTP server, using STARTTLS when offered
+type SMTPMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTPMailer returns an SMTPMailer sending from the address from through the server at addr (host:port).
+// An empty username disables authentication.
+func NewSMTPMailer(addr, from, username, password string) (*SMTPMailer, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("mail: invalid SMTP address %q: %w", addr, err)
+	}
+	if from == "" {
+		return nil, fmt.Errorf("mail: sender address is required")
+	}
+
+	m := &SMTPMailer{addr: addr, from: from}
+	if username != "" {
+		m.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return m, nil
+}
+
+// Send delivers msg. net/smtp does not take a context, so ctx is not honored once sending starts.
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	// Reject header injection through the recipient or subject
+	if strings.ContainsAny(msg.To, "\r\n") || strings.ContainsAny(msg.Subject, "\r\n") {
+		return fmt.Errorf("mail: invalid header value")
+	}
+
+	body := "From: " + m.from + "\r\n" +
+		"To: " + msg.To + "\r\n" +
+		"Subject: " + mime.QEncoding.Encode("UTF-8", msg.Subject) + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" +
+		strings.ReplaceAll(msg.Body, "\n", "\r\n")
+	return smtp.SendMail(m.addr, m.auth, m.from, []string{msg.To}, []byte(body))
+}
@@ -0,0 +1,276 @@
+// Package markdown renders a safe subset of Markdown to HTML.
+//
+// The renderer never passes raw HTML through: all text is escaped and only
+// the tags it generates itself are emitted, and link and image URLs are
+// limited to http, https, mailto (links only) and relative URLs. The output
+// can therefore be embedded in a page without further sanitization.
+//
+// Supported syntax: ATX headings, paragraphs, fenced code blocks, block quotes,
+// unordered and ordered lists (one level), horizontal rules, code spans,
+// emphasis, strong emphasis, links and images.
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	headingPattern     = regexp.MustCompile(`^(#{1,6})(?:[ \t]+(.*?))?[ \t]*#*[ \t]*$`)
+	ruleSpacePattern   = regexp.MustCompile(`[ \t]`)
+	unorderedPattern   = regexp.MustCompile(`^[ ]{0,3}[-*+][ \t]+(.*)$`)
+	orderedPattern     = regexp.MustCompile(`^[ ]{0,3}\d{1,9}[.)][ \t]+(.*)$`)
+	blockquotePattern  = regexp.MustCompile(`^[ ]{0,3}>[ ]?(.*)$`)
+	fencePattern       = regexp.MustCompile("^[ ]{0,3}(```|~~~)")
+	continuationIndent = regexp.MustCompile(`^(?:[ ]{2,}|\t)(.*)$`)
+)
+
+// ToHTML renders Markdown source as sanitized HTML
+func ToHTML(src string) string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	var b strings.Builder
+	renderBlocks(&b, strings.Split(src, "\n"))
+	return b.String()
+}
+
+// renderBlocks renders a sequence of lines as block-level elements
+func renderBlocks(b *strings.Builder, lines []string) {
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) > 0 {
+			b.WriteString("<p>")
+			b.WriteString(renderInline(strings.Join(paragraph, "\n")))
+			b.WriteString("</p>\n")
+			paragraph = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+
+		if m := fencePattern.FindStringSubmatch(line); m != nil {
+			flush()
+			var code []string
+			for i++; i < len(lines); i++ {
+				if strings.HasPrefix(strings.TrimSpace(lines[i]), m[1]) {
+					break
+				}
+				code = append(code, lines[i])
+			}
+			b.WriteString("<pre><code>")
+			if len(code) > 0 {
+				b.WriteString(html.EscapeString(strings.Join(code, "\n")))
+				b.WriteString("\n")
+			}
+			b.WriteString("</code></pre>\n")
+			continue
+		}
+
+		if m := headingPattern.FindStringSubmatch(strings.TrimLeft(line, " ")); m != nil && len(line)-len(strings.TrimLeft(line, " ")) <= 3 {
+			flush()
+			level := string(rune('0' + len(m[1])))
+			b.WriteString("<h" + level + ">")
+			b.WriteString(renderInline(m[2]))
+			b.WriteString("</h" + level + ">\n")
+			continue
+		}
+
+		if isRule(line) {
+			flush()
+			b.WriteString("<hr>\n")
+			continue
+		}
+
+		if blockquotePattern.MatchString(line) {
+			flush()
+			var quoted []string
+			for ; i < len(lines); i++ {
+				m := blockquotePattern.FindStringSubmatch(lines[i])
+				if m == nil {
+					break
+				}
+				quoted = append(quoted, m[1])
+			}
+			i--
+			b.WriteString("<blockquote>\n")
+			renderBlocks(b, quoted)
+			b.WriteString("</blockquote>\n")
+			continue
+		}
+
+		if unorderedPattern.MatchString(line) {
+			flush()
+			i = renderList(b, lines, i, unorderedPattern, "ul")
+			continue
+		}
+		if orderedPattern.MatchString(line) {
+			flush()
+			i = renderList(b, lines, i, orderedPattern, "ol")
+			continue
+		}
+
+		paragraph = append(paragraph, strings.TrimSpace(line))
+	}
+	flush()
+}
+
+// renderList renders the list starting at lines[start] and returns the index of its last line.
+// Indented lines following an item are treated as continuations of that item.
+func renderList(b *strings.Builder, lines []string, start int, item *regexp.Regexp, tag string) int {
+	var items []string
+	i := start
+	for ; i < len(lines); i++ {
+		if m := item.FindStringSubmatch(lines[i]); m != nil {
+			items = append(items, m[1])
+			continue
+		}
+		if m := continuationIndent.FindStringSubmatch(lines[i]); m != nil && strings.TrimSpace(m[1]) != "" {
+			items[len(items)-1] += "\n" + strings.TrimSpace(m[1])
+			continue
+		}
+		break
+	}
+
+	b.WriteString("<" + tag + ">\n")
+	for _, it := range items {
+		b.WriteString("<li>")
+		b.WriteString(renderInline(it))
+		b.WriteString("</li>\n")
+	}
+	b.WriteString("</" + tag + ">\n")
+	return i - 1
+}
+
+// isRule reports whether line is a thematic break such as "---" or "* * *"
+func isRule(line string) bool {
+	s := ruleSpacePattern.ReplaceAllString(line, "")
+	if len(s) < 3 || len(line)-len(strings.TrimLeft(line, " ")) > 3 {
+		return false
+	}
+	c := s[0]
+	if c != '-' && c != '*' && c != '_' {
+		return false
+	}
+	return strings.Count(s, string(c)) == len(s)
+}
+
+// renderInline renders code spans, emphasis, links and images within a block, escaping all other text
+func renderInline(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && isPunct(s[i+1]):
+			b.WriteString(html.EscapeString(s[i+1 : i+2]))
+			i += 2
+			continue
+
+		case c == '`':
+			run := i
+			for run < len(s) && s[run] == '`' {
+				run++
+			}
+			fence := s[i:run]
+			if end := strings.Index(s[run:], fence); end >= 0 {
+				code := strings.TrimSpace(s[run : run+end])
+				b.WriteString("<code>" + html.EscapeString(code) + "</code>")
+				i = run + end + len(fence)
+				continue
+			}
+			b.WriteString(fence)
+			i = run
+			continue
+
+		case c == '*' || (c == '_' && (i == 0 || !isAlnum(s[i-1]))):
+			if i+1 < len(s) && s[i+1] == c {
+				delim := s[i : i+2]
+				if end := strings.Index(s[i+2:], delim); end > 0 {
+					b.WriteString("<strong>" + renderInline(s[i+2:i+2+end]) + "</strong>")
+					i += 2 + end + 2
+					continue
+				}
+			} else if end := strings.IndexByte(s[i+1:], c); end > 0 && s[i+1] != ' ' {
+				b.WriteString("<em>" + renderInline(s[i+1:i+1+end]) + "</em>")
+				i += 1 + end + 1
+				continue
+			}
+
+		case c == '!' && i+1 < len(s) && s[i+1] == '[':
+			if text, url, n, ok := parseLink(s[i+1:]); ok {
+				if safe, ok := safeURL(url, false); ok {
+					b.WriteString(`<img src="` + html.EscapeString(safe) + `" alt="` + html.EscapeString(text) + `">`)
+				} else {
+					b.WriteString(html.EscapeString(text))
+				}
+				i += 1 + n
+				continue
+			}
+
+		case c == '[':
+			if text, url, n, ok := parseLink(s[i:]); ok {
+				if safe, ok := safeURL(url, true); ok {
+					b.WriteString(`<a href="` + html.EscapeString(safe) + `" rel="nofollow">` + renderInline(text) + `</a>`)
+				} else {
+					b.WriteString(renderInline(text))
+				}
+				i += n
+				continue
+			}
+		}
+
+		b.WriteString(html.EscapeString(s[i : i+1]))
+		i++
+	}
+	return b.String()
+}
+
+// parseLink parses "[text](url)" at the start of s and returns the text, the URL
+// and the number of bytes consumed
+func parseLink(s string) (text, url string, n int, ok bool) {
+	closeText := strings.Index(s, "](")
+	if closeText < 0 {
+		return "", "", 0, false
+	}
+	closeURL := strings.IndexByte(s[closeText+2:], ')')
+	if closeURL < 0 {
+		return "", "", 0, false
+	}
+	text = s[1:closeText]
+	url = strings.TrimSpace(s[closeText+2 : closeText+2+closeURL])
+	if strings.ContainsAny(url, " \t\n") {
+		return "", "", 0, false
+	}
+	return text, url, closeText + 2 + closeURL + 1, true
+}
+
+// safeURL returns url if it is relative or uses an allowed scheme.
+// mailto is only allowed for links.
+func safeURL(url string, link bool) (string, bool) {
+	scheme, _, found := strings.Cut(url, ":")
+	if !found || strings.ContainsAny(scheme, "/?#") {
+		return url, true
+	}
+	switch strings.ToLower(scheme) {
+	case "http", "https":
+		return url, true
+	case "mailto":
+		return url, link
+	}
+	return "", false
+}
+
+// isAlnum reports whether c is an ASCII letter or digit
+func isAlnum(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// isPunct reports whether c is an ASCII punctuation character that may be backslash-escaped
+func isPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
@@ -0,0 +1,107 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	scriptBlockPattern = regexp.MustCompile(`(?is)<script\b[^>]*>.*?</script\s*>`)
+	scriptTagPattern   = regexp.MustCompile(`(?i)</?script\b[^>]*>`)
+	codeSpanPattern    = regexp.MustCompile("``[^\n]*?``|`[^`\n]*`")
+	htmlTagPattern     = regexp.MustCompile(`<[a-zA-Z][a-zA-Z0-9-]*(?:\s+[^\s"'<>/=]+(?:\s*=\s*(?:"[^"]*"|'[^']*'|[^\s"'<>]+))?)*\s*/?>`)
+	htmlAttrPattern    = regexp.MustCompile(`\s+([^\s"'<>/=]+)(?:\s*=\s*("[^"]*"|'[^']*'|[^\s"'<>]+))?`)
+)
+
+// StripUnsafeHTML removes raw HTML from Markdown source that would run script if the source
+// were rendered by a renderer that passes HTML through: <script> elements, on* event handler
+// attributes and attributes whose value is a javascript: URL. It reports whether anything was removed.
+//
+// Only HTML is touched. Markdown syntax is left as written, including links, which ToHTML
+// already restricts to safe schemes, and code spans and fenced code blocks, which render as text.
+func StripUnsafeHTML(src string) (string, bool) {
+	lines := strings.Split(src, "\n")
+	var out, text []string
+	changed := false
+	flush := func() {
+		if len(text) > 0 {
+			clean, c := stripText(strings.Join(text, "\n"))
+			out = append(out, clean)
+			changed = changed || c
+			text = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		m := fencePattern.FindStringSubmatch(lines[i])
+		if m == nil {
+			text = append(text, lines[i])
+			continue
+		}
+		flush()
+		// Keep the fence and its contents verbatim, up to and including the closing fence
+		out = append(out, lines[i])
+		for i++; i < len(lines); i++ {
+			out = append(out, lines[i])
+			if strings.HasPrefix(strings.TrimSpace(lines[i]), m[1]) {
+				break
+			}
+		}
+	}
+	flush()
+
+	if !changed {
+		return src, false
+	}
+	return strings.Join(out, "\n"), true
+}
+
+// stripText sanitizes text outside fenced code blocks, leaving code spans as they are
+func stripText(s string) (string, bool) {
+	var b strings.Builder
+	changed := false
+	last := 0
+	for _, loc := range codeSpanPattern.FindAllStringIndex(s, -1) {
+		clean, c := stripHTML(s[last:loc[0]])
+		b.WriteString(clean)
+		b.WriteString(s[loc[0]:loc[1]])
+		changed = changed || c
+		last = loc[1]
+	}
+	clean, c := stripHTML(s[last:])
+	b.WriteString(clean)
+	return b.String(), changed || c
+}
+
+// stripHTML removes script elements and unsafe attributes from s
+func stripHTML(s string) (string, bool) {
+	out := scriptBlockPattern.ReplaceAllString(s, "")
+	out = scriptTagPattern.ReplaceAllString(out, "")
+	out = htmlTagPattern.ReplaceAllStringFunc(out, func(tag string) string {
+		return htmlAttrPattern.ReplaceAllStringFunc(tag, func(attr string) string {
+			m := htmlAttrPattern.FindStringSubmatch(attr)
+			if isUnsafeAttr(m[1], m[2]) {
+				return ""
+			}
+			return attr
+		})
+	})
+	return out, out != s
+}
+
+// isUnsafeAttr reports whether an attribute is an event handler or holds a javascript: URL
+func isUnsafeAttr(name, value string) bool {
+	if strings.HasPrefix(strings.ToLower(name), "on") {
+		return true
+	}
+	value = html.UnescapeString(strings.Trim(value, `"'`))
+	// Browsers ignore whitespace and control characters inside the scheme, e.g. "java\tscript:"
+	value = strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, value)
+	return strings.HasPrefix(strings.ToLower(value), "javascript:")
+}
@@ -0,0 +1,133 @@
+// Package metrics keeps per-route request counters and latency samples in memory.
+package metrics
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+)
+
+// sampleSize is the number of most recent latencies kept per route for percentiles
+const sampleSize = 1024
+
+// Registry collects request metrics keyed by route pattern.
+// It is safe for concurrent use.
+type Registry struct {
+	startedAt time.Time
+
+	mu     sync.RWMutex
+	routes map[string]*routeStats
+}
+
+// routeStats holds the counters of one route
+type routeStats struct {
+	mu       sync.Mutex
+	requests int64
+	errors   int64
+	// samples is a ring buffer of the latest latencies; next is the slot written next
+	samples []time.Duration
+	next    int
+}
+
+// RouteMetrics is a snapshot of the metrics of one route
+type RouteMetrics struct {
+	Route    string `json:"route"`
+	Requests int64  `json:"requests"`
+	// Errors counts responses with a 5xx status
+	Errors int64 `json:"errors"`
+	// P50Ms and P99Ms are latency percentiles in milliseconds over the latest requests
+	P50Ms float64 `json:"p50_ms"`
+	P99Ms float64 `json:"p99_ms"`
+}
+
+// Snapshot is the state of a Registry at one point in time
+type Snapshot struct {
+	StartedAt time.Time      `json:"started_at"`
+	Routes    []RouteMetrics `json:"routes"`
+}
+
+// NewRegistry creates an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{
+		startedAt: time.Now(),
+		routes:    make(map[string]*routeStats),
+	}
+}
+
+// Observe records one request to route that was answered with status after elapsed
+func (r *Registry) Observe(route string, status int, elapsed time.Duration) {
+	stats := r.stats(route)
+
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+	stats.requests++
+	if status >= 500 {
+		stats.errors++
+	}
+	if len(stats.samples) < sampleSize {
+		stats.samples = append(stats.samples, elapsed)
+		return
+	}
+	stats.samples[stats.next] = elapsed
+	stats.next = (stats.next + 1) % sampleSize
+}
+
+// stats returns the counters of route, creating them on first use
+func (r *Registry) stats(route string) *routeStats {
+	r.mu.RLock()
+	stats, ok := r.routes[route]
+	r.mu.RUnlock()
+	if ok {
+		return stats
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if stats, ok := r.routes[route]; ok {
+		return stats
+	}
+	stats = &routeStats{}
+	r.routes[route] = stats
+	return stats
+}
+
+// Snapshot returns the current metrics of every route that has been requested, ordered by route
+func (r *Registry) Snapshot() Snapshot {
+	r.mu.RLock()
+	routes := make(map[string]*routeStats, len(r.routes))
+	for route, stats := range r.routes {
+		routes[route] = stats
+	}
+	r.mu.RUnlock()
+
+	snapshot := Snapshot{StartedAt: r.startedAt, Routes: make([]RouteMetrics, 0, len(routes))}
+	for route, stats := range routes {
+		stats.mu.Lock()
+		m := RouteMetrics{Route: route, Requests: stats.requests, Errors: stats.errors}
+		samples := slices.Clone(stats.samples)
+		stats.mu.Unlock()
+
+		// Sort outside the lock so requests are not held up
+		slices.Sort(samples)
+		m.P50Ms = milliseconds(percentile(samples, 50))
+		m.P99Ms = milliseconds(percentile(samples, 99))
+		snapshot.Routes = append(snapshot.Routes, m)
+	}
+	slices.SortFunc(snapshot.Routes, func(a, b RouteMetrics) int { return cmp.Compare(a.Route, b.Route) })
+	return snapshot
+}
+
+// percentile returns the p-th percentile of sorted samples by the nearest-rank method, or 0 without samples
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (len(sorted)*p + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
+
+// milliseconds converts d to fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -2,13 +2,15 @@ package middleware
 
 import (
 	"context"
-	"database/sql"
 	"errors"
-	"log"
+	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/para7/nanaket-cms/internal/background"
 	"github.com/para7/nanaket-cms/internal/db"
+	"github.com/para7/nanaket-cms/internal/usecase"
 )
 
 // ContextKey is a type for context keys to avoid collisions
@@ -21,29 +23,93 @@ const (
 	CookieName = "auth_token"
 )
 
+// tokenUseTimeout bounds the background last_used_at update
+const tokenUseTimeout = 5 * time.Second
+
+// Authenticator resolves an access token to its owner.
+// Authenticate returns usecase.ErrInvalidToken for unknown or expired tokens.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (db.User, error)
+	RecordTokenUse(ctx context.Context, token string) error
+}
+
 // AuthMiddleware creates a middleware that validates access tokens
-// It checks Authorization header first, then falls back to cookie
-func AuthMiddleware(queries db.Querier) func(http.Handler) http.Handler {
+// It checks Authorization header first, then falls back to cookie.
+// Token use is recorded through tasks.
+func AuthMiddleware(auth Authenticator, tasks *background.Tasks) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			token := extractToken(r)
+			token := ExtractToken(r)
 			if token == "" {
-				http.Error(w, "Unauthorized: No token provided", http.StatusUnauthorized)
+				writeSpecError(w, http.StatusUnauthorized, "unauthorized", "No token provided", nil)
 				return
 			}
 
-			// Validate token using GetUserByToken
-			user, err := queries.GetUserByToken(r.Context(), token)
+			// Validate token
+			user, err := auth.Authenticate(r.Context(), token)
 			if err != nil {
-				if errors.Is(err, sql.ErrNoRows) {
-					http.Error(w, "Unauthorized: Invalid or expired token", http.StatusUnauthorized)
+				if errors.Is(err, usecase.ErrInvalidToken) {
+					writeSpecError(w, http.StatusUnauthorized, "unauthorized", "Invalid or expired token", nil)
 					return
 				}
-				log.Printf("Error validating token: %v", err)
-				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				slog.ErrorContext(r.Context(), "Error validating token", "error", err)
+				writeSpecError(w, http.StatusInternalServerError, "internal_error", "Internal server error", nil)
+				return
+			}
+
+			recordTokenUse(r, auth, tasks, token)
+
+			// Store user in context
+			ctx := context.WithValue(r.Context(), UserContextKey, user)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireRole creates a middleware that only lets users with at least the given role through.
+// It must be applied after AuthMiddleware so the user is available in context.
+func RequireRole(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := GetUserFromContext(r.Context())
+			if !ok {
+				writeSpecError(w, http.StatusUnauthorized, "unauthorized", "Authentication required", nil)
+				return
+			}
+
+			if !usecase.HasRole(user.Role, role) {
+				writeSpecError(w, http.StatusForbidden, "forbidden", "Insufficient role", nil)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// OptionalAuthMiddleware creates a middleware that stores the user in context when a
+// valid token is provided, but lets anonymous requests through unchanged.
+// Token use is recorded through tasks.
+func OptionalAuthMiddleware(auth Authenticator, tasks *background.Tasks) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := ExtractToken(r)
+			if token == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			user, err := auth.Authenticate(r.Context(), token)
+			if err != nil {
+				if !errors.Is(err, usecase.ErrInvalidToken) {
+					slog.ErrorContext(r.Context(), "Error validating token", "error", err)
+				}
+				next.ServeHTTP(w, r)
 				return
 			}
 
+			recordTokenUse(r, auth, tasks, token)
+
 			// Store user in context
 			ctx := context.WithValue(r.Context(), UserContextKey, user)
 			next.ServeHTTP(w, r.WithContext(ctx))
@@ -51,9 +117,22 @@ func AuthMiddleware(queries db.Querier) func(http.Handler) http.Handler {
 	}
 }
 
-// extractToken extracts the token from Authorization header or cookie
+// recordTokenUse updates the token's last_used_at in the background so
+// authentication adds no latency; failures are only logged
+func recordTokenUse(r *http.Request, auth Authenticator, tasks *background.Tasks, token string) {
+	ctx := context.WithoutCancel(r.Context())
+	tasks.Go(func() {
+		ctx, cancel := context.WithTimeout(ctx, tokenUseTimeout)
+		defer cancel()
+		if err := auth.RecordTokenUse(ctx, token); err != nil {
+			slog.WarnContext(ctx, "Failed to record token use", "error", err)
+		}
+	})
+}
+
+// ExtractToken extracts the token from Authorization header or cookie
 // Priority: 1. Authorization header (Bearer token) 2. Cookie (auth_token)
-func extractToken(r *http.Request) string {
+func ExtractToken(r *http.Request) string {
 	// Try Authorization header first
 	authHeader := r.Header.Get("Authorization")
 	if authHeader != "" {
@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/para7/nanaket-cms/internal/background"
+	"github.com/para7/nanaket-cms/internal/db"
+	"github.com/para7/nanaket-cms/internal/db/mock"
+	"github.com/para7/nanaket-cms/internal/repository"
+	"github.com/para7/nanaket-cms/internal/usecase"
+)
+
+// tokenTable stands in for the access_tokens table, applying the expiry
+// conditions of the auth queries against now
+type tokenTable struct {
+	mu     sync.Mutex
+	now    time.Time
+	tokens map[string]*db.AccessToken
+}
+
+// add stores token for user 1, expiring at expiresAt unless it is nil
+func (t *tokenTable) add(token string, expiresAt *time.Time) {
+	row := &db.AccessToken{ID: int64(len(t.tokens) + 1), UserID: 1, Token: tokenHash(token)}
+	if expiresAt != nil {
+		row.ExpiresAt = pgtype.Timestamp{Time: *expiresAt, Valid: true}
+	}
+	if t.tokens == nil {
+		t.tokens = make(map[string]*db.AccessToken)
+	}
+	t.tokens[row.Token] = row
+}
+
+// live returns the stored token with hash, or nil if it is unknown or expired
+func (t *tokenTable) live(hash string) *db.AccessToken {
+	row := t.tokens[hash]
+	if row == nil || (row.ExpiresAt.Valid && !row.ExpiresAt.Time.After(t.now)) {
+		return nil
+	}
+	return row
+}
+
+func (t *tokenTable) querier() *mock.Querier {
+	return &mock.Querier{
+		GetUserByTokenFunc: func(ctx context.Context, token string) (db.User, error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			row := t.live(token)
+			if row == nil {
+				return db.User{}, pgx.ErrNoRows
+			}
+			return db.User{ID: row.UserID, Email: "user@example.com", Role: usecase.UserRoleEditor}, nil
+		},
+		TouchAccessTokenFunc: func(ctx context.Context, token string) error {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			row := t.tokens[token]
+			if row != nil && (!row.LastUsedAt.Valid || row.LastUsedAt.Time.Before(t.now.Add(-time.Minute))) {
+				row.LastUsedAt = pgtype.Timestamp{Time: t.now, Valid: true}
+			}
+			return nil
+		},
+	}
+}
+
+// tokenHash hashes token the way access_tokens.token stores it
+func tokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func newTestAuth(table *tokenTable, tasks *background.Tasks) func(http.Handler) http.Handler {
+	auth := usecase.NewAuthUsecase(repository.NewAuthRepository(table.querier()), nil, nil)
+	return AuthMiddleware(auth, tasks)
+}
+
+func TestAuthMiddlewareTokenExpiry(t *testing.T) {
+	now := time.Now().UTC()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	table := &tokenTable{now: now}
+	table.add("expired", &past)
+	table.add("valid", &future)
+	table.add("forever", nil)
+
+	handler := newTestAuth(table, background.New())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		token string
+		want  int
+	}{
+		{"expired", http.StatusUnauthorized},
+		{"unknown", http.StatusUnauthorized},
+		{"valid", http.StatusNoContent},
+		{"forever", http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.token, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/tokens", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want != http.StatusUnauthorized {
+				return
+			}
+			var body struct {
+				Code string `json:"code"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil || body.Code != "unauthorized" {
+				t.Errorf("body code = %q (%v), want unauthorized", body.Code, err)
+			}
+		})
+	}
+}
+
+func TestAuthMiddlewareRecordsTokenUse(t *testing.T) {
+	start := time.Now().UTC()
+	table := &tokenTable{now: start}
+	table.add("valid", nil)
+	tasks := background.New()
+	handler := newTestAuth(table, tasks)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	lastUsed := func(at time.Time) time.Time {
+		t.Helper()
+		table.mu.Lock()
+		table.now = at
+		table.mu.Unlock()
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/tokens", nil)
+		req.Header.Set("Authorization", "Bearer valid")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		// Wait also makes later updates run before ServeHTTP returns
+		if err := tasks.Wait(context.Background()); err != nil {
+			t.Fatalf("wait for token use: %v", err)
+		}
+
+		table.mu.Lock()
+		defer table.mu.Unlock()
+		return table.tokens[tokenHash("valid")].LastUsedAt.Time
+	}
+
+	if got := lastUsed(start); !got.Equal(start) {
+		t.Errorf("last_used_at after first use = %v, want %v", got, start)
+	}
+	later := start.Add(2 * time.Minute)
+	if got := lastUsed(later); !got.Equal(later) {
+		t.Errorf("last_used_at after later use = %v, want %v", got, later)
+	}
+}
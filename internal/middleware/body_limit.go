@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// DefaultMaxBodyBytes is the request body limit used when none is configured (1MB)
+const DefaultMaxBodyBytes int64 = 1 << 20
+
+// MaxBodySize creates a middleware that limits request bodies to limit bytes.
+// Requests declaring a larger Content-Length are rejected with 413 up front;
+// otherwise reads past the limit fail with *http.MaxBytesError, which handlers
+// report as 413.
+func MaxBodySize(limit int64) func(http.Handler) http.Handler {
+	return MaxBodySizeFunc(func(*http.Request) int64 { return limit })
+}
+
+// MaxBodySizeFunc is like MaxBodySize but picks the limit per request,
+// e.g. to allow larger bodies on upload endpoints
+func MaxBodySizeFunc(limitFor func(*http.Request) int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit := limitFor(r)
+			if r.ContentLength > limit {
+				writeSpecError(w, http.StatusRequestEntityTooLarge, "request_too_large", fmt.Sprintf("Request body exceeds %d bytes", limit), nil)
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const (
+	// ClientIPContextKey is the key for storing the client IP in context
+	ClientIPContextKey ContextKey = "client_ip"
+	// CFConnectingIPHeader carries the client IP when the server runs behind Cloudflare
+	CFConnectingIPHeader = "CF-Connecting-IP"
+	// ForwardedForHeader lists the client IP followed by the proxies a request passed through
+	ForwardedForHeader = "X-Forwarded-For"
+)
+
+// RealIP creates a middleware that resolves the client IP once and stores it in the request context.
+// When trustProxy is true the IP is taken from CF-Connecting-IP, then the leftmost X-Forwarded-For
+// entry, then the direct peer; otherwise those headers are ignored, since any client can set them.
+// Enable trustProxy only when every request arrives through a proxy that overwrites these headers.
+func RealIP(trustProxy bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := peerIP(r)
+			if trustProxy {
+				ip = forwardedIP(r, ip)
+			}
+			ctx := context.WithValue(r.Context(), ClientIPContextKey, ip)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ClientIP returns the client IP resolved by RealIP, or the IP of the direct peer if RealIP did not run
+func ClientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(ClientIPContextKey).(string); ok {
+		return ip
+	}
+	return peerIP(r)
+}
+
+// forwardedIP returns the client IP reported by a proxy, or fallback when no header holds a valid IP
+func forwardedIP(r *http.Request, fallback string) string {
+	if ip := strings.TrimSpace(r.Header.Get(CFConnectingIPHeader)); net.ParseIP(ip) != nil {
+		return ip
+	}
+	if list := r.Header.Get(ForwardedForHeader); list != "" {
+		first, _, _ := strings.Cut(list, ",")
+		if ip := strings.TrimSpace(first); net.ParseIP(ip) != nil {
+			return ip
+		}
+	}
+	return fallback
+}
+
+// peerIP returns the IP address of the direct peer
+func peerIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRealIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		trustProxy bool
+		header     http.Header
+		want       string
+	}{
+		{"no headers", true, nil, "10.0.0.1"},
+		{"cloudflare", true, http.Header{CFConnectingIPHeader: {"203.0.113.7"}}, "203.0.113.7"},
+		{"forwarded for", true, http.Header{ForwardedForHeader: {"203.0.113.8, 198.51.100.1"}}, "203.0.113.8"},
+		{"cloudflare before forwarded for", true, http.Header{
+			CFConnectingIPHeader: {"203.0.113.7"},
+			ForwardedForHeader:   {"203.0.113.8"},
+		}, "203.0.113.7"},
+		{"invalid cloudflare", true, http.Header{
+			CFConnectingIPHeader: {"unknown"},
+			ForwardedForHeader:   {"203.0.113.8"},
+		}, "203.0.113.8"},
+		{"invalid forwarded for", true, http.Header{ForwardedForHeader: {"unknown, 203.0.113.8"}}, "10.0.0.1"},
+		{"ipv6", true, http.Header{ForwardedForHeader: {" 2001:db8::1 "}}, "2001:db8::1"},
+		{"untrusted", false, http.Header{
+			CFConnectingIPHeader: {"203.0.113.7"},
+			ForwardedForHeader:   {"203.0.113.8"},
+		}, "10.0.0.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			h := RealIP(tt.trustProxy)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = ClientIP(r)
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = "10.0.0.1:54321"
+			for k, vs := range tt.header {
+				for _, v := range vs {
+					req.Header.Add(k, v)
+				}
+			}
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.want {
+				t.Errorf("ClientIP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientIPWithoutRealIP(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:54321"
+	req.Header.Set(ForwardedForHeader, "203.0.113.8")
+
+	// Headers are only read by RealIP, so the direct peer is used
+	if got := ClientIP(req); got != "10.0.0.1" {
+		t.Errorf("ClientIP = %q, want 10.0.0.1", got)
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+)
+
+const (
+	// corsAllowMethods lists the methods accepted in preflight responses
+	corsAllowMethods = "GET, POST, PUT, PATCH, DELETE, OPTIONS"
+	// corsAllowHeaders lists the request headers accepted in preflight responses
+	corsAllowHeaders = "Authorization, Content-Type"
+	// corsMaxAge is how long (in seconds) browsers may cache a preflight response
+	corsMaxAge = 600
+)
+
+// ErrCORSWildcardWithCredentials is returned when "*" is combined with credentials,
+// which browsers refuse and which would expose cookies to every origin
+var ErrCORSWildcardWithCredentials = errors.New(`cors: wildcard origin "*" cannot be used with credentials`)
+
+// CORS creates a middleware that allows cross-origin requests from allowedOrigins.
+// The request origin is echoed back when it is listed; "*" allows any origin
+// but is rejected when allowCredentials is true.
+// Preflight (OPTIONS) requests are answered directly with 204 No Content.
+func CORS(allowedOrigins []string, allowCredentials bool) (func(http.Handler) http.Handler, error) {
+	wildcard := slices.Contains(allowedOrigins, "*")
+	if wildcard && allowCredentials {
+		return nil, ErrCORSWildcardWithCredentials
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			w.Header().Add("Vary", "Origin")
+
+			allowed := origin != "" && (wildcard || slices.Contains(allowedOrigins, origin))
+			if allowed {
+				if wildcard {
+					w.Header().Set("Access-Control-Allow-Origin", "*")
+				} else {
+					w.Header().Set("Access-Control-Allow-Origin", origin)
+				}
+				if allowCredentials {
+					w.Header().Set("Access-Control-Allow-Credentials", "true")
+				}
+			}
+
+			// Preflight request
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				if allowed {
+					w.Header().Set("Access-Control-Allow-Methods", corsAllowMethods)
+					w.Header().Set("Access-Control-Allow-Headers", corsAllowHeaders)
+					w.Header().Set("Access-Control-Max-Age", strconv.Itoa(corsMaxAge))
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}, nil
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+)
+
+// Envelope creates a middleware that wraps successful JSON responses as {"data": ...}.
+// List responses, which carry their entries in "items", become {"data": [...], "meta": {...}}
+// with the remaining fields (total, next_cursor, ...) moved to meta.
+// Error responses and non-JSON bodies are passed through unchanged.
+// When enabled is false the middleware does nothing.
+func Envelope(enabled bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !enabled {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ew := &envelopeWriter{ResponseWriter: w}
+			next.ServeHTTP(ew, r)
+			ew.finish()
+		})
+	}
+}
+
+// envelopeWriter buffers a successful JSON response so it can be wrapped once the handler returns
+type envelopeWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+	// buffering is set when the response is a 2xx JSON body to be wrapped
+	buffering bool
+	status    int
+	buf       bytes.Buffer
+}
+
+func (ew *envelopeWriter) WriteHeader(code int) {
+	if ew.wroteHeader {
+		return
+	}
+	ew.wroteHeader = true
+	if code < 200 || code >= 300 || code == http.StatusNoContent || !isJSON(ew.Header().Get("Content-Type")) {
+		ew.ResponseWriter.WriteHeader(code)
+		return
+	}
+	ew.buffering = true
+	ew.status = code
+}
+
+func (ew *envelopeWriter) Write(b []byte) (int, error) {
+	if !ew.wroteHeader {
+		ew.WriteHeader(http.StatusOK)
+	}
+	if ew.buffering {
+		return ew.buf.Write(b)
+	}
+	return ew.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher; buffered responses are only sent by finish
+func (ew *envelopeWriter) Flush() {
+	if ew.buffering {
+		return
+	}
+	if f, ok := ew.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (ew *envelopeWriter) Unwrap() http.ResponseWriter {
+	return ew.ResponseWriter
+}
+
+// finish writes the buffered response wrapped in the envelope.
+// A body that is not valid JSON is written as it is.
+func (ew *envelopeWriter) finish() {
+	if !ew.buffering {
+		return
+	}
+	body := ew.buf.Bytes()
+	if wrapped, err := wrapEnvelope(body); err == nil {
+		body = append(wrapped, '\n')
+	}
+	ew.Header().Del("Content-Length")
+	ew.ResponseWriter.WriteHeader(ew.status)
+	_, _ = ew.ResponseWriter.Write(body)
+}
+
+// wrapEnvelope wraps a JSON body as {"data": ...}, splitting list responses into data and meta
+func wrapEnvelope(body []byte) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err == nil {
+		if items, ok := fields["items"]; ok {
+			delete(fields, "items")
+			return json.Marshal(struct {
+				Data json.RawMessage            `json:"data"`
+				Meta map[string]json.RawMessage `json:"meta"`
+			}{Data: items, Meta: fields})
+		}
+	}
+	return json.Marshal(struct {
+		Data json.RawMessage `json:"data"`
+	}{Data: bytes.TrimSpace(body)})
+}
+
+// isJSON reports whether contentType is application/json
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "application/json"
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ValidateJSONBody creates a middleware that checks JSON request bodies against schema,
+// a JSON Schema in the dialect of OpenAPI 3.0 schema objects (e.g. nullable instead of
+// type lists). A body that does not match is answered with 400 and code invalid_request,
+// listing every problem in fields by its dotted path (e.g. "articles.3.title"), so the
+// handler never sees a structurally invalid document.
+//
+// Like ValidateRequests, it leaves bodies that are empty or not valid JSON to the handler,
+// which answers them with empty_body or malformed_json.
+func ValidateJSONBody(schema []byte) (func(http.Handler) http.Handler, error) {
+	var s openapi3.Schema
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return nil, fmt.Errorf("json schema: load: %w", err)
+	}
+	if err := s.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("json schema: invalid schema: %w", err)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					writeSpecError(w, http.StatusRequestEntityTooLarge, "request_too_large", fmt.Sprintf("Request body exceeds %d bytes", maxBytesErr.Limit), nil)
+					return
+				}
+				writeSpecError(w, http.StatusBadRequest, "invalid_request", "Failed to read request body", nil)
+				return
+			}
+			// The handler reads the body again
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			var value any
+			if err := json.Unmarshal(body, &value); err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			err = s.VisitJSON(value, openapi3.MultiErrors())
+			if err == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var fields []specFieldError
+			collectSchemaErrors(err, "", &fields)
+			if len(fields) == 0 {
+				fields = append(fields, specFieldError{Field: "body", Message: err.Error()})
+			}
+			writeSpecError(w, http.StatusBadRequest, "invalid_request", "Request body does not match the schema", fields)
+		})
+	}, nil
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/para7/nanaket-cms/internal/usecase"
+)
+
+// DefaultMaintenanceRetryAfter is the Retry-After sent with maintenance responses when none is configured
+const DefaultMaintenanceRetryAfter = 5 * time.Minute
+
+// Maintenance creates a middleware that enforces the maintenance mode of maintenance.
+// In read_only mode only GET, HEAD and OPTIONS requests are served; in down mode nothing is.
+// Rejected requests get 503 with code maintenance and a Retry-After header.
+// Requests to the exempt paths (such as health checks and the endpoint that switches
+// the mode back) are always served.
+func Maintenance(maintenance usecase.MaintenanceUsecase, retryAfter time.Duration, exempt ...string) func(http.Handler) http.Handler {
+	seconds := strconv.Itoa(max(int(math.Ceil(retryAfter.Seconds())), 1))
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if slices.Contains(exempt, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			switch maintenance.CachedMode(r.Context()) {
+			case usecase.MaintenanceDown:
+				w.Header().Set("Retry-After", seconds)
+				writeSpecError(w, http.StatusServiceUnavailable, "maintenance", "Service is down for maintenance", nil)
+				return
+			case usecase.MaintenanceReadOnly:
+				if !isReadMethod(r.Method) {
+					w.Header().Set("Retry-After", seconds)
+					writeSpecError(w, http.StatusServiceUnavailable, "maintenance", "Service is read-only for maintenance", nil)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isReadMethod reports whether method does not change anything on the server
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
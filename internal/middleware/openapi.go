@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+// specFieldError describes one part of a request that does not match the OpenAPI spec
+type specFieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidateRequests creates a middleware that checks requests to operations described in the
+// OpenAPI document spec against it: path, query and header parameters and JSON request bodies.
+// A request that does not match is answered with 400 and code invalid_request, listing every
+// problem in fields; requests to paths the spec does not describe are passed through.
+//
+// Security requirements are not checked here, as the route guards authenticate requests.
+// Bodies that are missing, not JSON or not valid JSON are also left to the handlers, which
+// already answer them with specific codes (empty_body, malformed_json).
+func ValidateRequests(spec []byte) (func(http.Handler) http.Handler, error) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(spec)
+	if err != nil {
+		return nil, fmt.Errorf("openapi: load spec: %w", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("openapi: invalid spec: %w", err)
+	}
+	// Match paths on any host: servers only documents the default local address
+	doc.Servers = nil
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("openapi: build router: %w", err)
+	}
+
+	options := &openapi3filter.Options{
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		MultiError:         true,
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, pathParams, err := router.FindRoute(r)
+			if err != nil {
+				// Not described by the spec
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			err = openapi3filter.ValidateRequest(r.Context(), &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    options,
+			})
+			if err == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				writeSpecError(w, http.StatusRequestEntityTooLarge, "request_too_large", fmt.Sprintf("Request body exceeds %d bytes", maxBytesErr.Limit), nil)
+				return
+			}
+			fields := specFieldErrors(err)
+			if len(fields) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			writeSpecError(w, http.StatusBadRequest, "invalid_request", "Request does not match the API specification", fields)
+		})
+	}, nil
+}
+
+// specFieldErrors flattens the errors returned by openapi3filter into one entry per problem.
+// Body errors the handlers report better themselves are left out.
+func specFieldErrors(err error) []specFieldError {
+	var multi openapi3.MultiError
+	if errors.As(err, &multi) {
+		var fields []specFieldError
+		for _, e := range multi {
+			fields = append(fields, specFieldErrors(e)...)
+		}
+		return fields
+	}
+
+	var reqErr *openapi3filter.RequestError
+	if !errors.As(err, &reqErr) {
+		// Body schema errors are reported on their own when collecting multiple errors
+		var fields []specFieldError
+		collectSchemaErrors(err, "", &fields)
+		if len(fields) == 0 {
+			fields = append(fields, specFieldError{Field: "request", Message: err.Error()})
+		}
+		return fields
+	}
+
+	if reqErr.Parameter != nil {
+		field := reqErr.Parameter.Name
+		var schemaErrs []specFieldError
+		collectSchemaErrors(reqErr.Err, field, &schemaErrs)
+		if len(schemaErrs) > 0 {
+			return schemaErrs
+		}
+		msg := reqErr.Reason
+		if reqErr.Err != nil {
+			msg = reqErr.Err.Error()
+		}
+		return []specFieldError{{Field: field, Message: msg}}
+	}
+
+	// A missing body, another Content-Type or a body that does not decode is left to the handler
+	var parseErr *openapi3filter.ParseError
+	if reqErr.Err == nil || errors.Is(reqErr.Err, openapi3filter.ErrInvalidRequired) || errors.As(reqErr.Err, &parseErr) {
+		return nil
+	}
+	var fields []specFieldError
+	collectSchemaErrors(reqErr.Err, "", &fields)
+	if len(fields) == 0 {
+		fields = append(fields, specFieldError{Field: "body", Message: reqErr.Error()})
+	}
+	return fields
+}
+
+// collectSchemaErrors appends an entry for every schema error in err, naming fields by their
+// dotted path below prefix (e.g. "tags.0")
+func collectSchemaErrors(err error, prefix string, fields *[]specFieldError) {
+	var multi openapi3.MultiError
+	if errors.As(err, &multi) {
+		for _, e := range multi {
+			collectSchemaErrors(e, prefix, fields)
+		}
+		return
+	}
+	var schemaErr *openapi3.SchemaError
+	if !errors.As(err, &schemaErr) {
+		return
+	}
+	path := schemaErr.JSONPointer()
+	if prefix != "" {
+		path = append([]string{prefix}, path...)
+	}
+	field := strings.Join(path, ".")
+	if field == "" {
+		field = "body"
+	}
+	*fields = append(*fields, specFieldError{Field: field, Message: schemaErr.Reason})
+}
+
+// writeSpecError writes an error in the JSON format used by the API
+func writeSpecError(w http.ResponseWriter, status int, code, msg string, fields []specFieldError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(struct {
+		Error     string           `json:"error"`
+		Code      string           `json:"code"`
+		Fields    []specFieldError `json:"fields,omitempty"`
+		RequestID string           `json:"request_id,omitempty"`
+	}{Error: msg, Code: code, Fields: fields, RequestID: w.Header().Get(RequestIDHeader)})
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/para7/nanaket-cms/internal/usecase"
+)
+
+// Quota creates a middleware that counts requests against the daily quota of the
+// authenticated user. It must run after the auth middleware; requests without a user
+// and requests of admins are not counted. Every counted response carries
+// X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset (Unix seconds),
+// and requests over the quota get 429 with code quota_exceeded and a Retry-After header.
+// If the quota cannot be checked, the request is let through like RateLimit does.
+func Quota(quota usecase.QuotaUsecase) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := GetUserFromContext(r.Context())
+			if !ok || user.Role == usecase.UserRoleAdmin {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			now := time.Now()
+			status, err := quota.Consume(r.Context(), user.ID, now)
+			if err != nil {
+				slog.WarnContext(r.Context(), "Quota store unavailable, allowing request", "user_id", user.ID, "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(status.Limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(status.Remaining))
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(status.Reset.Unix(), 10))
+			if !status.Allowed {
+				seconds := max(int(math.Ceil(status.Reset.Sub(now).Seconds())), 1)
+				w.Header().Set("Retry-After", strconv.Itoa(seconds))
+				writeSpecError(w, http.StatusTooManyRequests, "quota_exceeded", "Daily request quota exceeded", nil)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimitStore keeps per-key request history for RateLimit.
+// Implementations must be safe for concurrent use.
+type RateLimitStore interface {
+	// Allow records a request for key at now if fewer than limit requests were
+	// recorded within the sliding window ending at now. When the request is
+	// rejected, retryAfter is the time until the oldest recorded request leaves the window.
+	Allow(ctx context.Context, key string, limit int, window time.Duration, now time.Time) (allowed bool, retryAfter time.Duration, err error)
+}
+
+// RateLimit creates a middleware that allows at most limit requests per client IP (see ClientIP)
+// within a sliding window. Rejected requests get 429 with a Retry-After header.
+// If the store fails, the request is let through so an outage does not lock everyone out.
+func RateLimit(store RateLimitStore, limit int, window time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Method + " " + r.URL.Path + " " + ClientIP(r)
+
+			allowed, retryAfter, err := store.Allow(r.Context(), key, limit, window, time.Now())
+			if err != nil {
+				slog.WarnContext(r.Context(), "Rate limit store unavailable, allowing request", "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !allowed {
+				seconds := max(int(math.Ceil(retryAfter.Seconds())), 1)
+				w.Header().Set("Retry-After", strconv.Itoa(seconds))
+				writeSpecError(w, http.StatusTooManyRequests, "rate_limited", "Too many requests", nil)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// MemoryRateLimitStore is an in-process RateLimitStore.
+// Counts are not shared between server instances.
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	hits      map[string][]time.Time
+	lastSweep time.Time
+}
+
+// NewMemoryRateLimitStore creates a new instance of MemoryRateLimitStore
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		hits: make(map[string][]time.Time),
+	}
+}
+
+// Allow implements RateLimitStore
+func (s *MemoryRateLimitStore) Allow(ctx context.Context, key string, limit int, window time.Duration, now time.Time) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Drop keys that have gone quiet so the map does not grow without bound
+	if now.Sub(s.lastSweep) > window {
+		for k, hits := range s.hits {
+			if len(hits) == 0 || now.Sub(hits[len(hits)-1]) >= window {
+				delete(s.hits, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	hits := s.hits[key]
+	start := 0
+	for start < len(hits) && now.Sub(hits[start]) >= window {
+		start++
+	}
+	hits = hits[start:]
+
+	if len(hits) >= limit {
+		s.hits[key] = hits
+		return false, hits[0].Add(window).Sub(now), nil
+	}
+
+	s.hits[key] = append(hits, now)
+	return true, 0, nil
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+)
+
+const (
+	// RequestIDContextKey is the key for storing the request ID in context
+	RequestIDContextKey ContextKey = "request_id"
+	// RequestIDHeader is the header used to receive and echo the request ID
+	RequestIDHeader = "X-Request-ID"
+	// maxRequestIDLength bounds client-supplied request IDs
+	maxRequestIDLength = 128
+)
+
+// RequestID creates a middleware that assigns every request a correlation ID.
+// A valid incoming X-Request-ID is reused; otherwise a random UUID is generated.
+// The ID is stored in the request context and echoed in the X-Request-ID response header.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !isValidRequestID(id) {
+			id = newUUID()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		ctx := context.WithValue(r.Context(), RequestIDContextKey, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequestIDFromContext retrieves the request ID from the request context.
+// It returns "" if RequestID did not run.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(RequestIDContextKey).(string)
+	return id
+}
+
+// isValidRequestID reports whether id is a non-empty, reasonably sized string
+// of printable ASCII, so it is safe to echo in headers and logs
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// newUUID returns a random (version 4) UUID
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
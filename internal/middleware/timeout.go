@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DefaultRequestTimeout is the request deadline used when none is configured
+const DefaultRequestTimeout = 5 * time.Second
+
+// Timeout creates a middleware that gives every request context a deadline of d.
+// Database queries run with the request context, so a slow query is canceled
+// once the deadline passes. A 500 written after the deadline has passed is
+// assumed to be caused by it and is replaced with a JSON 504 response.
+func Timeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			next.ServeHTTP(&timeoutWriter{ResponseWriter: w, ctx: ctx}, r.WithContext(ctx))
+		})
+	}
+}
+
+// timeoutWriter rewrites a 500 response as 504 when the request deadline has passed
+type timeoutWriter struct {
+	http.ResponseWriter
+	ctx       context.Context
+	rewritten bool
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	if code != http.StatusInternalServerError || !errors.Is(tw.ctx.Err(), context.DeadlineExceeded) {
+		tw.ResponseWriter.WriteHeader(code)
+		return
+	}
+	tw.rewritten = true
+	tw.Header().Set("Content-Type", "application/json")
+	tw.ResponseWriter.WriteHeader(http.StatusGatewayTimeout)
+	_, _ = fmt.Fprintf(tw.ResponseWriter, `{"error":"Request timed out","code":"timeout","request_id":%q}`+"\n", RequestIDFromContext(tw.ctx))
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	if tw.rewritten {
+		// Discard the body of the replaced 500; report it as written
+		return len(b), nil
+	}
+	return tw.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (tw *timeoutWriter) Unwrap() http.ResponseWriter {
+	return tw.ResponseWriter
+}
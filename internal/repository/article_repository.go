@@ -2,18 +2,92 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/para7/nanaket-cms/internal/db"
 )
 
+// Article sort orders accepted by ArticleRepository.ListPaginated.
+// A leading "-" means descending.
+const (
+	ArticleSortIDDesc          = "-id"
+	ArticleSortCreatedAt       = "created_at"
+	ArticleSortCreatedAtDesc   = "-created_at"
+	ArticleSortPublishedAt     = "published_at"
+	ArticleSortPublishedAtDesc = "-published_at"
+	ArticleSortTitle           = "title"
+)
+
+// ArticleCursor is the position of an article in a sort order; ListPaginated continues after it.
+// Besides the ID and pinned flag it holds the article's value of the sort key.
+type ArticleCursor struct {
+	ID     int64
+	Pinned bool
+	// Time is created_at or published_at, following the sort order; nil for a null published_at
+	Time *time.Time
+	// Title is set when sorting by title
+	Title string
+}
+
+// ArticleCursorAt returns the position of article in the sort order sort
+func ArticleCursorAt(sort string, article db.Article) ArticleCursor {
+	c := ArticleCursor{ID: article.ID, Pinned: article.IsPinned}
+	switch sort {
+	case ArticleSortCreatedAt, ArticleSortCreatedAtDesc:
+		t := article.CreatedAt.Time
+		c.Time = &t
+	case ArticleSortPublishedAt, ArticleSortPublishedAtDesc:
+		if article.PublishedAt.Valid {
+			t := article.PublishedAt.Time
+			c.Time = &t
+		}
+	case ArticleSortTitle:
+		c.Title = article.Title
+	}
+	return c
+}
+
 // ArticleRepository defines the interface for article data access
 type ArticleRepository interface {
-	Create(ctx context.Context, userID int64, title, content string, publishedAt pgtype.Timestamp) (db.Article, error)
+	Create(ctx context.Context, userID int64, title, content, status, slug, publicID string, publishedAt *time.Time, featuredImageID, categoryID *int64) (db.Article, error)
 	GetByID(ctx context.Context, id int64) (db.Article, error)
+	GetBySlug(ctx context.Context, slug string) (db.Article, error)
+	GetByPublicID(ctx context.Context, publicID string) (db.Article, error)
+	SlugExists(ctx context.Context, slug string) (bool, error)
 	List(ctx context.Context) ([]db.Article, error)
-	Update(ctx context.Context, id, userID int64, title, content string, publishedAt pgtype.Timestamp) (db.Article, error)
+	ListPaginated(ctx context.Context, sort, status, tag string, categoryIDs []int64, publishedFrom, publishedTo *time.Time, limit int32, after *ArticleCursor) ([]db.Article, error)
+	Count(ctx context.Context, status, tag string, categoryIDs []int64, publishedFrom, publishedTo *time.Time, userID int64) (int64, error)
+	ListForExport(ctx context.Context, status, tag string, userID, afterID int64, limit int32) ([]db.Article, error)
+	ArchiveMonths(ctx context.Context) ([]db.ListArchiveMonthsRow, error)
+	CountByAuthor(ctx context.Context, status string, includeEmpty bool) ([]db.CountArticlesByAuthorRow, error)
+	Search(ctx context.Context, pattern string, limit int32) ([]db.Article, error)
+	ListRelated(ctx context.Context, id int64, limit int32) ([]db.Article, error)
+	ListRecentByAuthor(ctx context.Context, userID, excludeID int64, limit int32) ([]db.Article, error)
+	ListScheduled(ctx context.Context) ([]db.Article, error)
+	PublishScheduled(ctx context.Context, id int64) (db.Article, error)
+	Publish(ctx context.Context, id int64) (db.Article, error)
+	Unpublish(ctx context.Context, id int64) (db.Article, error)
+	Transfer(ctx context.Context, id, newUserID int64) (db.Article, error)
+	TransferAllByUser(ctx context.Context, userID, newUserID int64) (int64, error)
+	Update(ctx context.Context, id, userID int64, title, content, status string, publishedAt *time.Time, featuredImageID, categoryID *int64, expectedVersion *int32) (db.Article, error)
+	IncrementViewCount(ctx context.Context, id int64) error
 	Delete(ctx context.Context, id int64) error
+	DeleteArticles(ctx context.Context, ids []int64) ([]int64, error)
+	Restore(ctx context.Context, id int64) (db.Article, error)
+	LockPinned(ctx context.Context) error
+	CountPinned(ctx context.Context) (int64, error)
+	SetPinned(ctx context.Context, id int64, pinned bool) (db.Article, error)
+	HardDelete(ctx context.Context, id int64) error
+	ListAll(ctx context.Context) ([]db.Article, error)
+	ExistingIDs(ctx context.Context, ids []int64) ([]int64, error)
+	ExistingSlugs(ctx context.Context, slugs []string) ([]string, error)
+	Import(ctx context.Context, article db.Article, preserveID bool) (db.Article, error)
+	SyncIDSequence(ctx context.Context) error
+	ListWithoutPublicID(ctx context.Context, limit int32) ([]int64, error)
+	SetPublicID(ctx context.Context, id int64, publicID string) error
 }
 
 // articleRepository implements ArticleRepository interface
@@ -29,13 +103,19 @@ func NewArticleRepository(querier db.Querier) ArticleRepository {
 }
 
 // Create creates a new article
-func (r *articleRepository) Create(ctx context.Context, userID int64, title, content string, publishedAt pgtype.Timestamp) (db.Article, error) {
-	return r.querier.CreateArticle(ctx, db.CreateArticleParams{
-		UserID:      userID,
-		Title:       title,
-		Content:     content,
-		PublishedAt: publishedAt,
+func (r *articleRepository) Create(ctx context.Context, userID int64, title, content, status, slug, publicID string, publishedAt *time.Time, featuredImageID, categoryID *int64) (db.Article, error) {
+	article, err := r.querier.CreateArticle(ctx, db.CreateArticleParams{
+		UserID:          userID,
+		Title:           title,
+		Content:         content,
+		PublishedAt:     timestamp(publishedAt),
+		Status:          status,
+		Slug:            &slug,
+		FeaturedImageID: featuredImageID,
+		CategoryID:      categoryID,
+		PublicID:        &publicID,
 	})
+	return article, translateError(err)
 }
 
 // GetByID retrieves an article by ID
@@ -43,23 +123,413 @@ func (r *articleRepository) GetByID(ctx context.Context, id int64) (db.Article,
 	return r.querier.GetArticle(ctx, id)
 }
 
+// GetBySlug retrieves an article by slug
+func (r *articleRepository) GetBySlug(ctx context.Context, slug string) (db.Article, error) {
+	return r.querier.GetArticleBySlug(ctx, &slug)
+}
+
+// GetByPublicID retrieves an article by its public ID
+func (r *articleRepository) GetByPublicID(ctx context.Context, publicID string) (db.Article, error) {
+	return r.querier.GetArticleByPublicID(ctx, &publicID)
+}
+
+// SlugExists reports whether any article, including soft-deleted ones, uses slug
+func (r *articleRepository) SlugExists(ctx context.Context, slug string) (bool, error) {
+	return r.querier.ArticleSlugExists(ctx, &slug)
+}
+
 // List retrieves all articles
 func (r *articleRepository) List(ctx context.Context) ([]db.Article, error) {
 	return r.querier.ListArticles(ctx)
 }
 
-// Update updates an article
-func (r *articleRepository) Update(ctx context.Context, id, userID int64, title, content string, publishedAt pgtype.Timestamp) (db.Article, error) {
+// ListPaginated retrieves up to limit articles with the given status that come after the
+// cursor after in the sort order; a nil cursor starts at the first article.
+// Published articles whose published_at is still in the future are left out.
+// sort must be one of the ArticleSort constants; each maps to its own query.
+// Pinned articles come first whatever the sort order, and ties are broken by ID, newest first,
+// so the order is total and pages neither repeat nor skip articles. Paging is keyset based:
+// articles added or removed before the cursor do not shift the following pages.
+// An empty tag disables tag filtering, and nil categoryIDs disables category filtering.
+// publishedFrom and publishedTo bound published_at inclusively; nil leaves that side open.
+func (r *articleRepository) ListPaginated(ctx context.Context, sort, status, tag string, categoryIDs []int64, publishedFrom, publishedTo *time.Time, limit int32, after *ArticleCursor) ([]db.Article, error) {
+	var tagFilter *string
+	if tag != "" {
+		tagFilter = &tag
+	}
+	var cursorID *int64
+	var cursorPinned bool
+	var cursorTime pgtype.Timestamp
+	var cursorTitle *string
+	if after != nil {
+		cursorID = &after.ID
+		cursorPinned = after.Pinned
+		cursorTime = timestamp(after.Time)
+		cursorTitle = &after.Title
+	}
+
+	switch sort {
+	case ArticleSortIDDesc:
+		return r.querier.ListArticlesByID(ctx, db.ListArticlesByIDParams{
+			Status:        status,
+			Tag:           tagFilter,
+			CategoryIds:   categoryIDs,
+			PublishedFrom: timestamp(publishedFrom),
+			PublishedTo:   timestamp(publishedTo),
+			CursorID:      cursorID,
+			CursorPinned:  cursorPinned,
+			MaxResults:    limit,
+		})
+	case ArticleSortCreatedAt:
+		return r.querier.ListArticlesByCreatedAt(ctx, db.ListArticlesByCreatedAtParams{
+			Status:        status,
+			Tag:           tagFilter,
+			CategoryIds:   categoryIDs,
+			PublishedFrom: timestamp(publishedFrom),
+			PublishedTo:   timestamp(publishedTo),
+			CursorID:      cursorID,
+			CursorPinned:  cursorPinned,
+			CursorTime:    cursorTime,
+			MaxResults:    limit,
+		})
+	case ArticleSortCreatedAtDesc:
+		return r.querier.ListArticlesByCreatedAtDesc(ctx, db.ListArticlesByCreatedAtDescParams{
+			Status:        status,
+			Tag:           tagFilter,
+			CategoryIds:   categoryIDs,
+			PublishedFrom: timestamp(publishedFrom),
+			PublishedTo:   timestamp(publishedTo),
+			CursorID:      cursorID,
+			CursorPinned:  cursorPinned,
+			CursorTime:    cursorTime,
+			MaxResults:    limit,
+		})
+	case ArticleSortPublishedAt:
+		return r.querier.ListArticlesByPublishedAt(ctx, db.ListArticlesByPublishedAtParams{
+			Status:        status,
+			Tag:           tagFilter,
+			CategoryIds:   categoryIDs,
+			PublishedFrom: timestamp(publishedFrom),
+			PublishedTo:   timestamp(publishedTo),
+			CursorID:      cursorID,
+			CursorPinned:  cursorPinned,
+			CursorTime:    cursorTime,
+			MaxResults:    limit,
+		})
+	case ArticleSortPublishedAtDesc:
+		return r.querier.ListArticlesByPublishedAtDesc(ctx, db.ListArticlesByPublishedAtDescParams{
+			Status:        status,
+			Tag:           tagFilter,
+			CategoryIds:   categoryIDs,
+			PublishedFrom: timestamp(publishedFrom),
+			PublishedTo:   timestamp(publishedTo),
+			CursorID:      cursorID,
+			CursorPinned:  cursorPinned,
+			CursorTime:    cursorTime,
+			MaxResults:    limit,
+		})
+	case ArticleSortTitle:
+		return r.querier.ListArticlesByTitle(ctx, db.ListArticlesByTitleParams{
+			Status:        status,
+			Tag:           tagFilter,
+			CategoryIds:   categoryIDs,
+			PublishedFrom: timestamp(publishedFrom),
+			PublishedTo:   timestamp(publishedTo),
+			CursorID:      cursorID,
+			CursorPinned:  cursorPinned,
+			CursorTitle:   cursorTitle,
+			MaxResults:    limit,
+		})
+	}
+	return nil, fmt.Errorf("unknown article sort %q", sort)
+}
+
+// Count counts articles with the given status, using the same visibility rules and
+// tag, category and published_at filters as ListPaginated. A zero userID disables author filtering.
+func (r *articleRepository) Count(ctx context.Context, status, tag string, categoryIDs []int64, publishedFrom, publishedTo *time.Time, userID int64) (int64, error) {
+	var userFilter *int64
+	if userID != 0 {
+		userFilter = &userID
+	}
+	var tagFilter *string
+	if tag != "" {
+		tagFilter = &tag
+	}
+
+	return r.querier.CountArticles(ctx, db.CountArticlesParams{
+		Status:        status,
+		UserID:        userFilter,
+		Tag:           tagFilter,
+		CategoryIds:   categoryIDs,
+		PublishedFrom: timestamp(publishedFrom),
+		PublishedTo:   timestamp(publishedTo),
+	})
+}
+
+// ArchiveMonths counts the visible published articles per year and month of published_at,
+// newest month first. Articles without published_at are not counted.
+func (r *articleRepository) ArchiveMonths(ctx context.Context) ([]db.ListArchiveMonthsRow, error) {
+	return r.querier.ListArchiveMonths(ctx)
+}
+
+// CountByAuthor counts the visible articles of each active user, most articles first.
+// An empty status counts every status. Users without matching articles are only
+// included when includeEmpty is true.
+func (r *articleRepository) CountByAuthor(ctx context.Context, status string, includeEmpty bool) ([]db.CountArticlesByAuthorRow, error) {
+	var statusFilter *string
+	if status != "" {
+		statusFilter = &status
+	}
+	return r.querier.CountArticlesByAuthor(ctx, db.CountArticlesByAuthorParams{
+		Status:       statusFilter,
+		IncludeEmpty: includeEmpty,
+	})
+}
+
+// ListForExport retrieves up to limit articles with IDs above afterID in ID order,
+// using the same visibility rules and tag filter as ListPaginated.
+// A zero userID disables author filtering.
+func (r *articleRepository) ListForExport(ctx context.Context, status, tag string, userID, afterID int64, limit int32) ([]db.Article, error) {
+	var userFilter *int64
+	if userID != 0 {
+		userFilter = &userID
+	}
+	var tagFilter *string
+	if tag != "" {
+		tagFilter = &tag
+	}
+
+	return r.querier.ListArticlesForExport(ctx, db.ListArticlesForExportParams{
+		Status:     status,
+		UserID:     userFilter,
+		Tag:        tagFilter,
+		AfterID:    afterID,
+		MaxResults: limit,
+	})
+}
+
+// Search retrieves published articles whose title or content matches the ILIKE pattern
+func (r *articleRepository) Search(ctx context.Context, pattern string, limit int32) ([]db.Article, error) {
+	return r.querier.SearchArticles(ctx, db.SearchArticlesParams{
+		Pattern:    pattern,
+		MaxResults: limit,
+	})
+}
+
+// ListRelated retrieves published articles sharing tags with article id, those sharing the most first
+func (r *articleRepository) ListRelated(ctx context.Context, id int64, limit int32) ([]db.Article, error) {
+	return r.querier.ListRelatedArticles(ctx, db.ListRelatedArticlesParams{
+		ArticleID:  id,
+		MaxResults: limit,
+	})
+}
+
+// ListRecentByAuthor retrieves the latest published articles of a user, except excludeID
+func (r *articleRepository) ListRecentByAuthor(ctx context.Context, userID, excludeID int64, limit int32) ([]db.Article, error) {
+	return r.querier.ListRecentArticlesByAuthor(ctx, db.ListRecentArticlesByAuthorParams{
+		UserID:     userID,
+		ExcludeID:  excludeID,
+		MaxResults: limit,
+	})
+}
+
+// ListScheduled retrieves drafts whose published_at has passed, oldest first
+func (r *articleRepository) ListScheduled(ctx context.Context) ([]db.Article, error) {
+	return r.querier.ListScheduledArticles(ctx)
+}
+
+// PublishScheduled publishes a draft whose published_at has passed.
+// It returns sql.ErrNoRows if the article is no longer such a draft.
+func (r *articleRepository) PublishScheduled(ctx context.Context, id int64) (db.Article, error) {
+	return r.querier.PublishScheduledArticle(ctx, id)
+}
+
+// Publish publishes an article with published_at set to now.
+// It returns sql.ErrNoRows if the article is missing or already published and visible.
+func (r *articleRepository) Publish(ctx context.Context, id int64) (db.Article, error) {
+	return r.querier.PublishArticle(ctx, id)
+}
+
+// Unpublish turns an article back into a draft without published_at.
+// It returns sql.ErrNoRows if the article is missing or already such a draft.
+func (r *articleRepository) Unpublish(ctx context.Context, id int64) (db.Article, error) {
+	return r.querier.UnpublishArticle(ctx, id)
+}
+
+// Transfer makes newUserID the author of an article.
+// It returns sql.ErrNoRows if the article is missing or newUserID is already its author.
+func (r *articleRepository) Transfer(ctx context.Context, id, newUserID int64) (db.Article, error) {
+	return r.querier.TransferArticle(ctx, db.TransferArticleParams{
+		NewUserID: newUserID,
+		ID:        id,
+	})
+}
+
+// TransferAllByUser makes newUserID the author of every article of userID, including
+// soft-deleted ones, and returns the number of articles moved
+func (r *articleRepository) TransferAllByUser(ctx context.Context, userID, newUserID int64) (int64, error) {
+	return r.querier.TransferArticlesByUser(ctx, db.TransferArticlesByUserParams{
+		NewUserID: newUserID,
+		UserID:    userID,
+	})
+}
+
+// Update updates an article and increments its version.
+// When expectedVersion is non-nil the update only applies if the article is still at
+// that version; otherwise, as for a missing article, sql.ErrNoRows is returned.
+func (r *articleRepository) Update(ctx context.Context, id, userID int64, title, content, status string, publishedAt *time.Time, featuredImageID, categoryID *int64, expectedVersion *int32) (db.Article, error) {
 	return r.querier.UpdateArticle(ctx, db.UpdateArticleParams{
-		ID:          id,
-		UserID:      userID,
-		Title:       title,
-		Content:     content,
-		PublishedAt: publishedAt,
+		ID:              id,
+		UserID:          userID,
+		Title:           title,
+		Content:         content,
+		PublishedAt:     timestamp(publishedAt),
+		Status:          status,
+		FeaturedImageID: featuredImageID,
+		CategoryID:      categoryID,
+		ExpectedVersion: expectedVersion,
 	})
 }
 
-// Delete deletes an article
+// IncrementViewCount adds one to an article's view_count without touching updated_at
+func (r *articleRepository) IncrementViewCount(ctx context.Context, id int64) error {
+	return r.querier.IncrementArticleViewCount(ctx, id)
+}
+
+// Delete soft-deletes an article by setting its deleted_at.
+// It returns sql.ErrNoRows if there is no live article with the given ID.
 func (r *articleRepository) Delete(ctx context.Context, id int64) error {
-	return r.querier.DeleteArticle(ctx, id)
+	rows, err := r.querier.SoftDeleteArticle(ctx, id)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteArticles soft-deletes the live articles among ids in a single statement
+// and returns the IDs that were deleted
+func (r *articleRepository) DeleteArticles(ctx context.Context, ids []int64) ([]int64, error) {
+	return r.querier.SoftDeleteArticles(ctx, ids)
+}
+
+// Restore clears deleted_at on a soft-deleted article
+func (r *articleRepository) Restore(ctx context.Context, id int64) (db.Article, error) {
+	return r.querier.RestoreArticle(ctx, id)
+}
+
+// LockPinned blocks until no other transaction holds the pin lock, and holds it until
+// the surrounding transaction ends, so pin limit checks do not race
+func (r *articleRepository) LockPinned(ctx context.Context) error {
+	return r.querier.LockPinnedArticles(ctx)
+}
+
+// CountPinned counts the live pinned articles
+func (r *articleRepository) CountPinned(ctx context.Context) (int64, error) {
+	return r.querier.CountPinnedArticles(ctx)
+}
+
+// SetPinned pins or unpins a live article
+func (r *articleRepository) SetPinned(ctx context.Context, id int64, pinned bool) (db.Article, error) {
+	return r.querier.SetArticlePinned(ctx, db.SetArticlePinnedParams{
+		IsPinned: pinned,
+		ID:       id,
+	})
+}
+
+// HardDelete permanently removes an article, whether soft-deleted or not.
+// It returns sql.ErrNoRows if there is no article with the given ID.
+func (r *articleRepository) HardDelete(ctx context.Context, id int64) error {
+	rows, err := r.querier.HardDeleteArticle(ctx, id)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// timestamp converts an optional time into a nullable pgtype.Timestamp
+func timestamp(t *time.Time) pgtype.Timestamp {
+	if t == nil {
+		return pgtype.Timestamp{}
+	}
+	return pgtype.Timestamp{Time: *t, Valid: true}
+}
+
+// ListAll retrieves every article, including soft-deleted ones, in ID order
+func (r *articleRepository) ListAll(ctx context.Context) ([]db.Article, error) {
+	return r.querier.ListAllArticles(ctx)
+}
+
+// ExistingIDs returns those of ids already used by an article, including soft-deleted ones
+func (r *articleRepository) ExistingIDs(ctx context.Context, ids []int64) ([]int64, error) {
+	return r.querier.ListExistingArticleIDs(ctx, ids)
+}
+
+// ExistingSlugs returns those of slugs already used by an article, including soft-deleted ones
+func (r *articleRepository) ExistingSlugs(ctx context.Context, slugs []string) ([]string, error) {
+	found, err := r.querier.ListExistingArticleSlugs(ctx, slugs)
+	if err != nil {
+		return nil, err
+	}
+	existing := make([]string, 0, len(found))
+	for _, slug := range found {
+		if slug != nil {
+			existing = append(existing, *slug)
+		}
+	}
+	return existing, nil
+}
+
+// Import inserts an article from a backup with its timestamps, view count, version and deletion state.
+// With preserveID the article keeps its ID; call SyncIDSequence afterwards.
+// Otherwise a new ID is assigned.
+func (r *articleRepository) Import(ctx context.Context, article db.Article, preserveID bool) (db.Article, error) {
+	var id *int64
+	if preserveID {
+		id = &article.ID
+	}
+
+	imported, err := r.querier.ImportArticle(ctx, db.ImportArticleParams{
+		ID:              id,
+		UserID:          article.UserID,
+		Title:           article.Title,
+		Content:         article.Content,
+		PublishedAt:     article.PublishedAt,
+		CreatedAt:       article.CreatedAt,
+		UpdatedAt:       article.UpdatedAt,
+		Status:          article.Status,
+		Slug:            article.Slug,
+		DeletedAt:       article.DeletedAt,
+		ViewCount:       article.ViewCount,
+		FeaturedImageID: article.FeaturedImageID,
+		Version:         article.Version,
+		CategoryID:      article.CategoryID,
+		IsPinned:        article.IsPinned,
+		PublicID:        article.PublicID,
+	})
+	return imported, translateError(err)
+}
+
+// SyncIDSequence advances the article ID sequence past the largest ID in use
+func (r *articleRepository) SyncIDSequence(ctx context.Context) error {
+	return r.querier.SyncArticleIDSequence(ctx)
+}
+
+// ListWithoutPublicID returns the IDs of up to limit articles, including soft-deleted ones, that have no public ID
+func (r *articleRepository) ListWithoutPublicID(ctx context.Context, limit int32) ([]int64, error) {
+	return r.querier.ListArticlesWithoutPublicID(ctx, limit)
+}
+
+// SetPublicID assigns publicID to an article that has none; an article that already has one is left unchanged
+func (r *articleRepository) SetPublicID(ctx context.Context, id int64, publicID string) error {
+	_, err := r.querier.SetArticlePublicID(ctx, db.SetArticlePublicIDParams{
+		ID:       id,
+		PublicID: &publicID,
+	})
+	return translateError(err)
 }
@@ -0,0 +1,131 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/para7/nanaket-cms/internal/db"
+	"github.com/para7/nanaket-cms/internal/db/mock"
+	"github.com/para7/nanaket-cms/internal/repository"
+)
+
+func TestArticlePublishedAtRoundTrip(t *testing.T) {
+	set := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		publishedAt *time.Time
+	}{
+		{"null", nil},
+		{"set", &set},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The querier keeps the created row, as the articles table would
+			var stored db.Article
+			repo := repository.NewArticleRepository(&mock.Querier{
+				CreateArticleFunc: func(ctx context.Context, arg db.CreateArticleParams) (db.Article, error) {
+					stored = db.Article{ID: 1, UserID: arg.UserID, Title: arg.Title, Content: arg.Content, Status: arg.Status, PublishedAt: arg.PublishedAt}
+					return stored, nil
+				},
+				GetArticleFunc: func(ctx context.Context, id int64) (db.Article, error) {
+					return stored, nil
+				},
+			})
+
+			if _, err := repo.Create(context.Background(), 1, "Title", "Content", "draft", "title", "01HZX3C5R6K9T1V2W3X4Y5Z6A7", tt.publishedAt, nil, nil); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			got, err := repo.GetByID(context.Background(), 1)
+			if err != nil {
+				t.Fatalf("GetByID: %v", err)
+			}
+
+			if tt.publishedAt == nil {
+				if got.PublishedAt.Valid {
+					t.Errorf("published_at = %v, want NULL", got.PublishedAt.Time)
+				}
+				return
+			}
+			if !got.PublishedAt.Valid || !got.PublishedAt.Time.Equal(*tt.publishedAt) {
+				t.Errorf("published_at = %v (valid %v), want %v", got.PublishedAt.Time, got.PublishedAt.Valid, *tt.publishedAt)
+			}
+		})
+	}
+}
+
+func TestArticleHardDeleteMissing(t *testing.T) {
+	repo := repository.NewArticleRepository(&mock.Querier{
+		HardDeleteArticleFunc: func(ctx context.Context, id int64) (int64, error) {
+			return 0, nil
+		},
+	})
+
+	if err := repo.HardDelete(context.Background(), 1); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("got %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestArticlePagesWithEqualSortKeys(t *testing.T) {
+	ctx := context.Background()
+	tx := testTx(t)
+	repo := repository.NewArticleRepository(db.New(tx))
+
+	var userID, categoryID int64
+	if err := tx.QueryRow(ctx, "INSERT INTO users (email, name) VALUES ('pages@example.com', 'Pages') RETURNING id").Scan(&userID); err != nil {
+		t.Fatalf("insert user: %v", err)
+	}
+	// The articles are listed by their category so rows already in the database stay out
+	if err := tx.QueryRow(ctx, "INSERT INTO categories (name, slug) VALUES ('Pages', 'pages-test') RETURNING id").Scan(&categoryID); err != nil {
+		t.Fatalf("insert category: %v", err)
+	}
+	// created_at is the transaction time and the title and published_at are shared,
+	// so every sort key ties and only the ID orders the articles
+	publishedAt := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	var want []int64
+	for i := range 5 {
+		slug := fmt.Sprintf("equal-sort-keys-%d", i)
+		a, err := repo.Create(ctx, userID, "Same title", "Content", "published", slug, fmt.Sprintf("01HZX3C5R6K9T1V2W3X4Y5Z6A%d", i), &publishedAt, nil, &categoryID)
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		want = append([]int64{a.ID}, want...)
+	}
+
+	sorts := []string{
+		repository.ArticleSortIDDesc,
+		repository.ArticleSortCreatedAt,
+		repository.ArticleSortCreatedAtDesc,
+		repository.ArticleSortPublishedAt,
+		repository.ArticleSortPublishedAtDesc,
+		repository.ArticleSortTitle,
+	}
+	for _, sort := range sorts {
+		t.Run(sort, func(t *testing.T) {
+			var seen []int64
+			var cursor *repository.ArticleCursor
+			for range len(want) {
+				page, err := repo.ListPaginated(ctx, sort, "published", "", []int64{categoryID}, nil, nil, 2, cursor)
+				if err != nil {
+					t.Fatalf("ListPaginated: %v", err)
+				}
+				if len(page) == 0 {
+					break
+				}
+				for _, a := range page {
+					seen = append(seen, a.ID)
+				}
+				next := repository.ArticleCursorAt(sort, page[len(page)-1])
+				cursor = &next
+			}
+			if !slices.Equal(seen, want) {
+				t.Errorf("pages returned %v, want %v", seen, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/para7/nanaket-cms/internal/db"
+)
+
+// ArticleRevisionRepository defines the interface for article revision data access
+type ArticleRevisionRepository interface {
+	Snapshot(ctx context.Context, articleID int64) error
+	GetByID(ctx context.Context, articleID, id int64) (db.ArticleRevision, error)
+	List(ctx context.Context, articleID int64) ([]db.ArticleRevision, error)
+}
+
+// articleRevisionRepository implements ArticleRevisionRepository interface
+type articleRevisionRepository struct {
+	querier db.Querier
+}
+
+// NewArticleRevisionRepository creates a new instance of ArticleRevisionRepository
+func NewArticleRevisionRepository(querier db.Querier) ArticleRevisionRepository {
+	return &articleRevisionRepository{
+		querier: querier,
+	}
+}
+
+// Snapshot saves the current title and content of an article as a revision.
+// It locks the article row until the transaction ends, so it must run in the same
+// transaction as the update it precedes. A missing or deleted article yields sql.ErrNoRows.
+func (r *articleRevisionRepository) Snapshot(ctx context.Context, articleID int64) error {
+	rows, err := r.querier.CreateArticleRevision(ctx, articleID)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetByID retrieves a revision of the given article
+func (r *articleRevisionRepository) GetByID(ctx context.Context, articleID, id int64) (db.ArticleRevision, error) {
+	return r.querier.GetArticleRevision(ctx, db.GetArticleRevisionParams{
+		ID:        id,
+		ArticleID: articleID,
+	})
+}
+
+// List retrieves the revisions of an article, newest first
+func (r *articleRevisionRepository) List(ctx context.Context, articleID int64) ([]db.ArticleRevision, error) {
+	return r.querier.ListArticleRevisions(ctx, articleID)
+}
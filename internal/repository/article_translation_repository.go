@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/para7/nanaket-cms/internal/db"
+)
+
+// ArticleTranslationRepository defines the interface for article translation data access
+type ArticleTranslationRepository interface {
+	Upsert(ctx context.Context, articleID int64, locale, title, content string) (db.ArticleTranslation, error)
+	ListByArticles(ctx context.Context, articleIDs []int64, locales []string) (map[int64]map[string]db.ArticleTranslation, error)
+}
+
+// articleTranslationRepository implements ArticleTranslationRepository interface
+type articleTranslationRepository struct {
+	querier db.Querier
+}
+
+// NewArticleTranslationRepository creates a new instance of ArticleTranslationRepository
+func NewArticleTranslationRepository(querier db.Querier) ArticleTranslationRepository {
+	return &articleTranslationRepository{
+		querier: querier,
+	}
+}
+
+// Upsert creates the translation of an article into locale, or replaces it if one exists
+func (r *articleTranslationRepository) Upsert(ctx context.Context, articleID int64, locale, title, content string) (db.ArticleTranslation, error) {
+	translation, err := r.querier.UpsertArticleTranslation(ctx, db.UpsertArticleTranslationParams{
+		ArticleID: articleID,
+		Locale:    locale,
+		Title:     title,
+		Content:   content,
+	})
+	return translation, translateError(err)
+}
+
+// ListByArticles retrieves the translations of several articles into any of locales in one query,
+// keyed by article ID and then by locale
+func (r *articleTranslationRepository) ListByArticles(ctx context.Context, articleIDs []int64, locales []string) (map[int64]map[string]db.ArticleTranslation, error) {
+	rows, err := r.querier.ListArticleTranslations(ctx, db.ListArticleTranslationsParams{
+		ArticleIds: articleIDs,
+		Locales:    locales,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[int64]map[string]db.ArticleTranslation)
+	for _, row := range rows {
+		if result[row.ArticleID] == nil {
+			result[row.ArticleID] = make(map[string]db.ArticleTranslation)
+		}
+		result[row.ArticleID][row.Locale] = row
+	}
+	return result, nil
+}
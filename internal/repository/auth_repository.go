@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/para7/nanaket-cms/internal/db"
+)
+
+// AuthRepository defines the interface for access token and password data access.
+// Tokens and passwords are stored as hashes; plaintext never reaches the database.
+type AuthRepository interface {
+	CreateToken(ctx context.Context, userID int64, tokenHash string, expiresAt pgtype.Timestamp) (db.AccessToken, error)
+	GetUserByToken(ctx context.Context, tokenHash string) (db.User, error)
+	GetToken(ctx context.Context, tokenHash string) (db.AccessToken, error)
+	RefreshToken(ctx context.Context, oldTokenHash, newTokenHash string, expiresAt pgtype.Timestamp) (db.AccessToken, error)
+	ListTokensByUser(ctx context.Context, userID int64) ([]db.AccessToken, error)
+	DeleteTokenByID(ctx context.Context, userID, tokenID int64) error
+	TouchToken(ctx context.Context, tokenHash string) error
+	GetUserByID(ctx context.Context, id int64) (db.User, error)
+	GetUserByEmail(ctx context.Context, email string) (db.User, error)
+	SetPasswordHash(ctx context.Context, userID int64, passwordHash *string) error
+	SetEmail(ctx context.Context, userID int64, email string) (db.User, error)
+}
+
+// authRepository implements AuthRepository interface
+type authRepository struct {
+	querier db.Querier
+}
+
+// NewAuthRepository creates a new instance of AuthRepository
+func NewAuthRepository(querier db.Querier) AuthRepository {
+	return &authRepository{
+		querier: querier,
+	}
+}
+
+// CreateToken stores a new token for a user
+func (r *authRepository) CreateToken(ctx context.Context, userID int64, tokenHash string, expiresAt pgtype.Timestamp) (db.AccessToken, error) {
+	token, err := r.querier.CreateAccessToken(ctx, db.CreateAccessTokenParams{
+		UserID:    userID,
+		Token:     tokenHash,
+		ExpiresAt: expiresAt,
+	})
+	return token, translateError(err)
+}
+
+// GetUserByToken retrieves the owner of a non-expired token
+func (r *authRepository) GetUserByToken(ctx context.Context, tokenHash string) (db.User, error) {
+	return r.querier.GetUserByToken(ctx, tokenHash)
+}
+
+// GetToken retrieves a non-expired token
+func (r *authRepository) GetToken(ctx context.Context, tokenHash string) (db.AccessToken, error) {
+	return r.querier.GetAccessToken(ctx, tokenHash)
+}
+
+// RefreshToken replaces a non-expired token with a new one in a single statement.
+// It returns sql.ErrNoRows if the old token is unknown or expired.
+func (r *authRepository) RefreshToken(ctx context.Context, oldTokenHash, newTokenHash string, expiresAt pgtype.Timestamp) (db.AccessToken, error) {
+	return r.querier.RefreshToken(ctx, db.RefreshTokenParams{
+		OldToken:  oldTokenHash,
+		NewToken:  newTokenHash,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// ListTokensByUser retrieves a user's non-expired tokens, newest first
+func (r *authRepository) ListTokensByUser(ctx context.Context, userID int64) ([]db.AccessToken, error) {
+	return r.querier.ListAccessTokensByUser(ctx, userID)
+}
+
+// DeleteTokenByID deletes one of a user's tokens.
+// It returns sql.ErrNoRows if the user has no token with that ID.
+func (r *authRepository) DeleteTokenByID(ctx context.Context, userID, tokenID int64) error {
+	rows, err := r.querier.DeleteAccessTokenByID(ctx, db.DeleteAccessTokenByIDParams{
+		ID:     tokenID,
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// TouchToken records that a token was just used.
+// Updates within a minute of the previous one are skipped to limit writes.
+func (r *authRepository) TouchToken(ctx context.Context, tokenHash string) error {
+	return r.querier.TouchAccessToken(ctx, tokenHash)
+}
+
+// GetUserByID retrieves a live user with their password hash
+func (r *authRepository) GetUserByID(ctx context.Context, id int64) (db.User, error) {
+	return r.querier.GetUser(ctx, id)
+}
+
+// GetUserByEmail retrieves a user, including soft-deleted ones, with their password hash
+func (r *authRepository) GetUserByEmail(ctx context.Context, email string) (db.User, error) {
+	return r.querier.GetUserByEmail(ctx, email)
+}
+
+// SetPasswordHash replaces a user's password hash; nil removes the password.
+// It returns sql.ErrNoRows if there is no live user with the given ID.
+func (r *authRepository) SetPasswordHash(ctx context.Context, userID int64, passwordHash *string) error {
+	rows, err := r.querier.SetUserPassword(ctx, db.SetUserPasswordParams{
+		ID:           userID,
+		PasswordHash: passwordHash,
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// SetEmail replaces a user's email with one the user has confirmed.
+// It returns sql.ErrNoRows if there is no live user with the given ID and
+// ErrDuplicateKey if another user has the email by now.
+func (r *authRepository) SetEmail(ctx context.Context, userID int64, email string) (db.User, error) {
+	user, err := r.querier.SetUserEmail(ctx, db.SetUserEmailParams{
+		ID:    userID,
+		Email: email,
+	})
+	return user, translateError(err)
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/para7/nanaket-cms/internal/db"
+)
+
+// CategoryRepository defines the interface for category data access
+type CategoryRepository interface {
+	Create(ctx context.Context, name, slug string, parentID *int64) (db.Category, error)
+	GetByID(ctx context.Context, id int64) (db.Category, error)
+	List(ctx context.Context) ([]db.Category, error)
+	Update(ctx context.Context, id int64, name, slug string, parentID *int64) (db.Category, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// categoryRepository implements CategoryRepository interface
+type categoryRepository struct {
+	querier db.Querier
+}
+
+// NewCategoryRepository creates a new instance of CategoryRepository
+func NewCategoryRepository(querier db.Querier) CategoryRepository {
+	return &categoryRepository{
+		querier: querier,
+	}
+}
+
+// Create creates a new category.
+// It returns ErrDuplicateKey if the slug is taken.
+func (r *categoryRepository) Create(ctx context.Context, name, slug string, parentID *int64) (db.Category, error) {
+	category, err := r.querier.CreateCategory(ctx, db.CreateCategoryParams{
+		Name:     name,
+		Slug:     slug,
+		ParentID: parentID,
+	})
+	return category, translateError(err)
+}
+
+// GetByID retrieves a category by ID
+func (r *categoryRepository) GetByID(ctx context.Context, id int64) (db.Category, error) {
+	return r.querier.GetCategory(ctx, id)
+}
+
+// List retrieves all categories ordered by ID
+func (r *categoryRepository) List(ctx context.Context) ([]db.Category, error) {
+	return r.querier.ListCategories(ctx)
+}
+
+// Update updates a category.
+// It returns ErrDuplicateKey if the slug is taken.
+func (r *categoryRepository) Update(ctx context.Context, id int64, name, slug string, parentID *int64) (db.Category, error) {
+	category, err := r.querier.UpdateCategory(ctx, db.UpdateCategoryParams{
+		Name:     name,
+		Slug:     slug,
+		ParentID: parentID,
+		ID:       id,
+	})
+	return category, translateError(err)
+}
+
+// Delete deletes a category.
+// It returns sql.ErrNoRows if there is no category with the given ID,
+// and ErrStillReferenced if it has subcategories or articles.
+func (r *categoryRepository) Delete(ctx context.Context, id int64) error {
+	rows, err := r.querier.DeleteCategory(ctx, id)
+	if err != nil {
+		return translateDeleteError(err)
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/para7/nanaket-cms/internal/db"
+)
+
+// CommentRepository defines the interface for comment data access
+type CommentRepository interface {
+	Create(ctx context.Context, articleID int64, userID *int64, authorName *string, content string) (db.Comment, error)
+	ListByArticle(ctx context.Context, articleID int64, limit int32, cursor int64) ([]db.Comment, error)
+}
+
+// commentRepository implements CommentRepository interface
+type commentRepository struct {
+	querier db.Querier
+}
+
+// NewCommentRepository creates a new instance of CommentRepository
+func NewCommentRepository(querier db.Querier) CommentRepository {
+	return &commentRepository{
+		querier: querier,
+	}
+}
+
+// Create creates a new comment on an article.
+// Comments are written either by a registered user (userID) or by a guest (authorName).
+func (r *commentRepository) Create(ctx context.Context, articleID int64, userID *int64, authorName *string, content string) (db.Comment, error) {
+	return r.querier.CreateComment(ctx, db.CreateCommentParams{
+		ArticleID:    articleID,
+		UserID:       userID,
+		TempUserName: authorName,
+		Content:      content,
+	})
+}
+
+// ListByArticle retrieves up to limit comments on an article with an ID lower than cursor, newest first
+func (r *commentRepository) ListByArticle(ctx context.Context, articleID int64, limit int32, cursor int64) ([]db.Comment, error) {
+	return r.querier.ListCommentsByArticle(ctx, db.ListCommentsByArticleParams{
+		ArticleID: articleID,
+		ID:        cursor,
+		Limit:     limit,
+	})
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	// ErrDuplicateKey is returned when a write violates a unique constraint
+	ErrDuplicateKey = errors.New("duplicate key")
+	// ErrMissingReference is returned when a write references a row that does not exist
+	ErrMissingReference = errors.New("missing reference")
+	// ErrStillReferenced is returned when a delete fails because other rows reference the row
+	ErrStillReferenced = errors.New("still referenced")
+)
+
+const (
+	// uniqueViolationCode is the PostgreSQL SQLSTATE for unique_violation
+	uniqueViolationCode = "23505"
+	// foreignKeyViolationCode is the PostgreSQL SQLSTATE for foreign_key_violation
+	foreignKeyViolationCode = "23503"
+)
+
+// translateError converts driver-specific errors into repository errors
+func translateError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case uniqueViolationCode:
+			return ErrDuplicateKey
+		case foreignKeyViolationCode:
+			return ErrMissingReference
+		}
+	}
+	return err
+}
+
+// translateDeleteError converts driver-specific errors of a DELETE into repository errors.
+// A foreign key violation on delete means the row is still referenced.
+func translateDeleteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode {
+		return ErrStillReferenced
+	}
+	return err
+}
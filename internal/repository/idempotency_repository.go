@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/para7/nanaket-cms/internal/db"
+)
+
+// IdempotencyRepository defines the interface for idempotency key data access
+type IdempotencyRepository interface {
+	Claim(ctx context.Context, key string) (bool, error)
+	Get(ctx context.Context, key string) (db.IdempotencyKey, error)
+	Complete(ctx context.Context, key string, articleID int64) error
+	Release(ctx context.Context, key string) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// idempotencyRepository implements IdempotencyRepository interface
+type idempotencyRepository struct {
+	querier db.Querier
+}
+
+// NewIdempotencyRepository creates a new instance of IdempotencyRepository
+func NewIdempotencyRepository(querier db.Querier) IdempotencyRepository {
+	return &idempotencyRepository{
+		querier: querier,
+	}
+}
+
+// Claim records key as in progress and reports whether this caller got it.
+// A key that is unused, expired (24 hours) or abandoned while in progress (1 minute) can be claimed;
+// the single INSERT makes concurrent claims of the same key race-free.
+func (r *idempotencyRepository) Claim(ctx context.Context, key string) (bool, error) {
+	rows, err := r.querier.ClaimIdempotencyKey(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+// Get retrieves an idempotency key
+func (r *idempotencyRepository) Get(ctx context.Context, key string) (db.IdempotencyKey, error) {
+	return r.querier.GetIdempotencyKey(ctx, key)
+}
+
+// Complete records the article created for a claimed key
+func (r *idempotencyRepository) Complete(ctx context.Context, key string, articleID int64) error {
+	return r.querier.CompleteIdempotencyKey(ctx, db.CompleteIdempotencyKeyParams{
+		Key:       key,
+		ArticleID: &articleID,
+	})
+}
+
+// Release gives up a claimed key that has not been completed, so it can be used again
+func (r *idempotencyRepository) Release(ctx context.Context, key string) error {
+	return r.querier.ReleaseIdempotencyKey(ctx, key)
+}
+
+// DeleteExpired removes keys older than 24 hours and returns how many were removed
+func (r *idempotencyRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	return r.querier.DeleteExpiredIdempotencyKeys(ctx)
+}
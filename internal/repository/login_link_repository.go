@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/para7/nanaket-cms/internal/db"
+)
+
+// LoginLinkRepository defines the interface for magic login link data access.
+// Links are identified by the hash of their token; plaintext tokens never reach the database.
+type LoginLinkRepository interface {
+	Create(ctx context.Context, userID int64, tokenHash string, email *string, expiresAt pgtype.Timestamp) error
+	Consume(ctx context.Context, tokenHash string) (int64, error)
+	ConsumeEmailChange(ctx context.Context, tokenHash string) (int64, string, error)
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// loginLinkRepository implements LoginLinkRepository interface
+type loginLinkRepository struct {
+	querier db.Querier
+}
+
+// NewLoginLinkRepository creates a new instance of LoginLinkRepository
+func NewLoginLinkRepository(querier db.Querier) LoginLinkRepository {
+	return &loginLinkRepository{
+		querier: querier,
+	}
+}
+
+// Create stores a new link for a user. With email set, the link confirms a change of
+// the user's email to it instead of logging the user in.
+func (r *loginLinkRepository) Create(ctx context.Context, userID int64, tokenHash string, email *string, expiresAt pgtype.Timestamp) error {
+	err := r.querier.CreateLoginLink(ctx, db.CreateLoginLinkParams{
+		UserID:    userID,
+		Token:     tokenHash,
+		Email:     email,
+		ExpiresAt: expiresAt,
+	})
+	return translateError(err)
+}
+
+// Consume deletes a non-expired login link and returns its user ID.
+// Deleting is atomic, so of concurrent calls with the same token only one succeeds.
+// It returns sql.ErrNoRows if the link is unknown, expired or already used.
+func (r *loginLinkRepository) Consume(ctx context.Context, tokenHash string) (int64, error) {
+	return r.querier.ConsumeLoginLink(ctx, tokenHash)
+}
+
+// ConsumeEmailChange deletes a non-expired email change link and returns its user ID
+// and the new email. Like Consume, of concurrent calls with the same token only one succeeds.
+// It returns sql.ErrNoRows if the link is unknown, expired or already used.
+func (r *loginLinkRepository) ConsumeEmailChange(ctx context.Context, tokenHash string) (int64, string, error) {
+	row, err := r.querier.ConsumeEmailChangeLink(ctx, tokenHash)
+	if err != nil {
+		return 0, "", err
+	}
+	return row.UserID, *row.Email, nil
+}
+
+// DeleteExpired removes expired login links and returns how many were deleted
+func (r *loginLinkRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	return r.querier.DeleteExpiredLoginLinks(ctx)
+}
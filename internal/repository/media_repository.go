@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/para7/nanaket-cms/internal/db"
+)
+
+// MediaRepository defines the interface for media file data access
+type MediaRepository interface {
+	Create(ctx context.Context, userID *int64, filename, objectKey, contentType string, size int64) (db.MediaFile, error)
+	GetByID(ctx context.Context, id int64) (db.MediaFile, error)
+	List(ctx context.Context, limit int32, cursor int64) ([]db.MediaFile, error)
+	ListByIDs(ctx context.Context, ids []int64) ([]db.MediaFile, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// mediaRepository implements MediaRepository interface
+type mediaRepository struct {
+	querier db.Querier
+}
+
+// NewMediaRepository creates a new instance of MediaRepository
+func NewMediaRepository(querier db.Querier) MediaRepository {
+	return &mediaRepository{
+		querier: querier,
+	}
+}
+
+// Create records an uploaded file stored under objectKey
+func (r *mediaRepository) Create(ctx context.Context, userID *int64, filename, objectKey, contentType string, size int64) (db.MediaFile, error) {
+	media, err := r.querier.CreateMediaFile(ctx, db.CreateMediaFileParams{
+		UserID:      userID,
+		Filename:    filename,
+		ObjectKey:   objectKey,
+		ContentType: contentType,
+		Size:        size,
+	})
+	return media, translateError(err)
+}
+
+// GetByID retrieves a media file by ID
+func (r *mediaRepository) GetByID(ctx context.Context, id int64) (db.MediaFile, error) {
+	return r.querier.GetMediaFile(ctx, id)
+}
+
+// List retrieves up to limit media files with an ID lower than cursor, newest first
+func (r *mediaRepository) List(ctx context.Context, limit int32, cursor int64) ([]db.MediaFile, error) {
+	return r.querier.ListMediaFiles(ctx, db.ListMediaFilesParams{
+		ID:    cursor,
+		Limit: limit,
+	})
+}
+
+// ListByIDs retrieves the media files with the given IDs in a single query
+func (r *mediaRepository) ListByIDs(ctx context.Context, ids []int64) ([]db.MediaFile, error) {
+	return r.querier.ListMediaFilesByIDs(ctx, ids)
+}
+
+// Delete deletes a media file record.
+// It returns sql.ErrNoRows if there is no media file with the given ID,
+// and ErrStillReferenced if an article still uses it.
+func (r *mediaRepository) Delete(ctx context.Context, id int64) error {
+	rows, err := r.querier.DeleteMediaFile(ctx, id)
+	if err != nil {
+		return translateDeleteError(err)
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package repository_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// testTx begins a transaction on the database at TEST_DATABASE_URL and rolls it
+// back when the test ends, so tests see the real schema and leave no rows behind.
+// The test is skipped when TEST_DATABASE_URL is not set.
+func testTx(t *testing.T) pgx.Tx {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+	conn, err := pgx.Connect(ctx, url)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		conn.Close(ctx)
+		t.Fatalf("begin: %v", err)
+	}
+	t.Cleanup(func() {
+		tx.Rollback(ctx)
+		conn.Close(ctx)
+	})
+	return tx
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/para7/nanaket-cms/internal/db"
+)
+
+// QuotaRepository defines the interface for per-user daily request counts
+type QuotaRepository interface {
+	Consume(ctx context.Context, userID int64, day time.Time, limit int32) (used int32, ok bool, err error)
+	DeleteBefore(ctx context.Context, day time.Time) (int64, error)
+}
+
+// quotaRepository implements QuotaRepository interface
+type quotaRepository struct {
+	querier db.Querier
+}
+
+// NewQuotaRepository creates a new instance of QuotaRepository
+func NewQuotaRepository(querier db.Querier) QuotaRepository {
+	return &quotaRepository{
+		querier: querier,
+	}
+}
+
+// Consume counts a request of the user on day (a UTC date) unless limit requests were already
+// counted that day. It returns the count afterwards and whether the request was counted.
+// The check and the increment are a single statement, so concurrent requests cannot overshoot the limit.
+func (r *quotaRepository) Consume(ctx context.Context, userID int64, day time.Time, limit int32) (int32, bool, error) {
+	used, err := r.querier.ConsumeUserQuota(ctx, db.ConsumeUserQuotaParams{
+		UserID:   userID,
+		Day:      pgtype.Date{Time: day, Valid: true},
+		MaxCount: limit,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return limit, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return used, true, nil
+}
+
+// DeleteBefore removes the counts of days before day and returns how many were removed
+func (r *quotaRepository) DeleteBefore(ctx context.Context, day time.Time) (int64, error) {
+	return r.querier.DeleteUserQuotaUsageBefore(ctx, pgtype.Date{Time: day, Valid: true})
+}
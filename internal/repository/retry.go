@@ -0,0 +1,305 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/para7/nanaket-cms/internal/db"
+)
+
+// DefaultReadRetries is the default number of times a failed read is retried
+const DefaultReadRetries = 2
+
+// DefaultReadRetryDelay is the default delay before the first retry; it doubles on each retry
+const DefaultReadRetryDelay = 50 * time.Millisecond
+
+// RetryPolicy controls how reads that fail with a transient error are retried
+type RetryPolicy struct {
+	// Retries is the number of retries after the first attempt; 0 disables retrying
+	Retries int
+	// BaseDelay is the wait before the first retry, doubled for each further retry
+	BaseDelay time.Duration
+}
+
+// transientErrorCodes are PostgreSQL SQLSTATEs after which the same read may succeed
+var transientErrorCodes = map[string]bool{
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+	"53300": true, // too_many_connections
+	"57P03": true, // cannot_connect_now
+}
+
+// isTransient reports whether err is worth retrying: a connection failure that happened
+// before the query was sent, a connection exception (SQLSTATE class 08) or one of transientErrorCodes
+func isTransient(err error) bool {
+	if pgconn.SafeToRetry(err) {
+		return true
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return transientErrorCodes[pgErr.Code] || strings.HasPrefix(pgErr.Code, "08")
+	}
+	return false
+}
+
+// retryRead runs read, retrying it with exponential backoff while it fails with a transient error.
+// It gives up early when ctx is done.
+func retryRead[T any](ctx context.Context, policy RetryPolicy, read func() (T, error)) (T, error) {
+	delay := policy.BaseDelay
+	for attempt := 0; ; attempt++ {
+		v, err := read()
+		if err == nil || attempt >= policy.Retries || !isTransient(err) {
+			return v, err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return v, err
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+// retryQuerier wraps a db.Querier and retries reads (Get, List, Count, Search and existence
+// checks) that fail with a transient error. Writes go straight to the wrapped Querier:
+// a failed write may already have been applied, so retrying it is not safe in general.
+type retryQuerier struct {
+	db.Querier
+	policy RetryPolicy
+}
+
+// NewRetryQuerier wraps querier so that its reads are retried according to policy.
+// Do not wrap a transaction's querier: after an error the transaction is aborted and retries cannot succeed.
+func NewRetryQuerier(querier db.Querier, policy RetryPolicy) db.Querier {
+	if policy.Retries <= 0 {
+		return querier
+	}
+	return &retryQuerier{
+		Querier: querier,
+		policy:  policy,
+	}
+}
+
+func (q *retryQuerier) ArticleSlugExists(ctx context.Context, slug *string) (bool, error) {
+	return retryRead(ctx, q.policy, func() (bool, error) { return q.Querier.ArticleSlugExists(ctx, slug) })
+}
+
+func (q *retryQuerier) CountArticles(ctx context.Context, arg db.CountArticlesParams) (int64, error) {
+	return retryRead(ctx, q.policy, func() (int64, error) { return q.Querier.CountArticles(ctx, arg) })
+}
+
+func (q *retryQuerier) CountArticlesByAuthor(ctx context.Context, arg db.CountArticlesByAuthorParams) ([]db.CountArticlesByAuthorRow, error) {
+	return retryRead(ctx, q.policy, func() ([]db.CountArticlesByAuthorRow, error) { return q.Querier.CountArticlesByAuthor(ctx, arg) })
+}
+
+func (q *retryQuerier) CountPinnedArticles(ctx context.Context) (int64, error) {
+	return retryRead(ctx, q.policy, func() (int64, error) { return q.Querier.CountPinnedArticles(ctx) })
+}
+
+func (q *retryQuerier) CountSearchUsers(ctx context.Context, pattern string) (int64, error) {
+	return retryRead(ctx, q.policy, func() (int64, error) { return q.Querier.CountSearchUsers(ctx, pattern) })
+}
+
+func (q *retryQuerier) CountUsers(ctx context.Context) (int64, error) {
+	return retryRead(ctx, q.policy, func() (int64, error) { return q.Querier.CountUsers(ctx) })
+}
+
+func (q *retryQuerier) GetAccessToken(ctx context.Context, token string) (db.AccessToken, error) {
+	return retryRead(ctx, q.policy, func() (db.AccessToken, error) { return q.Querier.GetAccessToken(ctx, token) })
+}
+
+func (q *retryQuerier) GetArticle(ctx context.Context, id int64) (db.Article, error) {
+	return retryRead(ctx, q.policy, func() (db.Article, error) { return q.Querier.GetArticle(ctx, id) })
+}
+
+func (q *retryQuerier) GetArticleByPublicID(ctx context.Context, publicID *string) (db.Article, error) {
+	return retryRead(ctx, q.policy, func() (db.Article, error) { return q.Querier.GetArticleByPublicID(ctx, publicID) })
+}
+
+func (q *retryQuerier) GetArticleBySlug(ctx context.Context, slug *string) (db.Article, error) {
+	return retryRead(ctx, q.policy, func() (db.Article, error) { return q.Querier.GetArticleBySlug(ctx, slug) })
+}
+
+func (q *retryQuerier) GetArticleRevision(ctx context.Context, arg db.GetArticleRevisionParams) (db.ArticleRevision, error) {
+	return retryRead(ctx, q.policy, func() (db.ArticleRevision, error) { return q.Querier.GetArticleRevision(ctx, arg) })
+}
+
+func (q *retryQuerier) GetCategory(ctx context.Context, id int64) (db.Category, error) {
+	return retryRead(ctx, q.policy, func() (db.Category, error) { return q.Querier.GetCategory(ctx, id) })
+}
+
+func (q *retryQuerier) GetIdempotencyKey(ctx context.Context, key string) (db.IdempotencyKey, error) {
+	return retryRead(ctx, q.policy, func() (db.IdempotencyKey, error) { return q.Querier.GetIdempotencyKey(ctx, key) })
+}
+
+func (q *retryQuerier) GetMediaFile(ctx context.Context, id int64) (db.MediaFile, error) {
+	return retryRead(ctx, q.policy, func() (db.MediaFile, error) { return q.Querier.GetMediaFile(ctx, id) })
+}
+
+func (q *retryQuerier) GetSetting(ctx context.Context, key string) (string, error) {
+	return retryRead(ctx, q.policy, func() (string, error) { return q.Querier.GetSetting(ctx, key) })
+}
+
+func (q *retryQuerier) GetUser(ctx context.Context, id int64) (db.User, error) {
+	return retryRead(ctx, q.policy, func() (db.User, error) { return q.Querier.GetUser(ctx, id) })
+}
+
+func (q *retryQuerier) GetUserByEmail(ctx context.Context, email string) (db.User, error) {
+	return retryRead(ctx, q.policy, func() (db.User, error) { return q.Querier.GetUserByEmail(ctx, email) })
+}
+
+func (q *retryQuerier) GetUserByToken(ctx context.Context, token string) (db.User, error) {
+	return retryRead(ctx, q.policy, func() (db.User, error) { return q.Querier.GetUserByToken(ctx, token) })
+}
+
+func (q *retryQuerier) GetWebhook(ctx context.Context, id int64) (db.Webhook, error) {
+	return retryRead(ctx, q.policy, func() (db.Webhook, error) { return q.Querier.GetWebhook(ctx, id) })
+}
+
+func (q *retryQuerier) ListAccessTokensByUser(ctx context.Context, userID int64) ([]db.AccessToken, error) {
+	return retryRead(ctx, q.policy, func() ([]db.AccessToken, error) { return q.Querier.ListAccessTokensByUser(ctx, userID) })
+}
+
+func (q *retryQuerier) ListAllArticles(ctx context.Context) ([]db.Article, error) {
+	return retryRead(ctx, q.policy, func() ([]db.Article, error) { return q.Querier.ListAllArticles(ctx) })
+}
+
+func (q *retryQuerier) ListAllUsers(ctx context.Context) ([]db.User, error) {
+	return retryRead(ctx, q.policy, func() ([]db.User, error) { return q.Querier.ListAllUsers(ctx) })
+}
+
+func (q *retryQuerier) ListArchiveMonths(ctx context.Context) ([]db.ListArchiveMonthsRow, error) {
+	return retryRead(ctx, q.policy, func() ([]db.ListArchiveMonthsRow, error) { return q.Querier.ListArchiveMonths(ctx) })
+}
+
+func (q *retryQuerier) ListArticleRevisions(ctx context.Context, articleID int64) ([]db.ArticleRevision, error) {
+	return retryRead(ctx, q.policy, func() ([]db.ArticleRevision, error) { return q.Querier.ListArticleRevisions(ctx, articleID) })
+}
+
+func (q *retryQuerier) ListArticleTranslations(ctx context.Context, arg db.ListArticleTranslationsParams) ([]db.ArticleTranslation, error) {
+	return retryRead(ctx, q.policy, func() ([]db.ArticleTranslation, error) { return q.Querier.ListArticleTranslations(ctx, arg) })
+}
+
+func (q *retryQuerier) ListArticles(ctx context.Context) ([]db.Article, error) {
+	return retryRead(ctx, q.policy, func() ([]db.Article, error) { return q.Querier.ListArticles(ctx) })
+}
+
+func (q *retryQuerier) ListArticlesByCreatedAt(ctx context.Context, arg db.ListArticlesByCreatedAtParams) ([]db.Article, error) {
+	return retryRead(ctx, q.policy, func() ([]db.Article, error) { return q.Querier.ListArticlesByCreatedAt(ctx, arg) })
+}
+
+func (q *retryQuerier) ListArticlesByCreatedAtDesc(ctx context.Context, arg db.ListArticlesByCreatedAtDescParams) ([]db.Article, error) {
+	return retryRead(ctx, q.policy, func() ([]db.Article, error) { return q.Querier.ListArticlesByCreatedAtDesc(ctx, arg) })
+}
+
+func (q *retryQuerier) ListArticlesByID(ctx context.Context, arg db.ListArticlesByIDParams) ([]db.Article, error) {
+	return retryRead(ctx, q.policy, func() ([]db.Article, error) { return q.Querier.ListArticlesByID(ctx, arg) })
+}
+
+func (q *retryQuerier) ListArticlesByPublishedAt(ctx context.Context, arg db.ListArticlesByPublishedAtParams) ([]db.Article, error) {
+	return retryRead(ctx, q.policy, func() ([]db.Article, error) { return q.Querier.ListArticlesByPublishedAt(ctx, arg) })
+}
+
+func (q *retryQuerier) ListArticlesByPublishedAtDesc(ctx context.Context, arg db.ListArticlesByPublishedAtDescParams) ([]db.Article, error) {
+	return retryRead(ctx, q.policy, func() ([]db.Article, error) { return q.Querier.ListArticlesByPublishedAtDesc(ctx, arg) })
+}
+
+func (q *retryQuerier) ListArticlesByTitle(ctx context.Context, arg db.ListArticlesByTitleParams) ([]db.Article, error) {
+	return retryRead(ctx, q.policy, func() ([]db.Article, error) { return q.Querier.ListArticlesByTitle(ctx, arg) })
+}
+
+func (q *retryQuerier) ListArticlesByUser(ctx context.Context, userID int64) ([]db.Article, error) {
+	return retryRead(ctx, q.policy, func() ([]db.Article, error) { return q.Querier.ListArticlesByUser(ctx, userID) })
+}
+
+func (q *retryQuerier) ListArticlesForExport(ctx context.Context, arg db.ListArticlesForExportParams) ([]db.Article, error) {
+	return retryRead(ctx, q.policy, func() ([]db.Article, error) { return q.Querier.ListArticlesForExport(ctx, arg) })
+}
+
+func (q *retryQuerier) ListArticlesWithoutPublicID(ctx context.Context, limit int32) ([]int64, error) {
+	return retryRead(ctx, q.policy, func() ([]int64, error) { return q.Querier.ListArticlesWithoutPublicID(ctx, limit) })
+}
+
+func (q *retryQuerier) ListCategories(ctx context.Context) ([]db.Category, error) {
+	return retryRead(ctx, q.policy, func() ([]db.Category, error) { return q.Querier.ListCategories(ctx) })
+}
+
+func (q *retryQuerier) ListCommentsByArticle(ctx context.Context, arg db.ListCommentsByArticleParams) ([]db.Comment, error) {
+	return retryRead(ctx, q.policy, func() ([]db.Comment, error) { return q.Querier.ListCommentsByArticle(ctx, arg) })
+}
+
+func (q *retryQuerier) ListExistingArticleIDs(ctx context.Context, ids []int64) ([]int64, error) {
+	return retryRead(ctx, q.policy, func() ([]int64, error) { return q.Querier.ListExistingArticleIDs(ctx, ids) })
+}
+
+func (q *retryQuerier) ListExistingArticleSlugs(ctx context.Context, slugs []string) ([]*string, error) {
+	return retryRead(ctx, q.policy, func() ([]*string, error) { return q.Querier.ListExistingArticleSlugs(ctx, slugs) })
+}
+
+func (q *retryQuerier) ListExistingUserEmails(ctx context.Context, emails []string) ([]string, error) {
+	return retryRead(ctx, q.policy, func() ([]string, error) { return q.Querier.ListExistingUserEmails(ctx, emails) })
+}
+
+func (q *retryQuerier) ListMediaFiles(ctx context.Context, arg db.ListMediaFilesParams) ([]db.MediaFile, error) {
+	return retryRead(ctx, q.policy, func() ([]db.MediaFile, error) { return q.Querier.ListMediaFiles(ctx, arg) })
+}
+
+func (q *retryQuerier) ListMediaFilesByIDs(ctx context.Context, ids []int64) ([]db.MediaFile, error) {
+	return retryRead(ctx, q.policy, func() ([]db.MediaFile, error) { return q.Querier.ListMediaFilesByIDs(ctx, ids) })
+}
+
+func (q *retryQuerier) ListRecentArticlesByAuthor(ctx context.Context, arg db.ListRecentArticlesByAuthorParams) ([]db.Article, error) {
+	return retryRead(ctx, q.policy, func() ([]db.Article, error) { return q.Querier.ListRecentArticlesByAuthor(ctx, arg) })
+}
+
+func (q *retryQuerier) ListRelatedArticles(ctx context.Context, arg db.ListRelatedArticlesParams) ([]db.Article, error) {
+	return retryRead(ctx, q.policy, func() ([]db.Article, error) { return q.Querier.ListRelatedArticles(ctx, arg) })
+}
+
+func (q *retryQuerier) ListScheduledArticles(ctx context.Context) ([]db.Article, error) {
+	return retryRead(ctx, q.policy, func() ([]db.Article, error) { return q.Querier.ListScheduledArticles(ctx) })
+}
+
+func (q *retryQuerier) ListTagNamesByArticles(ctx context.Context, articleIds []int64) ([]db.ListTagNamesByArticlesRow, error) {
+	return retryRead(ctx, q.policy, func() ([]db.ListTagNamesByArticlesRow, error) {
+		return q.Querier.ListTagNamesByArticles(ctx, articleIds)
+	})
+}
+
+func (q *retryQuerier) ListTagsByArticle(ctx context.Context, articleID int64) ([]db.Tag, error) {
+	return retryRead(ctx, q.policy, func() ([]db.Tag, error) { return q.Querier.ListTagsByArticle(ctx, articleID) })
+}
+
+func (q *retryQuerier) ListUsers(ctx context.Context) ([]db.User, error) {
+	return retryRead(ctx, q.policy, func() ([]db.User, error) { return q.Querier.ListUsers(ctx) })
+}
+
+func (q *retryQuerier) ListUsersByIDs(ctx context.Context, ids []int64) ([]db.User, error) {
+	return retryRead(ctx, q.policy, func() ([]db.User, error) { return q.Querier.ListUsersByIDs(ctx, ids) })
+}
+
+func (q *retryQuerier) ListUsersPaginated(ctx context.Context, arg db.ListUsersPaginatedParams) ([]db.User, error) {
+	return retryRead(ctx, q.policy, func() ([]db.User, error) { return q.Querier.ListUsersPaginated(ctx, arg) })
+}
+
+func (q *retryQuerier) ListWebhooks(ctx context.Context) ([]db.Webhook, error) {
+	return retryRead(ctx, q.policy, func() ([]db.Webhook, error) { return q.Querier.ListWebhooks(ctx) })
+}
+
+func (q *retryQuerier) ListWebhooksByEvent(ctx context.Context, event string) ([]db.Webhook, error) {
+	return retryRead(ctx, q.policy, func() ([]db.Webhook, error) { return q.Querier.ListWebhooksByEvent(ctx, event) })
+}
+
+func (q *retryQuerier) SearchArticles(ctx context.Context, arg db.SearchArticlesParams) ([]db.Article, error) {
+	return retryRead(ctx, q.policy, func() ([]db.Article, error) { return q.Querier.SearchArticles(ctx, arg) })
+}
+
+func (q *retryQuerier) SearchUsers(ctx context.Context, arg db.SearchUsersParams) ([]db.User, error) {
+	return retryRead(ctx, q.policy, func() ([]db.User, error) { return q.Querier.SearchUsers(ctx, arg) })
+}
@@ -0,0 +1,81 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/para7/nanaket-cms/internal/db"
+	"github.com/para7/nanaket-cms/internal/db/mock"
+	"github.com/para7/nanaket-cms/internal/repository"
+)
+
+// failingGetArticle returns a GetArticle that fails with err on the first failures calls
+// and succeeds afterwards, counting the calls in calls
+func failingGetArticle(failures int, err error, calls *int) func(ctx context.Context, id int64) (db.Article, error) {
+	return func(ctx context.Context, id int64) (db.Article, error) {
+		*calls++
+		if *calls <= failures {
+			return db.Article{}, err
+		}
+		return db.Article{ID: id}, nil
+	}
+}
+
+func TestRetryQuerierRead(t *testing.T) {
+	serialization := &pgconn.PgError{Code: "40001"}
+	policy := repository.RetryPolicy{Retries: 2, BaseDelay: time.Millisecond}
+
+	tests := []struct {
+		name      string
+		failures  int
+		err       error
+		wantCalls int
+		wantErr   bool
+	}{
+		{"fails twice then succeeds", 2, serialization, 3, false},
+		{"fails more often than retried", 3, serialization, 3, true},
+		{"not transient", 2, errors.New("syntax error"), 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			q := repository.NewRetryQuerier(&mock.Querier{
+				GetArticleFunc: failingGetArticle(tt.failures, tt.err, &calls),
+			}, policy)
+
+			article, err := q.GetArticle(context.Background(), 1)
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("got %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil || article.ID != 1 {
+				t.Errorf("got %+v, %v; want article 1", article, err)
+			}
+		})
+	}
+}
+
+func TestRetryQuerierLeavesWritesAlone(t *testing.T) {
+	var calls int
+	q := repository.NewRetryQuerier(&mock.Querier{
+		SoftDeleteArticleFunc: func(ctx context.Context, id int64) (int64, error) {
+			calls++
+			return 0, &pgconn.PgError{Code: "40001"}
+		},
+	}, repository.RetryPolicy{Retries: 2, BaseDelay: time.Millisecond})
+
+	if _, err := q.SoftDeleteArticle(context.Background(), 1); err == nil {
+		t.Fatal("expected the write to fail")
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/para7/nanaket-cms/internal/db"
+)
+
+// SettingRepository defines the interface for runtime setting data access
+type SettingRepository interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key, value string) error
+}
+
+// settingRepository implements SettingRepository interface
+type settingRepository struct {
+	querier db.Querier
+}
+
+// NewSettingRepository creates a new instance of SettingRepository
+func NewSettingRepository(querier db.Querier) SettingRepository {
+	return &settingRepository{
+		querier: querier,
+	}
+}
+
+// Get retrieves the value of a setting; it returns sql.ErrNoRows when the setting was never set
+func (r *settingRepository) Get(ctx context.Context, key string) (string, error) {
+	return r.querier.GetSetting(ctx, key)
+}
+
+// Set stores the value of a setting, creating it if needed
+func (r *settingRepository) Set(ctx context.Context, key, value string) error {
+	return r.querier.SetSetting(ctx, db.SetSettingParams{
+		Key:   key,
+		Value: value,
+	})
+}
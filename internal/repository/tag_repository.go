@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/para7/nanaket-cms/internal/db"
+)
+
+// TagRepository defines the interface for tag data access
+type TagRepository interface {
+	Upsert(ctx context.Context, name string) (db.Tag, error)
+	Attach(ctx context.Context, articleID, tagID int64) error
+	DetachExcept(ctx context.Context, articleID int64, keepTagIDs []int64) error
+	ListByArticle(ctx context.Context, articleID int64) ([]db.Tag, error)
+	ListNamesByArticles(ctx context.Context, articleIDs []int64) (map[int64][]string, error)
+}
+
+// tagRepository implements TagRepository interface
+type tagRepository struct {
+	querier db.Querier
+}
+
+// NewTagRepository creates a new instance of TagRepository
+func NewTagRepository(querier db.Querier) TagRepository {
+	return &tagRepository{
+		querier: querier,
+	}
+}
+
+// Upsert returns the tag with the given name, creating it if needed
+func (r *tagRepository) Upsert(ctx context.Context, name string) (db.Tag, error) {
+	return r.querier.UpsertTag(ctx, name)
+}
+
+// Attach associates a tag with an article (no-op if already attached)
+func (r *tagRepository) Attach(ctx context.Context, articleID, tagID int64) error {
+	return r.querier.AttachTag(ctx, db.AttachTagParams{
+		ArticleID: articleID,
+		TagID:     tagID,
+	})
+}
+
+// DetachExcept removes every tag from an article except the given ones
+func (r *tagRepository) DetachExcept(ctx context.Context, articleID int64, keepTagIDs []int64) error {
+	return r.querier.DetachTagsExcept(ctx, db.DetachTagsExceptParams{
+		ArticleID: articleID,
+		TagIds:    keepTagIDs,
+	})
+}
+
+// ListByArticle retrieves the tags of an article ordered by name
+func (r *tagRepository) ListByArticle(ctx context.Context, articleID int64) ([]db.Tag, error) {
+	return r.querier.ListTagsByArticle(ctx, articleID)
+}
+
+// ListNamesByArticles retrieves tag names for several articles in one query, keyed by article ID
+func (r *tagRepository) ListNamesByArticles(ctx context.Context, articleIDs []int64) (map[int64][]string, error) {
+	rows, err := r.querier.ListTagNamesByArticles(ctx, articleIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[int64][]string, len(articleIDs))
+	for _, row := range rows {
+		names[row.ArticleID] = append(names[row.ArticleID], row.Name)
+	}
+	return names, nil
+}
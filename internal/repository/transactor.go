@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/para7/nanaket-cms/internal/db"
+)
+
+// Transactor runs multi-step operations atomically
+type Transactor interface {
+	// WithTx runs fn inside a database transaction, committing if it returns nil
+	// and rolling back otherwise. Repositories used inside fn must come from tx.
+	WithTx(ctx context.Context, fn func(tx Tx) error) error
+}
+
+// Tx hands out repositories bound to a single transaction
+type Tx struct {
+	querier db.Querier
+}
+
+// NewTx creates a Tx whose repositories use querier
+func NewTx(querier db.Querier) Tx {
+	return Tx{querier: querier}
+}
+
+// Articles returns an ArticleRepository bound to the transaction
+func (t Tx) Articles() ArticleRepository {
+	return NewArticleRepository(t.querier)
+}
+
+// Revisions returns an ArticleRevisionRepository bound to the transaction
+func (t Tx) Revisions() ArticleRevisionRepository {
+	return NewArticleRevisionRepository(t.querier)
+}
+
+// Users returns a UserRepository bound to the transaction
+func (t Tx) Users() UserRepository {
+	return NewUserRepository(t.querier)
+}
+
+// Tags returns a TagRepository bound to the transaction
+func (t Tx) Tags() TagRepository {
+	return NewTagRepository(t.querier)
+}
+
+// transactor implements Transactor interface
+type transactor struct {
+	conn db.TxBeginner
+}
+
+// NewTransactor creates a new instance of Transactor that starts transactions on conn
+func NewTransactor(conn db.TxBeginner) Transactor {
+	return &transactor{
+		conn: conn,
+	}
+}
+
+// WithTx runs fn inside a transaction on the underlying connection
+func (t *transactor) WithTx(ctx context.Context, fn func(tx Tx) error) error {
+	return db.WithTx(ctx, t.conn, func(q *db.Queries) error {
+		return fn(NewTx(q))
+	})
+}
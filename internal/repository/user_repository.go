@@ -2,17 +2,31 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"strings"
 
 	"github.com/para7/nanaket-cms/internal/db"
 )
 
 // UserRepository defines the interface for user data access
 type UserRepository interface {
-	Create(ctx context.Context, email, name string) (db.User, error)
+	Create(ctx context.Context, email, name, role string) (db.User, error)
 	GetByID(ctx context.Context, id int64) (db.User, error)
+	GetByEmail(ctx context.Context, email string) (db.User, error)
 	List(ctx context.Context) ([]db.User, error)
+	ListByIDs(ctx context.Context, ids []int64) ([]db.User, error)
+	ListPaginated(ctx context.Context, limit, offset int32) ([]db.User, error)
+	Count(ctx context.Context) (int64, error)
+	Search(ctx context.Context, pattern string, limit, offset int32) ([]db.User, error)
+	CountSearch(ctx context.Context, pattern string) (int64, error)
 	Update(ctx context.Context, id int64, email, name string) (db.User, error)
+	UpsertByEmail(ctx context.Context, email, name, role string) (db.User, bool, error)
 	Delete(ctx context.Context, id int64) error
+	Restore(ctx context.Context, id int64) (db.User, error)
+	ListAll(ctx context.Context) ([]db.User, error)
+	ExistingEmails(ctx context.Context, emails []string) ([]string, error)
+	Import(ctx context.Context, user db.User, preserveID bool) (db.User, error)
+	SyncIDSequence(ctx context.Context) error
 }
 
 // userRepository implements UserRepository interface
@@ -28,11 +42,13 @@ func NewUserRepository(querier db.Querier) UserRepository {
 }
 
 // Create creates a new user
-func (r *userRepository) Create(ctx context.Context, email, name string) (db.User, error) {
-	return r.querier.CreateUser(ctx, db.CreateUserParams{
+func (r *userRepository) Create(ctx context.Context, email, name, role string) (db.User, error) {
+	user, err := r.querier.CreateUser(ctx, db.CreateUserParams{
 		Email: email,
 		Name:  name,
+		Role:  role,
 	})
+	return user, translateError(err)
 }
 
 // GetByID retrieves a user by ID
@@ -40,21 +56,152 @@ func (r *userRepository) GetByID(ctx context.Context, id int64) (db.User, error)
 	return r.querier.GetUser(ctx, id)
 }
 
+// GetByEmail retrieves a user by email, ignoring case
+func (r *userRepository) GetByEmail(ctx context.Context, email string) (db.User, error) {
+	return r.querier.GetUserByEmail(ctx, email)
+}
+
 // List retrieves all users
 func (r *userRepository) List(ctx context.Context) ([]db.User, error) {
 	return r.querier.ListUsers(ctx)
 }
 
+// ListByIDs retrieves the users with the given IDs in a single query, ordered by ID.
+// Unlike the other lookups it includes soft-deleted users, so articles can still name their author.
+func (r *userRepository) ListByIDs(ctx context.Context, ids []int64) ([]db.User, error) {
+	return r.querier.ListUsersByIDs(ctx, ids)
+}
+
+// ListPaginated retrieves a page of users ordered by ID
+func (r *userRepository) ListPaginated(ctx context.Context, limit, offset int32) ([]db.User, error) {
+	return r.querier.ListUsersPaginated(ctx, db.ListUsersPaginatedParams{
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+// Count returns the total number of users
+func (r *userRepository) Count(ctx context.Context) (int64, error) {
+	return r.querier.CountUsers(ctx)
+}
+
+// Search retrieves a page of users whose name or email matches the ILIKE pattern, ordered by ID
+func (r *userRepository) Search(ctx context.Context, pattern string, limit, offset int32) ([]db.User, error) {
+	return r.querier.SearchUsers(ctx, db.SearchUsersParams{
+		Pattern:    pattern,
+		MaxResults: limit,
+		RowOffset:  offset,
+	})
+}
+
+// CountSearch returns the number of users whose name or email matches the ILIKE pattern
+func (r *userRepository) CountSearch(ctx context.Context, pattern string) (int64, error) {
+	return r.querier.CountSearchUsers(ctx, pattern)
+}
+
 // Update updates a user
 func (r *userRepository) Update(ctx context.Context, id int64, email, name string) (db.User, error) {
-	return r.querier.UpdateUser(ctx, db.UpdateUserParams{
+	user, err := r.querier.UpdateUser(ctx, db.UpdateUserParams{
 		ID:    id,
 		Email: email,
 		Name:  name,
 	})
+	return user, translateError(err)
 }
 
-// Delete deletes a user
+// UpsertByEmail creates a user with role, or renames the existing user with the same email,
+// in a single statement. It reports whether the user was created.
+// It returns sql.ErrNoRows if the email belongs to a soft-deleted user.
+func (r *userRepository) UpsertByEmail(ctx context.Context, email, name, role string) (db.User, bool, error) {
+	row, err := r.querier.UpsertUserByEmail(ctx, db.UpsertUserByEmailParams{
+		Email: email,
+		Name:  name,
+		Role:  role,
+	})
+	if err != nil {
+		return db.User{}, false, err
+	}
+	user := db.User{
+		ID:           row.ID,
+		Name:         row.Name,
+		Email:        row.Email,
+		CreatedAt:    row.CreatedAt,
+		UpdatedAt:    row.UpdatedAt,
+		Role:         row.Role,
+		DeletedAt:    row.DeletedAt,
+		PasswordHash: row.PasswordHash,
+	}
+	return user, row.Inserted, nil
+}
+
+// Delete soft-deletes a user and revokes all of their access tokens.
+// It returns sql.ErrNoRows if there is no live user with the given ID.
+//
+// Nothing else cascades: the user's articles stay as they are (responses mark the
+// author inactive), and their comments and uploaded media keep referring to them.
+// Even without the token revocation, GetUserByToken ignores soft-deleted users.
 func (r *userRepository) Delete(ctx context.Context, id int64) error {
-	return r.querier.DeleteUser(ctx, id)
+	rows, err := r.querier.SoftDeleteUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return r.querier.DeleteAccessTokensByUser(ctx, id)
+}
+
+// Restore undoes a soft delete.
+// It returns sql.ErrNoRows if there is no soft-deleted user with the given ID.
+// Tokens revoked by Delete stay revoked; the user has to log in again.
+func (r *userRepository) Restore(ctx context.Context, id int64) (db.User, error) {
+	return r.querier.RestoreUser(ctx, id)
+}
+
+// ListAll retrieves every user, including soft-deleted ones, in ID order
+func (r *userRepository) ListAll(ctx context.Context) ([]db.User, error) {
+	return r.querier.ListAllUsers(ctx)
+}
+
+// ExistingEmails returns those of emails already used by a user, including soft-deleted ones.
+// Emails are compared ignoring case and returned lowercased.
+func (r *userRepository) ExistingEmails(ctx context.Context, emails []string) ([]string, error) {
+	lowered := make([]string, len(emails))
+	for i, email := range emails {
+		lowered[i] = strings.ToLower(email)
+	}
+	existing, err := r.querier.ListExistingUserEmails(ctx, lowered)
+	if err != nil {
+		return nil, err
+	}
+	for i, email := range existing {
+		existing[i] = strings.ToLower(email)
+	}
+	return existing, nil
+}
+
+// Import inserts a user from a backup with its timestamps and deletion state.
+// With preserveID the user keeps its ID; call SyncIDSequence afterwards.
+// Otherwise a new ID is assigned.
+func (r *userRepository) Import(ctx context.Context, user db.User, preserveID bool) (db.User, error) {
+	var id *int64
+	if preserveID {
+		id = &user.ID
+	}
+
+	imported, err := r.querier.ImportUser(ctx, db.ImportUserParams{
+		ID:        id,
+		Email:     user.Email,
+		Name:      user.Name,
+		Role:      user.Role,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+		DeletedAt: user.DeletedAt,
+	})
+	return imported, translateError(err)
+}
+
+// SyncIDSequence advances the user ID sequence past the largest ID in use
+func (r *userRepository) SyncIDSequence(ctx context.Context) error {
+	return r.querier.SyncUserIDSequence(ctx)
 }
@@ -0,0 +1,64 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/para7/nanaket-cms/internal/db"
+	"github.com/para7/nanaket-cms/internal/repository"
+)
+
+func TestUserEmailIgnoresCase(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewUserRepository(db.New(testTx(t)))
+
+	created, err := repo.Create(ctx, "A@x.com", "A", "viewer")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	got, err := repo.GetByEmail(ctx, "a@x.com")
+	if err != nil {
+		t.Fatalf("GetByEmail: %v", err)
+	}
+	if got.ID != created.ID {
+		t.Errorf("found user %d, want %d", got.ID, created.ID)
+	}
+
+	// The failed insert aborts the transaction, so this comes last
+	if _, err := repo.Create(ctx, "a@x.com", "B", "viewer"); !errors.Is(err, repository.ErrDuplicateKey) {
+		t.Errorf("Create with the email in another case: got %v, want ErrDuplicateKey", err)
+	}
+}
+
+func TestUserUpdateTimestamps(t *testing.T) {
+	ctx := context.Background()
+	tx := testTx(t)
+	repo := repository.NewUserRepository(db.New(tx))
+
+	// CURRENT_TIMESTAMP is fixed for the transaction, so the row is inserted with
+	// earlier timestamps for the update to move updated_at forward
+	before := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var id int64
+	err := tx.QueryRow(ctx,
+		"INSERT INTO users (email, name, created_at, updated_at) VALUES ($1, $2, $3, $3) RETURNING id",
+		"user@example.com", "Before", before).Scan(&id)
+	if err != nil {
+		t.Fatalf("insert user: %v", err)
+	}
+
+	updated, err := repo.Update(ctx, id, "user@example.com", "After")
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if updated.Name != "After" {
+		t.Errorf("name = %q, want After", updated.Name)
+	}
+	if !updated.CreatedAt.Time.Equal(before) {
+		t.Errorf("created_at = %v, want %v", updated.CreatedAt.Time, before)
+	}
+	if !updated.UpdatedAt.Time.After(before) {
+		t.Errorf("updated_at = %v, want after %v", updated.UpdatedAt.Time, before)
+	}
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/para7/nanaket-cms/internal/db"
+)
+
+// WebhookRepository defines the interface for webhook data access
+type WebhookRepository interface {
+	Create(ctx context.Context, url, secret string, events []string) (db.Webhook, error)
+	GetByID(ctx context.Context, id int64) (db.Webhook, error)
+	List(ctx context.Context) ([]db.Webhook, error)
+	ListByEvent(ctx context.Context, event string) ([]db.Webhook, error)
+	Update(ctx context.Context, id int64, url string, secret *string, events []string) (db.Webhook, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// webhookRepository implements WebhookRepository interface
+type webhookRepository struct {
+	querier db.Querier
+}
+
+// NewWebhookRepository creates a new instance of WebhookRepository
+func NewWebhookRepository(querier db.Querier) WebhookRepository {
+	return &webhookRepository{
+		querier: querier,
+	}
+}
+
+// Create registers a new webhook
+func (r *webhookRepository) Create(ctx context.Context, url, secret string, events []string) (db.Webhook, error) {
+	return r.querier.CreateWebhook(ctx, db.CreateWebhookParams{
+		Url:    url,
+		Secret: secret,
+		Events: events,
+	})
+}
+
+// GetByID retrieves a webhook by ID
+func (r *webhookRepository) GetByID(ctx context.Context, id int64) (db.Webhook, error) {
+	return r.querier.GetWebhook(ctx, id)
+}
+
+// List retrieves all webhooks ordered by ID
+func (r *webhookRepository) List(ctx context.Context) ([]db.Webhook, error) {
+	return r.querier.ListWebhooks(ctx)
+}
+
+// ListByEvent retrieves the webhooks subscribed to event
+func (r *webhookRepository) ListByEvent(ctx context.Context, event string) ([]db.Webhook, error) {
+	return r.querier.ListWebhooksByEvent(ctx, event)
+}
+
+// Update updates a webhook. A nil secret keeps the current one.
+func (r *webhookRepository) Update(ctx context.Context, id int64, url string, secret *string, events []string) (db.Webhook, error) {
+	return r.querier.UpdateWebhook(ctx, db.UpdateWebhookParams{
+		Url:    url,
+		Secret: secret,
+		Events: events,
+		ID:     id,
+	})
+}
+
+// Delete deletes a webhook.
+// It returns sql.ErrNoRows if there is no webhook with the given ID.
+func (r *webhookRepository) Delete(ctx context.Context, id int64) error {
+	rows, err := r.querier.DeleteWebhook(ctx, id)
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+// Package storage stores uploaded files as objects addressed by key.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrInvalidKey is returned for keys that are empty or contain path separators
+var ErrInvalidKey = errors.New("invalid object key")
+
+// ObjectStore stores objects and resolves their public URLs.
+// Implementations must be safe for concurrent use.
+type ObjectStore interface {
+	// Put stores the content read from r under key, replacing any existing object
+	Put(ctx context.Context, key, contentType string, r io.Reader) error
+	// Delete removes the object stored under key; deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+	// URL returns the public URL of the object stored under key
+	URL(key string) string
+}
+
+// LocalStore is an ObjectStore keeping objects as files in a single directory.
+// The files are expected to be served at baseURL, e.g. with http.FileServer.
+type LocalStore struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalStore creates the directory if needed and returns a LocalStore serving objects from baseURL
+func NewLocalStore(dir, baseURL string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &LocalStore{dir: dir, baseURL: strings.TrimRight(baseURL, "/")}, nil
+}
+
+// Put writes the object to a temporary file and renames it into place,
+// so readers never see a partially written object
+func (s *LocalStore) Put(_ context.Context, key, _ string, r io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Delete removes the object's file
+func (s *LocalStore) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Check verifies that the directory is writable
+func (s *LocalStore) Check(_ context.Context) error {
+	f, err := os.CreateTemp(s.dir, ".check-*")
+	if err != nil {
+		return err
+	}
+	name := f.Name()
+	_ = f.Close()
+	return os.Remove(name)
+}
+
+// URL returns baseURL/key
+func (s *LocalStore) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+// path maps key to a file inside dir, rejecting keys that could escape it
+func (s *LocalStore) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, ".") || strings.ContainsAny(key, `/\`) {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(s.dir, key), nil
+}
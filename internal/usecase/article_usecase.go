@@ -2,41 +2,873 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/para7/nanaket-cms/internal/db"
+	"github.com/para7/nanaket-cms/internal/markdown"
 	"github.com/para7/nanaket-cms/internal/repository"
+	"github.com/para7/nanaket-cms/internal/storage"
 )
 
+// Article statuses
+const (
+	ArticleStatusDraft     = "draft"
+	ArticleStatusPublished = "published"
+	ArticleStatusArchived  = "archived"
+)
+
+var (
+	// ErrInvalidArticleStatus is returned when an unknown article status is given
+	ErrInvalidArticleStatus = errors.New("invalid article status")
+	// ErrEmptySearchQuery is returned when a search query is empty or whitespace only
+	ErrEmptySearchQuery = errors.New("search query is empty")
+	// ErrInvalidArticleSort is returned when an unknown sort order is given
+	ErrInvalidArticleSort = errors.New("invalid article sort")
+	// ErrArticleNotFound is returned when the referenced article does not exist
+	ErrArticleNotFound = errors.New("article not found")
+	// ErrIdempotencyKeyInUse is returned when a request with the same idempotency key is still being processed
+	ErrIdempotencyKeyInUse = errors.New("idempotency key in use")
+	// ErrVersionConflict is returned when an article was modified after the version the caller based its update on
+	ErrVersionConflict = errors.New("version conflict")
+	// ErrRevisionNotFound is returned when the referenced revision does not exist for the article
+	ErrRevisionNotFound = errors.New("revision not found")
+	// ErrPinLimitReached is returned when pinning an article would exceed the pinned article limit
+	ErrPinLimitReached = errors.New("pinned article limit reached")
+)
+
+// IsValidArticleStatus reports whether status is a known article status
+func IsValidArticleStatus(status string) bool {
+	switch status {
+	case ArticleStatusDraft, ArticleStatusPublished, ArticleStatusArchived:
+		return true
+	}
+	return false
+}
+
+// maxArticleTitleLength is the maximum title length in characters
+const maxArticleTitleLength = 200
+
+// duplicateTitlePrefix is put before the title of an article copied by DuplicateArticle
+const duplicateTitlePrefix = "Copy of "
+
+// maxIdempotencyKeyLength matches idempotency_keys.key VARCHAR(255)
+const maxIdempotencyKeyLength = 255
+
+// DefaultMaxPublishAhead is how far in the future published_at may be scheduled by default
+const DefaultMaxPublishAhead = 365 * 24 * time.Hour
+
+// minPublishedAt is the earliest accepted published_at
+var minPublishedAt = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// DefaultMaxPinnedArticles is the default limit on the number of pinned articles
+const DefaultMaxPinnedArticles = 5
+
+// WarningContentSanitized is returned in Article.Warnings when unsafe HTML was removed from the content
+const WarningContentSanitized = "content: unsafe HTML (script elements, event handlers or javascript: URLs) was removed"
+
+// RelatedArticlesLimit is the number of related articles suggested for an article
+const RelatedArticlesLimit = 5
+
+// DefaultArticleSort lists the newest articles first, by ID so that pages follow the primary key
+const DefaultArticleSort = repository.ArticleSortIDDesc
+
+// IsValidArticleSort reports whether sort is an accepted article sort order
+func IsValidArticleSort(sort string) bool {
+	switch sort {
+	case repository.ArticleSortIDDesc,
+		repository.ArticleSortCreatedAt, repository.ArticleSortCreatedAtDesc,
+		repository.ArticleSortPublishedAt, repository.ArticleSortPublishedAtDesc,
+		repository.ArticleSortTitle:
+		return true
+	}
+	return false
+}
+
 // ArticleUsecase defines the interface for article business logic
 type ArticleUsecase interface {
-	CreateArticle(ctx context.Context, userID int64, title, content string, publishedAt pgtype.Timestamp) (db.Article, error)
-	GetArticle(ctx context.Context, id int64) (db.Article, error)
+	CreateArticle(ctx context.Context, in ArticleInput) (Article, error)
+	CreateArticleIdempotent(ctx context.Context, key string, in ArticleInput) (Article, bool, error)
+	DuplicateArticle(ctx context.Context, id, userID int64) (Article, error)
+	GetArticle(ctx context.Context, id int64) (Article, error)
+	GetArticleBySlug(ctx context.Context, slug string) (Article, error)
+	GetArticleByPublicID(ctx context.Context, publicID string) (Article, error)
 	ListArticles(ctx context.Context) ([]db.Article, error)
-	UpdateArticle(ctx context.Context, id, userID int64, title, content string, publishedAt pgtype.Timestamp) (db.Article, error)
+	ListArticlesPaginated(ctx context.Context, q ArticleListQuery) (ArticlePage, error)
+	CountArticles(ctx context.Context, status string, userID int64) (int64, error)
+	ListArchiveMonths(ctx context.Context) ([]db.ListArchiveMonthsRow, error)
+	CountArticlesByAuthor(ctx context.Context, status string, includeEmpty bool) ([]db.CountArticlesByAuthorRow, error)
+	ExportArticles(ctx context.Context, q ArticleExportQuery, emit func([]ArticleExportRow) error) error
+	SearchArticles(ctx context.Context, query string, limit int32) ([]Article, error)
+	ListRelatedArticles(ctx context.Context, id int64) ([]Article, error)
+	UpdateArticle(ctx context.Context, id int64, in ArticleInput) (Article, error)
+	UpdateArticlePartial(ctx context.Context, id int64, patch ArticlePatch) (Article, error)
+	ListArticleRevisions(ctx context.Context, id int64) ([]db.ArticleRevision, error)
+	RestoreArticleRevision(ctx context.Context, id, revisionID int64) (Article, error)
+	RecordArticleView(ctx context.Context, id int64) error
 	DeleteArticle(ctx context.Context, id int64) error
+	BulkDeleteArticles(ctx context.Context, ids []int64) (BulkDeleteResult, error)
+	RestoreArticle(ctx context.Context, id int64) (Article, error)
+	PublishArticle(ctx context.Context, id int64) (Article, bool, error)
+	UnpublishArticle(ctx context.Context, id int64) (Article, bool, error)
+	TransferArticle(ctx context.Context, id, newUserID int64) (Article, bool, error)
+	TransferUserArticles(ctx context.Context, userID, newUserID int64) (int64, error)
+	PinArticle(ctx context.Context, id int64) (Article, error)
+	UnpinArticle(ctx context.Context, id int64) (Article, error)
+	HardDeleteArticle(ctx context.Context, id int64) error
+	PublishScheduledArticles(ctx context.Context) ([]db.Article, error)
+	DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error)
+	AssignMissingPublicIDs(ctx context.Context) (int64, error)
+	ExpandAuthors(ctx context.Context, articles []Article) error
+	LocalizeArticles(ctx context.Context, articles []Article, locales []string) error
+	UpsertTranslation(ctx context.Context, id int64, locale string, in TranslationInput) (db.ArticleTranslation, error)
+}
+
+// Article is an article together with its associated data, as returned to clients
+type Article struct {
+	db.Article
+	Tags []string `json:"tags"`
+	// FeaturedImageURL is the public URL of the featured image, if any
+	FeaturedImageURL *string `json:"featured_image_url"`
+	// CategoryPath lists the article's category and its ancestors, root first; empty without a category
+	CategoryPath []CategoryRef `json:"category_path"`
+	// Author is only filled in by ExpandAuthors
+	Author *ArticleAuthor `json:"author,omitempty"`
+	// WordCount counts each Chinese or Japanese character as a word; see CountWords.
+	// It and ReadingTimeMinutes are only filled in by AddReadingTimes.
+	WordCount          *int `json:"word_count,omitempty"`
+	ReadingTimeMinutes *int `json:"reading_time_minutes,omitempty"`
+	// Locale is the language of Title and Content; LocalizeArticles switches it to a translation
+	Locale string `json:"locale"`
+	// Warnings describes changes made to the input when saving it, such as WarningContentSanitized
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ArticleAuthor is the compact form of an article's author embedded in expanded responses
+type ArticleAuthor struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// Active is false once the author has been deleted; their articles stay visible
+	Active bool `json:"active"`
+}
+
+// ArticleInput holds the writable fields of an article
+type ArticleInput struct {
+	UserID  int64
+	Title   string
+	Content string
+	// Status defaults to draft on create and to the current status on update
+	Status string
+	// PublishedAt is nil for articles without a publication time
+	PublishedAt *time.Time
+	// Tags replaces the article's tags; nil leaves them unchanged on update
+	Tags []string
+	// FeaturedImageID references a media file; nil for no featured image
+	FeaturedImageID *int64
+	// CategoryID references a category; nil for no category
+	CategoryID *int64
+	// Version is the version the update is based on; nil updates unconditionally
+	Version *int32
+}
+
+// TranslationInput holds the translated fields of an article
+type TranslationInput struct {
+	Title   string
+	Content string
+}
+
+// ArticlePatch holds the fields changed by a partial update; nil fields are left unchanged
+type ArticlePatch struct {
+	UserID      *int64
+	Title       *string
+	Content     *string
+	Status      *string
+	PublishedAt *time.Time
+	// Tags replaces the article's tags; a pointer to an empty slice removes them all
+	Tags *[]string
+	// FeaturedImageID sets the featured image; a pointer to 0 removes it
+	FeaturedImageID *int64
+	// CategoryID sets the category; a pointer to 0 removes it
+	CategoryID *int64
+	// Version is the version the patch is based on; nil means the version read when applying it
+	Version *int32
+}
+
+// BulkDeleteResult summarizes a bulk delete
+type BulkDeleteResult struct {
+	Deleted  []int64 `json:"deleted"`
+	NotFound []int64 `json:"not_found"`
+}
+
+// ArticleListQuery selects a page of articles
+type ArticleListQuery struct {
+	Status string
+	// Tag restricts the page to articles carrying that tag; empty disables the filter
+	Tag string
+	// CategoryID restricts the page to articles in that category or below it; 0 disables the filter
+	CategoryID int64
+	// PublishedFrom and PublishedTo restrict the page to articles published within that
+	// range, bounds included; nil leaves that side open
+	PublishedFrom *time.Time
+	PublishedTo   *time.Time
+	// Sort is one of the repository.ArticleSort constants; empty means DefaultArticleSort
+	Sort  string
+	Limit int32
+	// After is the position in Sort order after which the page starts; nil means the first page
+	After *repository.ArticleCursor
+}
+
+// ArticleExportQuery selects the articles to export
+type ArticleExportQuery struct {
+	Status string
+	// Tag restricts the export to articles carrying that tag; empty disables the filter
+	Tag string
+	// UserID restricts the export to that author; 0 disables the filter
+	UserID int64
+}
+
+// ArticleExportRow is one exported article together with its author's name
+type ArticleExportRow struct {
+	Article db.Article
+	// AuthorName is empty if the author no longer exists
+	AuthorName string
+}
+
+// ArticlePage represents a single page of articles
+type ArticlePage struct {
+	Items []Article
+	// Next is the position after which the following page starts (nil when there are no more rows)
+	Next *repository.ArticleCursor
+	// Total is the number of articles matching the query across all pages
+	Total int64
 }
 
 // articleUsecase implements ArticleUsecase interface
 type articleUsecase struct {
-	repo repository.ArticleRepository
+	repo       repository.ArticleRepository
+	tagRepo    repository.TagRepository
+	mediaRepo  repository.MediaRepository
+	mediaStore storage.ObjectStore
+	// categoryRepo resolves article category paths
+	categoryRepo repository.CategoryRepository
+	// userRepo loads the authors embedded by ExpandAuthors
+	userRepo repository.UserRepository
+	// idempotencyRepo remembers the articles created for Idempotency-Key values
+	idempotencyRepo repository.IdempotencyRepository
+	// revisionRepo reads the edit history; snapshots are written through tx
+	revisionRepo repository.ArticleRevisionRepository
+	// translationRepo stores the translations served by LocalizeArticles
+	translationRepo repository.ArticleTranslationRepository
+	// tx makes multi-step writes atomic
+	tx repository.Transactor
+	// maxPublishAhead bounds how far in the future published_at may be
+	maxPublishAhead time.Duration
+	// maxPinned is the number of articles that may be pinned at once
+	maxPinned int
+	// defaultLocale is the language of the articles themselves
+	defaultLocale string
+	// sanitizeContent strips unsafe HTML from content on create and update
+	sanitizeContent bool
 }
 
-// NewArticleUsecase creates a new instance of ArticleUsecase
-func NewArticleUsecase(repo repository.ArticleRepository) ArticleUsecase {
+// NewArticleUsecase creates a new instance of ArticleUsecase.
+// mediaStore resolves the URLs of featured images.
+// maxPublishAhead bounds how far in the future published_at may be set.
+// maxPinned limits how many articles may be pinned at once.
+// defaultLocale is the language articles are written in; translations add others.
+// sanitizeContent strips unsafe HTML from article content when it is saved.
+func NewArticleUsecase(repo repository.ArticleRepository, tagRepo repository.TagRepository, mediaRepo repository.MediaRepository, mediaStore storage.ObjectStore, categoryRepo repository.CategoryRepository, userRepo repository.UserRepository, idempotencyRepo repository.IdempotencyRepository, revisionRepo repository.ArticleRevisionRepository, translationRepo repository.ArticleTranslationRepository, tx repository.Transactor, maxPublishAhead time.Duration, maxPinned int, defaultLocale string, sanitizeContent bool) ArticleUsecase {
 	return &articleUsecase{
-		repo: repo,
+		repo:            repo,
+		tagRepo:         tagRepo,
+		mediaRepo:       mediaRepo,
+		mediaStore:      mediaStore,
+		categoryRepo:    categoryRepo,
+		userRepo:        userRepo,
+		idempotencyRepo: idempotencyRepo,
+		revisionRepo:    revisionRepo,
+		translationRepo: translationRepo,
+		tx:              tx,
+		maxPublishAhead: maxPublishAhead,
+		maxPinned:       maxPinned,
+		defaultLocale:   defaultLocale,
+		sanitizeContent: sanitizeContent,
+	}
+}
+
+// sanitize strips unsafe HTML from in.Content when sanitization is enabled.
+// It returns the warnings to report, if the content was changed.
+func (u *articleUsecase) sanitize(in *ArticleInput) []string {
+	if !u.sanitizeContent {
+		return nil
+	}
+	content, changed := markdown.StripUnsafeHTML(in.Content)
+	if !changed {
+		return nil
+	}
+	in.Content = content
+	return []string{WarningContentSanitized}
+}
+
+// normalizeArticleInput trims the title and validates the author, title and content
+func normalizeArticleInput(in *ArticleInput) error {
+	v := &ValidationError{}
+	if in.UserID == 0 {
+		v.Add("user_id", "is required")
+	}
+	in.Title = strings.TrimSpace(in.Title)
+	if in.Title == "" {
+		v.Add("title", "is required")
+	} else if utf8.RuneCountInString(in.Title) > maxArticleTitleLength {
+		v.Add("title", fmt.Sprintf("must be at most %d characters", maxArticleTitleLength))
+	}
+	if strings.TrimSpace(in.Content) == "" {
+		v.Add("content", "is required")
+	}
+	return v.Err()
+}
+
+// validatePublishedAt rejects published_at values before minPublishedAt
+// or more than maxPublishAhead after now
+func (u *articleUsecase) validatePublishedAt(publishedAt *time.Time) error {
+	if publishedAt == nil {
+		return nil
+	}
+	if publishedAt.Before(minPublishedAt) {
+		return invalidField("published_at", "must not be before 2000-01-01")
+	}
+	if publishedAt.After(time.Now().Add(u.maxPublishAhead)) {
+		return invalidField("published_at", "is too far in the future")
+	}
+	return nil
+}
+
+// CreateArticle creates a new article and attaches its tags
+func (u *articleUsecase) CreateArticle(ctx context.Context, in ArticleInput) (Article, error) {
+	if in.Status == "" {
+		in.Status = ArticleStatusDraft
+	}
+	if !IsValidArticleStatus(in.Status) {
+		return Article{}, ErrInvalidArticleStatus
+	}
+	warnings := u.sanitize(&in)
+	if err := normalizeArticleInput(&in); err != nil {
+		return Article{}, err
+	}
+	if err := u.validatePublishedAt(in.PublishedAt); err != nil {
+		return Article{}, err
+	}
+	if err := u.ensureMediaExists(ctx, in.FeaturedImageID); err != nil {
+		return Article{}, err
+	}
+	if err := u.ensureCategoryExists(ctx, in.CategoryID); err != nil {
+		return Article{}, err
+	}
+
+	// Create the article and attach its tags atomically, so a failure leaves no untagged article behind
+	var article db.Article
+	var tags []string
+	err := u.tx.WithTx(ctx, func(tx repository.Tx) error {
+		slug, err := uniqueSlug(ctx, tx.Articles(), Slugify(in.Title))
+		if err != nil {
+			return err
+		}
+
+		publicID, err := newPublicID(time.Now())
+		if err != nil {
+			return err
+		}
+
+		article, err = tx.Articles().Create(ctx, in.UserID, in.Title, in.Content, in.Status, slug, publicID, in.PublishedAt, in.FeaturedImageID, in.CategoryID)
+		if err != nil {
+			return err
+		}
+
+		tags, err = setTags(ctx, tx.Tags(), article.ID, in.Tags)
+		return err
+	})
+	if err != nil {
+		return Article{}, err
+	}
+
+	return u.withDetails(ctx, Article{Article: article, Tags: tags, Warnings: warnings})
+}
+
+// CreateArticleIdempotent creates an article at most once per key within 24 hours.
+// A repeated key returns the article created for it, as it is now, with replayed set.
+// While the first request with a key is still in progress, others get ErrIdempotencyKeyInUse.
+// If creation fails the key is released so the client can retry with it.
+func (u *articleUsecase) CreateArticleIdempotent(ctx context.Context, key string, in ArticleInput) (article Article, replayed bool, err error) {
+	if err := validateIdempotencyKey(key); err != nil {
+		return Article{}, false, err
+	}
+
+	claimed, err := u.idempotencyRepo.Claim(ctx, key)
+	if err != nil {
+		return Article{}, false, err
+	}
+	if !claimed {
+		record, err := u.idempotencyRepo.Get(ctx, key)
+		if errors.Is(err, sql.ErrNoRows) {
+			// Expired and cleaned up since the claim attempt
+			return Article{}, false, ErrIdempotencyKeyInUse
+		}
+		if err != nil {
+			return Article{}, false, err
+		}
+		if record.ArticleID == nil {
+			return Article{}, false, ErrIdempotencyKeyInUse
+		}
+		article, err := u.GetArticle(ctx, *record.ArticleID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return Article{}, false, ErrArticleNotFound
+		}
+		return article, true, err
+	}
+
+	article, err = u.CreateArticle(ctx, in)
+	if err != nil {
+		// Release even if the request was canceled, or the key stays blocked until it is abandoned
+		if relErr := u.idempotencyRepo.Release(context.WithoutCancel(ctx), key); relErr != nil {
+			slog.WarnContext(ctx, "Failed to release idempotency key", "error", relErr)
+		}
+		return Article{}, false, err
+	}
+	if err := u.idempotencyRepo.Complete(context.WithoutCancel(ctx), key, article.ID); err != nil {
+		return Article{}, false, err
+	}
+	return article, false, nil
+}
+
+// DuplicateArticle creates a draft owned by userID with the title (prefixed "Copy of "),
+// content and tags of article id. The copy has its own slug and public ID and no
+// published_at; the category and featured image are not copied. A title that would
+// exceed maxArticleTitleLength with the prefix is cut to fit.
+// It returns ErrArticleNotFound if the article does not exist.
+func (u *articleUsecase) DuplicateArticle(ctx context.Context, id, userID int64) (Article, error) {
+	// Read the source and create the copy atomically, so the copy matches one version of the source
+	var article db.Article
+	var tags []string
+	err := u.tx.WithTx(ctx, func(tx repository.Tx) error {
+		source, err := tx.Articles().GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		sourceTags, err := tx.Tags().ListByArticle(ctx, source.ID)
+		if err != nil {
+			return err
+		}
+
+		title := []rune(duplicateTitlePrefix + source.Title)
+		if len(title) > maxArticleTitleLength {
+			title = title[:maxArticleTitleLength]
+		}
+
+		slug, err := uniqueSlug(ctx, tx.Articles(), Slugify(string(title)))
+		if err != nil {
+			return err
+		}
+
+		publicID, err := newPublicID(time.Now())
+		if err != nil {
+			return err
+		}
+
+		article, err = tx.Articles().Create(ctx, userID, string(title), source.Content, ArticleStatusDraft, slug, publicID, nil, nil, nil)
+		if err != nil {
+			return err
+		}
+
+		names := make([]string, 0, len(sourceTags))
+		for _, tag := range sourceTags {
+			names = append(names, tag.Name)
+		}
+		tags, err = setTags(ctx, tx.Tags(), article.ID, names)
+		return err
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return Article{}, ErrArticleNotFound
+	}
+	if err != nil {
+		return Article{}, err
+	}
+
+	return u.withDetails(ctx, Article{Article: article, Tags: tags})
+}
+
+// validateIdempotencyKey accepts 1 to 255 characters of printable ASCII
+func validateIdempotencyKey(key string) error {
+	if key == "" || len(key) > maxIdempotencyKeyLength {
+		return invalidField("Idempotency-Key", fmt.Sprintf("must be 1 to %d characters", maxIdempotencyKeyLength))
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x21 || key[i] > 0x7e {
+			return invalidField("Idempotency-Key", "must be printable ASCII")
+		}
+	}
+	return nil
+}
+
+// DeleteExpiredIdempotencyKeys removes idempotency keys older than 24 hours
+func (u *articleUsecase) DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	return u.idempotencyRepo.DeleteExpired(ctx)
+}
+
+// publicIDBackfillBatch is the number of articles AssignMissingPublicIDs loads per query
+const publicIDBackfillBatch = 100
+
+// AssignMissingPublicIDs gives a public ID to every article created before public IDs were
+// introduced and returns how many it assigned
+func (u *articleUsecase) AssignMissingPublicIDs(ctx context.Context) (int64, error) {
+	var assigned int64
+	for {
+		ids, err := u.repo.ListWithoutPublicID(ctx, publicIDBackfillBatch)
+		if err != nil {
+			return assigned, err
+		}
+		for _, id := range ids {
+			publicID, err := newPublicID(time.Now())
+			if err != nil {
+				return assigned, err
+			}
+			if err := u.repo.SetPublicID(ctx, id, publicID); err != nil {
+				return assigned, err
+			}
+			assigned++
+		}
+		if len(ids) < publicIDBackfillBatch {
+			return assigned, nil
+		}
+	}
+}
+
+// uniqueSlug returns base, or base with the first free "-2", "-3", ... suffix.
+// Slugs of soft-deleted articles stay reserved so the article can be restored.
+func uniqueSlug(ctx context.Context, repo repository.ArticleRepository, base string) (string, error) {
+	slug := base
+	for n := 2; ; n++ {
+		exists, err := repo.SlugExists(ctx, slug)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return slug, nil
+		}
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// setTags replaces the tags of an article with names and returns the normalized tag names.
+// Tag rows are created on demand; tags no longer listed are detached.
+func setTags(ctx context.Context, tagRepo repository.TagRepository, articleID int64, names []string) ([]string, error) {
+	names = normalizeTags(names)
+
+	tagIDs := make([]int64, 0, len(names))
+	for _, name := range names {
+		tag, err := tagRepo.Upsert(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if err := tagRepo.Attach(ctx, articleID, tag.ID); err != nil {
+			return nil, err
+		}
+		tagIDs = append(tagIDs, tag.ID)
+	}
+
+	if err := tagRepo.DetachExcept(ctx, articleID, tagIDs); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// normalizeTags trims and lowercases tag names, dropping empty and duplicate entries
+func normalizeTags(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// withTags loads the tags of a single article
+func (u *articleUsecase) withTags(ctx context.Context, article db.Article) (Article, error) {
+	tags, err := u.tagRepo.ListByArticle(ctx, article.ID)
+	if err != nil {
+		return Article{}, err
+	}
+
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return u.withDetails(ctx, Article{Article: article, Tags: names})
+}
+
+// withTagsBatch loads the tags of several articles with a single query
+func (u *articleUsecase) withTagsBatch(ctx context.Context, articles []db.Article) ([]Article, error) {
+	ids := make([]int64, 0, len(articles))
+	for _, article := range articles {
+		ids = append(ids, article.ID)
+	}
+
+	tagsByArticle, err := u.tagRepo.ListNamesByArticles(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Article, 0, len(articles))
+	for _, article := range articles {
+		tags := tagsByArticle[article.ID]
+		if tags == nil {
+			tags = []string{}
+		}
+		result = append(result, Article{Article: article, Tags: tags})
+	}
+	if err := u.resolveDetails(ctx, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// withDetails resolves the featured image URL and category path of a single article
+func (u *articleUsecase) withDetails(ctx context.Context, article Article) (Article, error) {
+	items := []Article{article}
+	if err := u.resolveDetails(ctx, items); err != nil {
+		return Article{}, err
+	}
+	return items[0], nil
+}
+
+// resolveDetails fills in the featured image URLs and category paths of articles,
+// and sets their locale to the default one
+func (u *articleUsecase) resolveDetails(ctx context.Context, articles []Article) error {
+	for i := range articles {
+		articles[i].Locale = u.defaultLocale
+	}
+	if err := u.resolveFeaturedImages(ctx, articles); err != nil {
+		return err
+	}
+	return u.resolveCategoryPaths(ctx, articles)
+}
+
+// resolveFeaturedImages fills in FeaturedImageURL, loading all referenced media files with a single query
+func (u *articleUsecase) resolveFeaturedImages(ctx context.Context, articles []Article) error {
+	var ids []int64
+	for _, article := range articles {
+		if article.FeaturedImageID != nil {
+			ids = append(ids, *article.FeaturedImageID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	files, err := u.mediaRepo.ListByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	urls := make(map[int64]string, len(files))
+	for _, f := range files {
+		urls[f.ID] = u.mediaStore.URL(f.ObjectKey)
+	}
+
+	for i := range articles {
+		if id := articles[i].FeaturedImageID; id != nil {
+			if url, ok := urls[*id]; ok {
+				articles[i].FeaturedImageURL = &url
+			}
+		}
+	}
+	return nil
+}
+
+// resolveCategoryPaths fills in CategoryPath, loading the whole category tree with a single query
+// when any article has a category
+func (u *articleUsecase) resolveCategoryPaths(ctx context.Context, articles []Article) error {
+	for i := range articles {
+		articles[i].CategoryPath = []CategoryRef{}
+	}
+	if !slices.ContainsFunc(articles, func(a Article) bool { return a.CategoryID != nil }) {
+		return nil
+	}
+
+	categories, err := u.categoryRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	byID := make(map[int64]db.Category, len(categories))
+	for _, c := range categories {
+		byID[c.ID] = c
+	}
+
+	for i := range articles {
+		if id := articles[i].CategoryID; id != nil {
+			articles[i].CategoryPath = categoryPath(byID, *id)
+		}
+	}
+	return nil
+}
+
+// ExpandAuthors embeds the author of each article, loading all of them with a single query.
+// Soft-deleted authors are included but marked inactive.
+func (u *articleUsecase) ExpandAuthors(ctx context.Context, articles []Article) error {
+	if len(articles) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(articles))
+	for _, article := range articles {
+		ids = append(ids, article.UserID)
+	}
+
+	users, err := u.userRepo.ListByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	authors := make(map[int64]*ArticleAuthor, len(users))
+	for _, user := range users {
+		authors[user.ID] = &ArticleAuthor{ID: user.ID, Name: user.Name, Active: !user.DeletedAt.Valid}
+	}
+
+	for i := range articles {
+		articles[i].Author = authors[articles[i].UserID]
+	}
+	return nil
+}
+
+// LocalizeArticles replaces the title and content of each article with its translation into
+// the most preferred of locales that has one, and sets Locale to match. Articles without such
+// a translation stay in the default locale. locales must be canonical (see ParseLocale).
+func (u *articleUsecase) LocalizeArticles(ctx context.Context, articles []Article, locales []string) error {
+	candidates := fallbackLocales(locales, u.defaultLocale)
+	if len(articles) == 0 || len(candidates) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(articles))
+	for _, article := range articles {
+		ids = append(ids, article.ID)
+	}
+
+	translations, err := u.translationRepo.ListByArticles(ctx, ids, candidates)
+	if err != nil {
+		return err
+	}
+
+	for i := range articles {
+		for _, locale := range candidates {
+			if t, ok := translations[articles[i].ID][locale]; ok {
+				articles[i].Title = t.Title
+				articles[i].Content = t.Content
+				articles[i].Locale = locale
+				break
+			}
+		}
 	}
+	return nil
 }
 
-// CreateArticle creates a new article
-func (u *articleUsecase) CreateArticle(ctx context.Context, userID int64, title, content string, publishedAt pgtype.Timestamp) (db.Article, error) {
-	return u.repo.Create(ctx, userID, title, content, publishedAt)
+// UpsertTranslation creates or replaces the translation of an article into locale.
+// The default locale cannot be translated into, since it is the article's own title and content.
+// It returns ErrArticleNotFound if the article does not exist.
+func (u *articleUsecase) UpsertTranslation(ctx context.Context, id int64, locale string, in TranslationInput) (db.ArticleTranslation, error) {
+	v := &ValidationError{}
+	locale, err := ParseLocale(locale)
+	if err != nil {
+		v.Add("locale", "must be a BCP 47 language tag")
+	} else if locale == u.defaultLocale {
+		v.Add("locale", "is the default locale; update the article instead")
+	}
+	in.Title = strings.TrimSpace(in.Title)
+	if in.Title == "" {
+		v.Add("title", "is required")
+	} else if utf8.RuneCountInString(in.Title) > maxArticleTitleLength {
+		v.Add("title", fmt.Sprintf("must be at most %d characters", maxArticleTitleLength))
+	}
+	if strings.TrimSpace(in.Content) == "" {
+		v.Add("content", "is required")
+	}
+	if err := v.Err(); err != nil {
+		return db.ArticleTranslation{}, err
+	}
+
+	if _, err := u.repo.GetByID(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.ArticleTranslation{}, ErrArticleNotFound
+		}
+		return db.ArticleTranslation{}, err
+	}
+
+	translation, err := u.translationRepo.Upsert(ctx, id, locale, in.Title, in.Content)
+	if errors.Is(err, repository.ErrMissingReference) {
+		// The article was deleted permanently in the meantime
+		return db.ArticleTranslation{}, ErrArticleNotFound
+	}
+	return translation, err
+}
+
+// ensureCategoryExists returns a category_id ValidationError when id is set but no such category exists
+func (u *articleUsecase) ensureCategoryExists(ctx context.Context, id *int64) error {
+	if id == nil {
+		return nil
+	}
+	_, err := u.categoryRepo.GetByID(ctx, *id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return invalidField("category_id", "category not found")
+	}
+	return err
+}
+
+// ensureMediaExists returns a featured_image_id ValidationError when id is set but no such media file exists
+func (u *articleUsecase) ensureMediaExists(ctx context.Context, id *int64) error {
+	if id == nil {
+		return nil
+	}
+	_, err := u.mediaRepo.GetByID(ctx, *id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return invalidField("featured_image_id", "media not found")
+	}
+	return err
 }
 
 // GetArticle retrieves an article by ID
-func (u *articleUsecase) GetArticle(ctx context.Context, id int64) (db.Article, error) {
-	return u.repo.GetByID(ctx, id)
+func (u *articleUsecase) GetArticle(ctx context.Context, id int64) (Article, error) {
+	article, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return Article{}, err
+	}
+	return u.withTags(ctx, article)
+}
+
+// GetArticleBySlug retrieves an article by slug
+func (u *articleUsecase) GetArticleBySlug(ctx context.Context, slug string) (Article, error) {
+	article, err := u.repo.GetBySlug(ctx, slug)
+	if err != nil {
+		return Article{}, err
+	}
+	return u.withTags(ctx, article)
+}
+
+// GetArticleByPublicID retrieves an article by its public ID, which is matched case-insensitively
+func (u *articleUsecase) GetArticleByPublicID(ctx context.Context, publicID string) (Article, error) {
+	article, err := u.repo.GetByPublicID(ctx, strings.ToUpper(publicID))
+	if err != nil {
+		return Article{}, err
+	}
+	return u.withTags(ctx, article)
 }
 
 // ListArticles retrieves all articles
@@ -44,12 +876,566 @@ func (u *articleUsecase) ListArticles(ctx context.Context) ([]db.Article, error)
 	return u.repo.List(ctx)
 }
 
-// UpdateArticle updates an article
-func (u *articleUsecase) UpdateArticle(ctx context.Context, id, userID int64, title, content string, publishedAt pgtype.Timestamp) (db.Article, error) {
-	return u.repo.Update(ctx, id, userID, title, content, publishedAt)
+// ListArticlesPaginated retrieves a page of articles with the given status in the requested order
+func (u *articleUsecase) ListArticlesPaginated(ctx context.Context, q ArticleListQuery) (ArticlePage, error) {
+	if !IsValidArticleStatus(q.Status) {
+		return ArticlePage{}, ErrInvalidArticleStatus
+	}
+	if q.Sort == "" {
+		q.Sort = DefaultArticleSort
+	}
+	if !IsValidArticleSort(q.Sort) {
+		return ArticlePage{}, ErrInvalidArticleSort
+	}
+
+	var categoryIDs []int64
+	if q.CategoryID != 0 {
+		categories, err := u.categoryRepo.List(ctx)
+		if err != nil {
+			return ArticlePage{}, err
+		}
+		if !slices.ContainsFunc(categories, func(c db.Category) bool { return c.ID == q.CategoryID }) {
+			return ArticlePage{}, ErrCategoryNotFound
+		}
+		categoryIDs = categoryDescendants(categories, q.CategoryID)
+	}
+
+	// Fetch one extra row to find out whether another page exists
+	tag := strings.ToLower(strings.TrimSpace(q.Tag))
+	articles, err := u.repo.ListPaginated(ctx, q.Sort, q.Status, tag, categoryIDs, q.PublishedFrom, q.PublishedTo, q.Limit+1, q.After)
+	if err != nil {
+		return ArticlePage{}, err
+	}
+
+	var next *repository.ArticleCursor
+	if len(articles) > int(q.Limit) {
+		articles = articles[:q.Limit]
+		c := repository.ArticleCursorAt(q.Sort, articles[len(articles)-1])
+		next = &c
+	}
+
+	total, err := u.repo.Count(ctx, q.Status, tag, categoryIDs, q.PublishedFrom, q.PublishedTo, 0)
+	if err != nil {
+		return ArticlePage{}, err
+	}
+
+	items, err := u.withTagsBatch(ctx, articles)
+	if err != nil {
+		return ArticlePage{}, err
+	}
+	return ArticlePage{Items: items, Next: next, Total: total}, nil
+}
+
+// CountArticles counts articles with the given status; a non-zero userID restricts it to that author
+func (u *articleUsecase) CountArticles(ctx context.Context, status string, userID int64) (int64, error) {
+	if !IsValidArticleStatus(status) {
+		return 0, ErrInvalidArticleStatus
+	}
+	return u.repo.Count(ctx, status, "", nil, nil, nil, userID)
+}
+
+// ListArchiveMonths counts published articles per year and month of published_at, newest month first
+func (u *articleUsecase) ListArchiveMonths(ctx context.Context) ([]db.ListArchiveMonthsRow, error) {
+	return u.repo.ArchiveMonths(ctx)
 }
 
-// DeleteArticle deletes an article
+// CountArticlesByAuthor counts the articles of each active user, most articles first.
+// An empty status counts every status; users without articles are only included when includeEmpty is true.
+func (u *articleUsecase) CountArticlesByAuthor(ctx context.Context, status string, includeEmpty bool) ([]db.CountArticlesByAuthorRow, error) {
+	if status != "" && !IsValidArticleStatus(status) {
+		return nil, ErrInvalidArticleStatus
+	}
+	return u.repo.CountByAuthor(ctx, status, includeEmpty)
+}
+
+// exportBatchSize is the number of articles ExportArticles loads per query
+const exportBatchSize = 500
+
+// ExportArticles passes every article matching q to emit in ID order, exportBatchSize
+// articles at a time, so callers can stream an export without holding it all in memory.
+// It stops at the first error from the database or emit.
+func (u *articleUsecase) ExportArticles(ctx context.Context, q ArticleExportQuery, emit func([]ArticleExportRow) error) error {
+	if !IsValidArticleStatus(q.Status) {
+		return ErrInvalidArticleStatus
+	}
+	tag := strings.ToLower(strings.TrimSpace(q.Tag))
+
+	var afterID int64
+	for {
+		articles, err := u.repo.ListForExport(ctx, q.Status, tag, q.UserID, afterID, exportBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(articles) == 0 {
+			return nil
+		}
+
+		ids := make([]int64, 0, len(articles))
+		for _, article := range articles {
+			ids = append(ids, article.UserID)
+		}
+		users, err := u.userRepo.ListByIDs(ctx, ids)
+		if err != nil {
+			return err
+		}
+		names := make(map[int64]string, len(users))
+		for _, user := range users {
+			names[user.ID] = user.Name
+		}
+
+		rows := make([]ArticleExportRow, len(articles))
+		for i, article := range articles {
+			rows[i] = ArticleExportRow{Article: article, AuthorName: names[article.UserID]}
+		}
+		if err := emit(rows); err != nil {
+			return err
+		}
+
+		if len(articles) < exportBatchSize {
+			return nil
+		}
+		afterID = articles[len(articles)-1].ID
+	}
+}
+
+// PublishScheduledArticles publishes every draft whose published_at has passed
+// and returns the articles it published. Failures on individual articles do not
+// stop the run; they are joined into the returned error.
+func (u *articleUsecase) PublishScheduledArticles(ctx context.Context) ([]db.Article, error) {
+	due, err := u.repo.ListScheduled(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	published := make([]db.Article, 0, len(due))
+	var errs []error
+	for _, article := range due {
+		updated, err := u.repo.PublishScheduled(ctx, article.ID)
+		if errors.Is(err, sql.ErrNoRows) {
+			// Edited, deleted or published by someone else since it was listed
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("publish article %d: %w", article.ID, err))
+			continue
+		}
+		published = append(published, updated)
+	}
+	return published, errors.Join(errs...)
+}
+
+// SearchArticles retrieves published articles whose title or content contains query.
+// The query is matched literally and case-insensitively.
+func (u *articleUsecase) SearchArticles(ctx context.Context, query string, limit int32) ([]Article, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, ErrEmptySearchQuery
+	}
+
+	articles, err := u.repo.Search(ctx, "%"+escapeLike(query)+"%", limit)
+	if err != nil {
+		return nil, err
+	}
+	return u.withTagsBatch(ctx, articles)
+}
+
+// ListRelatedArticles retrieves up to RelatedArticlesLimit published articles sharing the most tags
+// with article id. When no article shares a tag with it, for example because it has no tags,
+// the latest articles of the same author are returned instead.
+func (u *articleUsecase) ListRelatedArticles(ctx context.Context, id int64) ([]Article, error) {
+	article, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrArticleNotFound
+		}
+		return nil, err
+	}
+
+	related, err := u.repo.ListRelated(ctx, id, RelatedArticlesLimit)
+	if err != nil {
+		return nil, err
+	}
+	if len(related) == 0 {
+		related, err = u.repo.ListRecentByAuthor(ctx, article.UserID, id, RelatedArticlesLimit)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return u.withTagsBatch(ctx, related)
+}
+
+// UpdateArticle updates an article and, when in.Tags is non-nil, replaces its tags.
+// The previous title and content are saved as a revision in the same transaction.
+// When in.Version is set and the article has since been modified, it returns ErrVersionConflict.
+func (u *articleUsecase) UpdateArticle(ctx context.Context, id int64, in ArticleInput) (Article, error) {
+	if in.Status == "" {
+		current, err := u.repo.GetByID(ctx, id)
+		if err != nil {
+			return Article{}, err
+		}
+		in.Status = current.Status
+	}
+	if !IsValidArticleStatus(in.Status) {
+		return Article{}, ErrInvalidArticleStatus
+	}
+	warnings := u.sanitize(&in)
+	if err := normalizeArticleInput(&in); err != nil {
+		return Article{}, err
+	}
+	if err := u.validatePublishedAt(in.PublishedAt); err != nil {
+		return Article{}, err
+	}
+	if err := u.ensureMediaExists(ctx, in.FeaturedImageID); err != nil {
+		return Article{}, err
+	}
+	if err := u.ensureCategoryExists(ctx, in.CategoryID); err != nil {
+		return Article{}, err
+	}
+
+	var (
+		article db.Article
+		tags    []string
+	)
+	err := u.tx.WithTx(ctx, func(tx repository.Tx) error {
+		if err := tx.Revisions().Snapshot(ctx, id); err != nil {
+			return err
+		}
+
+		var err error
+		article, err = tx.Articles().Update(ctx, id, in.UserID, in.Title, in.Content, in.Status, in.PublishedAt, in.FeaturedImageID, in.CategoryID, in.Version)
+		if err != nil || in.Tags == nil {
+			return err
+		}
+
+		tags, err = setTags(ctx, tx.Tags(), article.ID, in.Tags)
+		return err
+	})
+	if errors.Is(err, sql.ErrNoRows) && in.Version != nil {
+		// No row matched; tell a stale version apart from a missing article
+		if _, getErr := u.repo.GetByID(ctx, id); getErr == nil {
+			return Article{}, ErrVersionConflict
+		}
+	}
+	if err != nil {
+		return Article{}, err
+	}
+
+	var updated Article
+	if in.Tags == nil {
+		updated, err = u.withTags(ctx, article)
+	} else {
+		updated, err = u.withDetails(ctx, Article{Article: article, Tags: tags})
+	}
+	if err != nil {
+		return Article{}, err
+	}
+	updated.Warnings = warnings
+	return updated, nil
+}
+
+// UpdateArticlePartial loads an article, applies the non-nil fields of patch and saves it.
+// It returns ErrArticleNotFound if the article does not exist and ErrVersionConflict if
+// it was modified after patch.Version or, without one, while the patch was being applied.
+func (u *articleUsecase) UpdateArticlePartial(ctx context.Context, id int64, patch ArticlePatch) (Article, error) {
+	current, err := u.repo.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Article{}, ErrArticleNotFound
+	}
+	if err != nil {
+		return Article{}, err
+	}
+
+	in := ArticleInput{
+		UserID:          current.UserID,
+		Title:           current.Title,
+		Content:         current.Content,
+		Status:          current.Status,
+		PublishedAt:     timeOrNil(current.PublishedAt),
+		FeaturedImageID: current.FeaturedImageID,
+		CategoryID:      current.CategoryID,
+		// Guard against a concurrent update between reading and writing the article
+		Version: &current.Version,
+	}
+	if patch.Version != nil {
+		in.Version = patch.Version
+	}
+	if patch.UserID != nil {
+		in.UserID = *patch.UserID
+	}
+	if patch.Title != nil {
+		in.Title = *patch.Title
+	}
+	if patch.Content != nil {
+		in.Content = *patch.Content
+	}
+	if patch.Status != nil {
+		in.Status = *patch.Status
+	}
+	if patch.PublishedAt != nil {
+		in.PublishedAt = patch.PublishedAt
+	}
+	if patch.Tags != nil {
+		// A non-nil slice tells UpdateArticle to replace the tags
+		in.Tags = append([]string{}, *patch.Tags...)
+	}
+	if patch.FeaturedImageID != nil {
+		in.FeaturedImageID = patch.FeaturedImageID
+		if *patch.FeaturedImageID == 0 {
+			in.FeaturedImageID = nil
+		}
+	}
+	if patch.CategoryID != nil {
+		in.CategoryID = patch.CategoryID
+		if *patch.CategoryID == 0 {
+			in.CategoryID = nil
+		}
+	}
+
+	article, err := u.UpdateArticle(ctx, id, in)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Article{}, ErrArticleNotFound
+	}
+	return article, err
+}
+
+// ListArticleRevisions retrieves the saved revisions of an article, newest first.
+// It returns ErrArticleNotFound if the article does not exist.
+func (u *articleUsecase) ListArticleRevisions(ctx context.Context, id int64) ([]db.ArticleRevision, error) {
+	if _, err := u.repo.GetByID(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrArticleNotFound
+		}
+		return nil, err
+	}
+	return u.revisionRepo.List(ctx, id)
+}
+
+// RestoreArticleRevision sets the title and content of an article back to those of a revision.
+// The restore is an ordinary update, so the content it replaces becomes a revision in turn.
+func (u *articleUsecase) RestoreArticleRevision(ctx context.Context, id, revisionID int64) (Article, error) {
+	revision, err := u.revisionRepo.GetByID(ctx, id, revisionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Article{}, ErrRevisionNotFound
+	}
+	if err != nil {
+		return Article{}, err
+	}
+
+	return u.UpdateArticlePartial(ctx, id, ArticlePatch{
+		Title:   &revision.Title,
+		Content: &revision.Content,
+	})
+}
+
+// RecordArticleView counts one view of an article.
+// Every view is a row UPDATE; if write volume becomes a concern, sample here
+// (e.g. count 1 in N views and add N) or buffer counts in memory and flush periodically,
+// at the cost of exactness.
+func (u *articleUsecase) RecordArticleView(ctx context.Context, id int64) error {
+	return u.repo.IncrementViewCount(ctx, id)
+}
+
+// DeleteArticle soft-deletes an article; it can be brought back with RestoreArticle
 func (u *articleUsecase) DeleteArticle(ctx context.Context, id int64) error {
 	return u.repo.Delete(ctx, id)
 }
+
+// BulkDeleteArticles soft-deletes all given articles at once.
+// IDs that do not refer to a live article are reported in NotFound.
+func (u *articleUsecase) BulkDeleteArticles(ctx context.Context, ids []int64) (BulkDeleteResult, error) {
+	ids = slices.Compact(slices.Sorted(slices.Values(ids)))
+
+	deleted, err := u.repo.DeleteArticles(ctx, ids)
+	if err != nil {
+		return BulkDeleteResult{}, err
+	}
+	slices.Sort(deleted)
+
+	notFound := []int64{}
+	for _, id := range ids {
+		if _, ok := slices.BinarySearch(deleted, id); !ok {
+			notFound = append(notFound, id)
+		}
+	}
+	return BulkDeleteResult{Deleted: deleted, NotFound: notFound}, nil
+}
+
+// RestoreArticle undoes a soft delete
+func (u *articleUsecase) RestoreArticle(ctx context.Context, id int64) (Article, error) {
+	article, err := u.repo.Restore(ctx, id)
+	if err != nil {
+		return Article{}, err
+	}
+	return u.withTags(ctx, article)
+}
+
+// PublishArticle publishes an article now, setting published_at to the current time.
+// Publishing an article that is already published and visible leaves it unchanged;
+// changed reports whether the article was modified.
+// It returns ErrArticleNotFound if the article does not exist.
+func (u *articleUsecase) PublishArticle(ctx context.Context, id int64) (article Article, changed bool, err error) {
+	return u.setPublished(ctx, id, u.repo.Publish)
+}
+
+// UnpublishArticle turns an article back into a draft and clears published_at,
+// which also cancels a scheduled publication. Unpublishing a draft without published_at
+// leaves it unchanged; changed reports whether the article was modified.
+// It returns ErrArticleNotFound if the article does not exist.
+func (u *articleUsecase) UnpublishArticle(ctx context.Context, id int64) (article Article, changed bool, err error) {
+	return u.setPublished(ctx, id, u.repo.Unpublish)
+}
+
+// setPublished applies a publish or unpublish query, which matches no row when the article
+// is missing or already in the requested state; the two cases are told apart by a lookup.
+func (u *articleUsecase) setPublished(ctx context.Context, id int64, apply func(context.Context, int64) (db.Article, error)) (Article, bool, error) {
+	updated, err := apply(ctx, id)
+	changed := err == nil
+	if errors.Is(err, sql.ErrNoRows) {
+		updated, err = u.repo.GetByID(ctx, id)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return Article{}, false, ErrArticleNotFound
+	}
+	if err != nil {
+		return Article{}, false, err
+	}
+
+	article, err := u.withTags(ctx, updated)
+	if err != nil {
+		return Article{}, false, err
+	}
+	return article, changed, nil
+}
+
+// TransferArticle makes newUserID the author of article id. Transferring an article to its
+// current author leaves it unchanged; changed reports whether the article was modified.
+// It returns ErrArticleNotFound if the article does not exist and a ValidationError
+// if newUserID is not an active user.
+func (u *articleUsecase) TransferArticle(ctx context.Context, id, newUserID int64) (Article, bool, error) {
+	var (
+		article db.Article
+		changed bool
+	)
+	err := u.tx.WithTx(ctx, func(tx repository.Tx) error {
+		if err := ensureUserExists(ctx, tx.Users(), newUserID); err != nil {
+			return err
+		}
+
+		var err error
+		article, err = tx.Articles().Transfer(ctx, id, newUserID)
+		changed = err == nil
+		if errors.Is(err, sql.ErrNoRows) {
+			// Already owned by newUserID, or missing
+			article, err = tx.Articles().GetByID(ctx, id)
+		}
+		return err
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return Article{}, false, ErrArticleNotFound
+	}
+	if err != nil {
+		return Article{}, false, err
+	}
+
+	transferred, err := u.withTags(ctx, article)
+	if err != nil {
+		return Article{}, false, err
+	}
+	return transferred, changed, nil
+}
+
+// TransferUserArticles makes newUserID the author of every article of userID, including
+// soft-deleted ones so that restoring them later does not bring back the old author.
+// userID need not be an active user, so articles of deleted users can be reassigned.
+// It returns the number of articles moved, or a ValidationError if newUserID is userID
+// or not an active user.
+func (u *articleUsecase) TransferUserArticles(ctx context.Context, userID, newUserID int64) (int64, error) {
+	if newUserID == userID {
+		return 0, invalidField("new_user_id", "must differ from the current author")
+	}
+
+	var count int64
+	err := u.tx.WithTx(ctx, func(tx repository.Tx) error {
+		if err := ensureUserExists(ctx, tx.Users(), newUserID); err != nil {
+			return err
+		}
+
+		var err error
+		count, err = tx.Articles().TransferAllByUser(ctx, userID, newUserID)
+		return err
+	})
+	return count, err
+}
+
+// ensureUserExists returns a new_user_id ValidationError when there is no active user with id
+func ensureUserExists(ctx context.Context, users repository.UserRepository, id int64) error {
+	_, err := users.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return invalidField("new_user_id", "user not found")
+	}
+	return err
+}
+
+// PinArticle pins an article so that it is listed first.
+// Pinning an already pinned article is a no-op.
+// It returns ErrArticleNotFound if the article does not exist and ErrPinLimitReached
+// if maxPinned articles are already pinned.
+func (u *articleUsecase) PinArticle(ctx context.Context, id int64) (Article, error) {
+	var article db.Article
+	err := u.tx.WithTx(ctx, func(tx repository.Tx) error {
+		// Serialize pins so two requests cannot both take the last free slot
+		if err := tx.Articles().LockPinned(ctx); err != nil {
+			return err
+		}
+
+		var err error
+		article, err = tx.Articles().GetByID(ctx, id)
+		if err != nil || article.IsPinned {
+			return err
+		}
+
+		pinned, err := tx.Articles().CountPinned(ctx)
+		if err != nil {
+			return err
+		}
+		if pinned >= int64(u.maxPinned) {
+			return ErrPinLimitReached
+		}
+
+		article, err = tx.Articles().SetPinned(ctx, id, true)
+		return err
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return Article{}, ErrArticleNotFound
+	}
+	if err != nil {
+		return Article{}, err
+	}
+	return u.withTags(ctx, article)
+}
+
+// UnpinArticle unpins an article. Unpinning an article that is not pinned is a no-op.
+// It returns ErrArticleNotFound if the article does not exist.
+func (u *articleUsecase) UnpinArticle(ctx context.Context, id int64) (Article, error) {
+	article, err := u.repo.SetPinned(ctx, id, false)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Article{}, ErrArticleNotFound
+	}
+	if err != nil {
+		return Article{}, err
+	}
+	return u.withTags(ctx, article)
+}
+
+// HardDeleteArticle permanently removes an article and everything attached to it
+func (u *articleUsecase) HardDeleteArticle(ctx context.Context, id int64) error {
+	return u.repo.HardDelete(ctx, id)
+}
+
+// timeOrNil converts a nullable timestamp from the db layer into an optional time
+func timeOrNil(ts pgtype.Timestamp) *time.Time {
+	if !ts.Valid {
+		return nil
+	}
+	return &ts.Time
+}
@@ -0,0 +1,305 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/para7/nanaket-cms/internal/db"
+	"github.com/para7/nanaket-cms/internal/db/mock"
+	"github.com/para7/nanaket-cms/internal/repository"
+)
+
+// newCreateTestUsecase returns an ArticleUsecase whose transactions run on q.
+// Repositories other than media and categories are left nil, so unexpected calls panic.
+func newCreateTestUsecase(q *mock.Querier, media *mock.MediaRepository, categories *mock.CategoryRepository) ArticleUsecase {
+	tx := &mock.Transactor{
+		WithTxFunc: func(ctx context.Context, fn func(tx repository.Tx) error) error {
+			return fn(repository.NewTx(q))
+		},
+	}
+	return NewArticleUsecase(&mock.ArticleRepository{}, nil, media, nil, categories, nil, nil, nil, nil, tx, time.Hour, 5, "en", false)
+}
+
+// createQuerier returns a mock.Querier that accepts an article and its tags,
+// recording the create parameters and attached tag IDs
+func createQuerier(created *db.CreateArticleParams, attached *[]int64) *mock.Querier {
+	return &mock.Querier{
+		ArticleSlugExistsFunc: func(ctx context.Context, slug *string) (bool, error) {
+			return false, nil
+		},
+		CreateArticleFunc: func(ctx context.Context, arg db.CreateArticleParams) (db.Article, error) {
+			*created = arg
+			return db.Article{ID: 10, UserID: arg.UserID, Title: arg.Title, Content: arg.Content, Status: arg.Status, Slug: arg.Slug, PublicID: arg.PublicID, CategoryID: arg.CategoryID}, nil
+		},
+		UpsertTagFunc: func(ctx context.Context, name string) (db.Tag, error) {
+			return db.Tag{ID: int64(len(name)), Name: name}, nil
+		},
+		AttachTagFunc: func(ctx context.Context, arg db.AttachTagParams) error {
+			*attached = append(*attached, arg.TagID)
+			return nil
+		},
+		DetachTagsExceptFunc: func(ctx context.Context, arg db.DetachTagsExceptParams) error {
+			return nil
+		},
+	}
+}
+
+func TestCreateArticle(t *testing.T) {
+	var created db.CreateArticleParams
+	var attached []int64
+	u := newCreateTestUsecase(createQuerier(&created, &attached), nil, nil)
+
+	article, err := u.CreateArticle(context.Background(), ArticleInput{
+		UserID:  1,
+		Title:   "  Hello, World  ",
+		Content: "Body",
+		Tags:    []string{"Go", " go ", "news"},
+	})
+	if err != nil {
+		t.Fatalf("CreateArticle: %v", err)
+	}
+
+	if created.UserID != 1 || created.Title != "Hello, World" || created.Content != "Body" {
+		t.Errorf("stored %+v", created)
+	}
+	if created.Status != ArticleStatusDraft {
+		t.Errorf("stored status %q, want %q", created.Status, ArticleStatusDraft)
+	}
+	if created.Slug == nil || *created.Slug != "hello-world" {
+		t.Errorf("stored slug %v, want hello-world", created.Slug)
+	}
+	if created.PublicID == nil || *created.PublicID == "" {
+		t.Error("stored no public ID")
+	}
+	if created.PublishedAt.Valid {
+		t.Errorf("stored published_at %v, want NULL", created.PublishedAt)
+	}
+	if want := []int64{2, 4}; !slices.Equal(attached, want) {
+		t.Errorf("attached tag IDs %v, want %v", attached, want)
+	}
+
+	if article.ID != 10 || article.Title != "Hello, World" {
+		t.Errorf("returned %+v", article.Article)
+	}
+	if want := []string{"go", "news"}; !slices.Equal(article.Tags, want) {
+		t.Errorf("returned tags %v, want %v", article.Tags, want)
+	}
+	if article.Locale != "en" {
+		t.Errorf("returned locale %q, want en", article.Locale)
+	}
+}
+
+func TestCreateArticleSlugTaken(t *testing.T) {
+	var created db.CreateArticleParams
+	var attached []int64
+	q := createQuerier(&created, &attached)
+	q.ArticleSlugExistsFunc = func(ctx context.Context, slug *string) (bool, error) {
+		return *slug == "hello" || *slug == "hello-2", nil
+	}
+	u := newCreateTestUsecase(q, nil, nil)
+
+	if _, err := u.CreateArticle(context.Background(), ArticleInput{UserID: 1, Title: "Hello", Content: "Body"}); err != nil {
+		t.Fatalf("CreateArticle: %v", err)
+	}
+	if created.Slug == nil || *created.Slug != "hello-3" {
+		t.Errorf("stored slug %v, want hello-3", created.Slug)
+	}
+}
+
+func TestCreateArticleValidation(t *testing.T) {
+	// Nothing may reach the database, so the querier has no functions
+	u := newCreateTestUsecase(&mock.Querier{}, nil, nil)
+
+	_, err := u.CreateArticle(context.Background(), ArticleInput{Title: " ", Content: ""})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("got %v, want a ValidationError", err)
+	}
+	var fields []string
+	for _, f := range validationErr.Fields {
+		fields = append(fields, f.Field)
+	}
+	if want := []string{"user_id", "title", "content"}; !slices.Equal(fields, want) {
+		t.Errorf("invalid fields %v, want %v", fields, want)
+	}
+
+	_, err = u.CreateArticle(context.Background(), ArticleInput{UserID: 1, Title: "Hello", Content: "Body", Status: "deleted"})
+	if !errors.Is(err, ErrInvalidArticleStatus) {
+		t.Errorf("got %v, want ErrInvalidArticleStatus", err)
+	}
+}
+
+func TestCreateArticleMissingReferences(t *testing.T) {
+	missing := int64(99)
+	media := &mock.MediaRepository{
+		GetByIDFunc: func(ctx context.Context, id int64) (db.MediaFile, error) {
+			return db.MediaFile{}, sql.ErrNoRows
+		},
+	}
+	categories := &mock.CategoryRepository{
+		GetByIDFunc: func(ctx context.Context, id int64) (db.Category, error) {
+			return db.Category{}, sql.ErrNoRows
+		},
+	}
+	u := newCreateTestUsecase(&mock.Querier{}, media, categories)
+
+	tests := []struct {
+		name  string
+		in    ArticleInput
+		field string
+	}{
+		{"featured image", ArticleInput{UserID: 1, Title: "Hello", Content: "Body", FeaturedImageID: &missing}, "featured_image_id"},
+		{"category", ArticleInput{UserID: 1, Title: "Hello", Content: "Body", CategoryID: &missing}, "category_id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := u.CreateArticle(context.Background(), tt.in)
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || len(validationErr.Fields) != 1 || validationErr.Fields[0].Field != tt.field {
+				t.Errorf("got %v, want a ValidationError for %s", err, tt.field)
+			}
+		})
+	}
+}
+
+func TestCreateArticleStoreError(t *testing.T) {
+	errStore := errors.New("connection reset")
+
+	tests := []struct {
+		name  string
+		setup func(q *mock.Querier)
+	}{
+		{"create", func(q *mock.Querier) {
+			q.CreateArticleFunc = func(ctx context.Context, arg db.CreateArticleParams) (db.Article, error) {
+				return db.Article{}, errStore
+			}
+		}},
+		{"slug lookup", func(q *mock.Querier) {
+			q.ArticleSlugExistsFunc = func(ctx context.Context, slug *string) (bool, error) {
+				return false, errStore
+			}
+		}},
+		{"tags", func(q *mock.Querier) {
+			q.UpsertTagFunc = func(ctx context.Context, name string) (db.Tag, error) {
+				return db.Tag{}, errStore
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created db.CreateArticleParams
+			var attached []int64
+			q := createQuerier(&created, &attached)
+			tt.setup(q)
+			u := newCreateTestUsecase(q, nil, nil)
+
+			_, err := u.CreateArticle(context.Background(), ArticleInput{UserID: 1, Title: "Hello", Content: "Body", Tags: []string{"go"}})
+			if !errors.Is(err, errStore) {
+				t.Errorf("got %v, want %v", err, errStore)
+			}
+		})
+	}
+}
+
+func TestValidatePublishedAt(t *testing.T) {
+	u := &articleUsecase{maxPublishAhead: time.Hour}
+	now := time.Now()
+
+	tests := []struct {
+		name  string
+		at    time.Time
+		valid bool
+	}{
+		{"earliest", minPublishedAt, true},
+		{"before the earliest", minPublishedAt.Add(-time.Second), false},
+		{"negative Unix time", time.Unix(-1, 0), false},
+		{"now", now, true},
+		{"within the window", now.Add(59 * time.Minute), true},
+		{"past the window", now.Add(61 * time.Minute), false},
+		{"year 9999", time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := u.validatePublishedAt(&tt.at)
+			if tt.valid && err != nil {
+				t.Errorf("got %v, want nil", err)
+			}
+			var validationErr *ValidationError
+			if !tt.valid && (!errors.As(err, &validationErr) || validationErr.Fields[0].Field != "published_at") {
+				t.Errorf("got %v, want a published_at ValidationError", err)
+			}
+		})
+	}
+
+	if err := u.validatePublishedAt(nil); err != nil {
+		t.Errorf("nil published_at: got %v, want nil", err)
+	}
+}
+
+func TestDuplicateArticle(t *testing.T) {
+	slug, publicID := "hello", "01HZX3C5R6K9T1V2W3X4Y5Z6A7"
+	imageID, categoryID := int64(3), int64(4)
+	source := db.Article{
+		ID:              1,
+		UserID:          1,
+		Title:           "Hello",
+		Content:         "Body",
+		Status:          ArticleStatusPublished,
+		Slug:            &slug,
+		PublicID:        &publicID,
+		PublishedAt:     pgtype.Timestamp{Time: time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC), Valid: true},
+		FeaturedImageID: &imageID,
+		CategoryID:      &categoryID,
+	}
+
+	var created db.CreateArticleParams
+	var attached []int64
+	q := createQuerier(&created, &attached)
+	q.GetArticleFunc = func(ctx context.Context, id int64) (db.Article, error) {
+		if id != source.ID {
+			return db.Article{}, sql.ErrNoRows
+		}
+		return source, nil
+	}
+	q.ListTagsByArticleFunc = func(ctx context.Context, articleID int64) ([]db.Tag, error) {
+		return []db.Tag{{ID: 2, Name: "go"}, {ID: 4, Name: "news"}}, nil
+	}
+	q.ArticleSlugExistsFunc = func(ctx context.Context, slug *string) (bool, error) {
+		// The copy was duplicated once before
+		return *slug == "hello" || *slug == "copy-of-hello", nil
+	}
+	u := newCreateTestUsecase(q, nil, nil)
+
+	article, err := u.DuplicateArticle(context.Background(), source.ID, 2)
+	if err != nil {
+		t.Fatalf("DuplicateArticle: %v", err)
+	}
+
+	if created.Title != "Copy of Hello" || created.Content != source.Content || created.UserID != 2 {
+		t.Errorf("stored %+v", created)
+	}
+	if created.Status != ArticleStatusDraft {
+		t.Errorf("stored status %q, want %q", created.Status, ArticleStatusDraft)
+	}
+	if created.Slug == nil || *created.Slug != "copy-of-hello-2" {
+		t.Errorf("stored slug %v, want copy-of-hello-2", created.Slug)
+	}
+	if created.PublicID == nil || *created.PublicID == publicID {
+		t.Errorf("stored public ID %v, want a new one", created.PublicID)
+	}
+	if created.PublishedAt.Valid || created.FeaturedImageID != nil || created.CategoryID != nil {
+		t.Errorf("copied published_at, featured image or category: %+v", created)
+	}
+	if want := []string{"go", "news"}; !slices.Equal(article.Tags, want) {
+		t.Errorf("returned tags %v, want %v", article.Tags, want)
+	}
+
+	if _, err := u.DuplicateArticle(context.Background(), 99, 2); !errors.Is(err, ErrArticleNotFound) {
+		t.Errorf("missing source: got %v, want ErrArticleNotFound", err)
+	}
+}
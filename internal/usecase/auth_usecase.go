@@ -0,0 +1,311 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/para7/nanaket-cms/internal/db"
+	"github.com/para7/nanaket-cms/internal/repository"
+)
+
+// refreshedTokenTTL is the lifetime of tokens issued by Refresh
+const refreshedTokenTTL = 7 * 24 * time.Hour
+
+// loginTokenTTL is the lifetime of tokens issued by password and magic link logins
+const loginTokenTTL = 7 * 24 * time.Hour
+
+var (
+	// ErrInvalidToken is returned when a token is unknown or expired
+	ErrInvalidToken = errors.New("invalid or expired token")
+	// ErrTokenNotFound is returned when a user has no token with the given ID
+	ErrTokenNotFound = errors.New("token not found")
+)
+
+// AuthUsecase defines the interface for authentication business logic
+type AuthUsecase interface {
+	Authenticate(ctx context.Context, token string) (db.User, error)
+	RecordTokenUse(ctx context.Context, token string) error
+	Login(ctx context.Context, token string) (Session, error)
+	LoginSigned(ctx context.Context, token string) (SignedSession, error)
+	PasswordLogin(ctx context.Context, email, password string) (IssuedSession, error)
+	SetPassword(ctx context.Context, userID int64, password string, currentPassword *string) error
+	Refresh(ctx context.Context, token string) (IssuedToken, error)
+	CreateToken(ctx context.Context, userID int64, expiresAt pgtype.Timestamp) (IssuedToken, error)
+	ListTokens(ctx context.Context, userID int64) ([]db.AccessToken, error)
+	RevokeToken(ctx context.Context, userID, tokenID int64) error
+}
+
+// Session is an authenticated user together with the token that authenticated them
+type Session struct {
+	User  db.User
+	Token db.AccessToken
+}
+
+// SignedSession is an authenticated user together with a signed token issued to them
+type SignedSession struct {
+	User      db.User
+	Token     string
+	ExpiresAt time.Time
+}
+
+// IssuedSession is a user who logged in without a token together with the token issued to them
+type IssuedSession struct {
+	User  db.User
+	Token IssuedToken
+}
+
+// IssuedToken is a newly created token.
+// Secret is the plaintext token: it is handed to the client once and never stored.
+type IssuedToken struct {
+	Token  db.AccessToken
+	Secret string
+}
+
+// authUsecase implements AuthUsecase interface
+type authUsecase struct {
+	repo repository.AuthRepository
+	// signer verifies and issues signed tokens; nil disables them
+	signer *TokenSigner
+	// lockout locks password logins for an email after repeated failures; nil disables it
+	lockout *LoginLockout
+}
+
+// NewAuthUsecase creates a new instance of AuthUsecase.
+// signer enables signed tokens; pass nil to accept stored tokens only.
+// lockout locks password logins after repeated failures; pass nil to disable it.
+func NewAuthUsecase(repo repository.AuthRepository, signer *TokenSigner, lockout *LoginLockout) AuthUsecase {
+	return &authUsecase{
+		repo:    repo,
+		signer:  signer,
+		lockout: lockout,
+	}
+}
+
+// Authenticate returns the owner of a token.
+// Signed tokens are verified without a database lookup, so the returned user only has
+// the ID, name, email and role as of login. Stored tokens are looked up as before.
+// It returns ErrInvalidToken if the token is unknown, expired or badly signed.
+func (u *authUsecase) Authenticate(ctx context.Context, token string) (db.User, error) {
+	if isSignedToken(token) {
+		if u.signer == nil {
+			return db.User{}, ErrInvalidToken
+		}
+		return u.signer.verify(token, time.Now())
+	}
+
+	user, err := u.repo.GetUserByToken(ctx, hashToken(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.User{}, ErrInvalidToken
+	}
+	return user, err
+}
+
+// RecordTokenUse updates the token's last_used_at (at most once a minute).
+// Signed tokens are not stored, so their use is not recorded.
+func (u *authUsecase) RecordTokenUse(ctx context.Context, token string) error {
+	if isSignedToken(token) {
+		return nil
+	}
+	return u.repo.TouchToken(ctx, hashToken(token))
+}
+
+// Login validates a stored token and returns its owner and the token itself.
+// It returns ErrInvalidToken if the token is unknown, expired or a signed token.
+func (u *authUsecase) Login(ctx context.Context, token string) (Session, error) {
+	if isSignedToken(token) {
+		// Only stored tokens can start a session
+		return Session{}, ErrInvalidToken
+	}
+	user, err := u.Authenticate(ctx, token)
+	if err != nil {
+		return Session{}, err
+	}
+
+	accessToken, err := u.repo.GetToken(ctx, hashToken(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		// Expired between the two lookups
+		return Session{}, ErrInvalidToken
+	}
+	if err != nil {
+		return Session{}, err
+	}
+
+	return Session{User: user, Token: accessToken}, nil
+}
+
+// LoginSigned validates a stored token like Login and issues a signed token for its owner.
+// The signed token stays valid until it expires, even if the stored token is revoked.
+// It returns ErrSignedTokensDisabled if no signing key is configured.
+func (u *authUsecase) LoginSigned(ctx context.Context, token string) (SignedSession, error) {
+	if u.signer == nil {
+		return SignedSession{}, ErrSignedTokensDisabled
+	}
+	session, err := u.Login(ctx, token)
+	if err != nil {
+		return SignedSession{}, err
+	}
+
+	signed, expiresAt, err := u.signer.sign(session.User, time.Now())
+	if err != nil {
+		return SignedSession{}, err
+	}
+	return SignedSession{User: session.User, Token: signed, ExpiresAt: expiresAt}, nil
+}
+
+// PasswordLogin checks a user's email and password and issues a new stored token.
+// It returns ErrInvalidCredentials if no live user has the email, the user has no password
+// or the password is wrong; the cases are not told apart, and take about as long.
+// After repeated failures for the email it returns an *AccountLockedError until the cooldown
+// has passed, without checking the password; unregistered emails are locked the same way.
+func (u *authUsecase) PasswordLogin(ctx context.Context, email, password string) (IssuedSession, error) {
+	if u.lockout != nil {
+		if err := u.lockout.Check(email, time.Now()); err != nil {
+			return IssuedSession{}, err
+		}
+	}
+
+	user, err := u.repo.GetUserByEmail(ctx, strings.TrimSpace(email))
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && user.DeletedAt.Valid) {
+		// Still compare, so the response time does not reveal which emails are registered
+		checkPassword(nil, password)
+		u.recordLoginFailure(email)
+		return IssuedSession{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return IssuedSession{}, err
+	}
+	if !checkPassword(user.PasswordHash, password) {
+		u.recordLoginFailure(email)
+		return IssuedSession{}, ErrInvalidCredentials
+	}
+	if u.lockout != nil {
+		u.lockout.Reset(email)
+	}
+
+	issued, err := u.CreateToken(ctx, user.ID, pgtype.Timestamp{
+		Time:  time.Now().Add(loginTokenTTL),
+		Valid: true,
+	})
+	if err != nil {
+		return IssuedSession{}, err
+	}
+	return IssuedSession{User: user, Token: issued}, nil
+}
+
+// recordLoginFailure counts a failed password login for email towards its lockout
+func (u *authUsecase) recordLoginFailure(email string) {
+	if u.lockout != nil {
+		u.lockout.Fail(email, time.Now())
+	}
+}
+
+// SetPassword sets or changes a user's password.
+// When currentPassword is non-nil and the user already has a password, it must match,
+// otherwise ErrInvalidCredentials is returned; admins resetting another user's password pass nil.
+// It returns ErrUserNotFound if there is no live user with the given ID.
+func (u *authUsecase) SetPassword(ctx context.Context, userID int64, password string, currentPassword *string) error {
+	if err := validatePassword("password", password); err != nil {
+		return err
+	}
+
+	if currentPassword != nil {
+		user, err := u.repo.GetUserByID(ctx, userID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if user.PasswordHash != nil && !checkPassword(user.PasswordHash, *currentPassword) {
+			return ErrInvalidCredentials
+		}
+	}
+
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+	err = u.repo.SetPasswordHash(ctx, userID, &hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUserNotFound
+	}
+	return err
+}
+
+// Refresh exchanges a valid token for a new one with a fresh expiry and invalidates the old token.
+// It returns ErrInvalidToken if the token is unknown or expired.
+func (u *authUsecase) Refresh(ctx context.Context, token string) (IssuedToken, error) {
+	secret, err := generateToken()
+	if err != nil {
+		return IssuedToken{}, err
+	}
+
+	// Issue the new token and delete the old one in a single statement
+	accessToken, err := u.repo.RefreshToken(ctx, hashToken(token), hashToken(secret), pgtype.Timestamp{
+		Time:  time.Now().Add(refreshedTokenTTL),
+		Valid: true,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return IssuedToken{}, ErrInvalidToken
+	}
+	if err != nil {
+		return IssuedToken{}, err
+	}
+	return IssuedToken{Token: accessToken, Secret: secret}, nil
+}
+
+// CreateToken issues a new token for a user; an invalid expiresAt means the token never expires.
+// It returns ErrUserNotFound if the user does not exist.
+func (u *authUsecase) CreateToken(ctx context.Context, userID int64, expiresAt pgtype.Timestamp) (IssuedToken, error) {
+	secret, err := generateToken()
+	if err != nil {
+		return IssuedToken{}, err
+	}
+
+	accessToken, err := u.repo.CreateToken(ctx, userID, hashToken(secret), expiresAt)
+	if errors.Is(err, repository.ErrMissingReference) {
+		return IssuedToken{}, ErrUserNotFound
+	}
+	if err != nil {
+		return IssuedToken{}, err
+	}
+	return IssuedToken{Token: accessToken, Secret: secret}, nil
+}
+
+// ListTokens retrieves a user's non-expired tokens, newest first
+func (u *authUsecase) ListTokens(ctx context.Context, userID int64) ([]db.AccessToken, error) {
+	return u.repo.ListTokensByUser(ctx, userID)
+}
+
+// RevokeToken deletes one of a user's tokens, ending any session using it.
+// It returns ErrTokenNotFound if the user has no token with that ID.
+func (u *authUsecase) RevokeToken(ctx context.Context, userID, tokenID int64) error {
+	err := u.repo.DeleteTokenByID(ctx, userID, tokenID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrTokenNotFound
+	}
+	return err
+}
+
+// generateToken returns a new random access token
+func generateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashToken returns the hex-encoded SHA-256 of a token, which is what access_tokens stores.
+// Tokens are long random values, so an unsalted fast hash is sufficient.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
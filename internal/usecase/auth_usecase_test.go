@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/para7/nanaket-cms/internal/db"
+	"github.com/para7/nanaket-cms/internal/db/mock"
+)
+
+// authRepoWithToken returns a mock.AuthRepository that knows a single token of user
+func authRepoWithToken(token string, user db.User) *mock.AuthRepository {
+	hash := hashToken(token)
+	return &mock.AuthRepository{
+		GetUserByTokenFunc: func(ctx context.Context, tokenHash string) (db.User, error) {
+			if tokenHash != hash {
+				return db.User{}, sql.ErrNoRows
+			}
+			return user, nil
+		},
+		GetTokenFunc: func(ctx context.Context, tokenHash string) (db.AccessToken, error) {
+			if tokenHash != hash {
+				return db.AccessToken{}, sql.ErrNoRows
+			}
+			return db.AccessToken{ID: 7, UserID: user.ID, Token: hash}, nil
+		},
+	}
+}
+
+func TestAuthLogin(t *testing.T) {
+	user := db.User{ID: 1, Email: "user@example.com", Role: UserRoleEditor}
+	u := NewAuthUsecase(authRepoWithToken("secret", user), nil, nil)
+
+	session, err := u.Login(context.Background(), "secret")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if session.User.ID != user.ID || session.Token.ID != 7 {
+		t.Errorf("session = %+v", session)
+	}
+
+	if _, err := u.Login(context.Background(), "unknown"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("unknown token: got %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestAuthLoginTokenExpiresBetweenLookups(t *testing.T) {
+	repo := authRepoWithToken("secret", db.User{ID: 1})
+	repo.GetTokenFunc = func(ctx context.Context, tokenHash string) (db.AccessToken, error) {
+		return db.AccessToken{}, sql.ErrNoRows
+	}
+	u := NewAuthUsecase(repo, nil, nil)
+
+	if _, err := u.Login(context.Background(), "secret"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("got %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestAuthLoginRejectsSignedTokens(t *testing.T) {
+	// Signed tokens are refused before any lookup, so the repository has no functions
+	u := NewAuthUsecase(&mock.AuthRepository{}, nil, nil)
+
+	if _, err := u.Login(context.Background(), signedTokenPrefix+"payload.signature"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("got %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestAuthAuthenticateStoreError(t *testing.T) {
+	errStore := errors.New("connection reset")
+	u := NewAuthUsecase(&mock.AuthRepository{
+		GetUserByTokenFunc: func(ctx context.Context, tokenHash string) (db.User, error) {
+			return db.User{}, errStore
+		},
+	}, nil, nil)
+
+	// Only a missing row means the token is invalid; other errors are passed on
+	if _, err := u.Authenticate(context.Background(), "secret"); !errors.Is(err, errStore) {
+		t.Errorf("got %v, want %v", err, errStore)
+	}
+}
+
+func TestAuthRefresh(t *testing.T) {
+	var oldHash, newHash string
+	u := NewAuthUsecase(&mock.AuthRepository{
+		RefreshTokenFunc: func(ctx context.Context, oldTokenHash, newTokenHash string, expiresAt pgtype.Timestamp) (db.AccessToken, error) {
+			if oldTokenHash != hashToken("secret") {
+				return db.AccessToken{}, sql.ErrNoRows
+			}
+			oldHash, newHash = oldTokenHash, newTokenHash
+			return db.AccessToken{ID: 8, Token: newTokenHash, ExpiresAt: expiresAt}, nil
+		},
+	}, nil, nil)
+
+	issued, err := u.Refresh(context.Background(), "secret")
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if oldHash != hashToken("secret") {
+		t.Errorf("old token looked up as %q, want its hash", oldHash)
+	}
+	if issued.Secret == "" || newHash != hashToken(issued.Secret) {
+		t.Errorf("stored %q for secret %q, want the hash of the secret", newHash, issued.Secret)
+	}
+	if !issued.Token.ExpiresAt.Valid {
+		t.Error("refreshed token has no expiry")
+	}
+
+	if _, err := u.Refresh(context.Background(), "unknown"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("unknown token: got %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestAuthRevokeTokenNotFound(t *testing.T) {
+	u := NewAuthUsecase(&mock.AuthRepository{
+		DeleteTokenByIDFunc: func(ctx context.Context, userID, tokenID int64) error {
+			return sql.ErrNoRows
+		},
+	}, nil, nil)
+
+	if err := u.RevokeToken(context.Background(), 1, 99); !errors.Is(err, ErrTokenNotFound) {
+		t.Errorf("got %v, want ErrTokenNotFound", err)
+	}
+}
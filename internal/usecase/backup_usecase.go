@@ -0,0 +1,401 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/para7/nanaket-cms/internal/db"
+	"github.com/para7/nanaket-cms/internal/repository"
+)
+
+// BackupVersion is the format version written by Export and accepted by Import
+const BackupVersion = 1
+
+// BackupUsecase defines the interface for dumping and restoring content
+type BackupUsecase interface {
+	Export(ctx context.Context) (BackupDocument, error)
+	Import(ctx context.Context, doc BackupDocument, preserveIDs bool) (ImportResult, error)
+}
+
+// BackupDocument holds every user and article, including soft-deleted ones
+type BackupDocument struct {
+	Version    int             `json:"version"`
+	ExportedAt time.Time       `json:"exported_at"`
+	Users      []db.User       `json:"users"`
+	Articles   []BackupArticle `json:"articles"`
+}
+
+// BackupArticle is an article together with its tag names
+type BackupArticle struct {
+	db.Article
+	Tags []string `json:"tags"`
+}
+
+// ImportResult summarizes an import.
+// UserIDs and ArticleIDs map the IDs in the document to the IDs assigned on import.
+type ImportResult struct {
+	Users      int             `json:"users"`
+	Articles   int             `json:"articles"`
+	UserIDs    map[int64]int64 `json:"user_ids"`
+	ArticleIDs map[int64]int64 `json:"article_ids"`
+}
+
+// ImportConflict describes one record of a backup that cannot be imported as is
+type ImportConflict struct {
+	// Kind is "user" or "article"
+	Kind string `json:"kind"`
+	// ID is the record's ID in the document
+	ID      int64  `json:"id"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ImportConflictError is returned when a backup clashes with existing data or references missing rows.
+// Nothing is imported; Conflicts lists every problem found.
+type ImportConflictError struct {
+	Conflicts []ImportConflict
+}
+
+// add records a conflict
+func (e *ImportConflictError) add(kind string, id int64, field, message string) {
+	e.Conflicts = append(e.Conflicts, ImportConflict{Kind: kind, ID: id, Field: field, Message: message})
+}
+
+// Error implements the error interface
+func (e *ImportConflictError) Error() string {
+	return fmt.Sprintf("import has %d conflicts", len(e.Conflicts))
+}
+
+// backupUsecase implements BackupUsecase interface
+type backupUsecase struct {
+	userRepo     repository.UserRepository
+	articleRepo  repository.ArticleRepository
+	tagRepo      repository.TagRepository
+	categoryRepo repository.CategoryRepository
+	mediaRepo    repository.MediaRepository
+	// tx makes Import all-or-nothing
+	tx repository.Transactor
+}
+
+// NewBackupUsecase creates a new instance of BackupUsecase.
+// categoryRepo and mediaRepo check the categories and featured images referenced by imported articles.
+func NewBackupUsecase(userRepo repository.UserRepository, articleRepo repository.ArticleRepository, tagRepo repository.TagRepository, categoryRepo repository.CategoryRepository, mediaRepo repository.MediaRepository, tx repository.Transactor) BackupUsecase {
+	return &backupUsecase{
+		userRepo:     userRepo,
+		articleRepo:  articleRepo,
+		tagRepo:      tagRepo,
+		categoryRepo: categoryRepo,
+		mediaRepo:    mediaRepo,
+		tx:           tx,
+	}
+}
+
+// Export returns every user and article with its tags.
+// Categories and media files are not included; imported articles reference them by ID.
+func (u *backupUsecase) Export(ctx context.Context) (BackupDocument, error) {
+	users, err := u.userRepo.ListAll(ctx)
+	if err != nil {
+		return BackupDocument{}, err
+	}
+	articles, err := u.articleRepo.ListAll(ctx)
+	if err != nil {
+		return BackupDocument{}, err
+	}
+
+	ids := make([]int64, len(articles))
+	for i, article := range articles {
+		ids[i] = article.ID
+	}
+	tagsByArticle, err := u.tagRepo.ListNamesByArticles(ctx, ids)
+	if err != nil {
+		return BackupDocument{}, err
+	}
+
+	items := make([]BackupArticle, len(articles))
+	for i, article := range articles {
+		tags := tagsByArticle[article.ID]
+		if tags == nil {
+			tags = []string{}
+		}
+		items[i] = BackupArticle{Article: article, Tags: tags}
+	}
+	return BackupDocument{
+		Version:    BackupVersion,
+		ExportedAt: time.Now().UTC(),
+		Users:      users,
+		Articles:   items,
+	}, nil
+}
+
+// Import inserts the users and articles of doc in a single transaction.
+// With preserveIDs every record keeps its ID (and articles their public ID); otherwise new IDs are assigned and
+// article user_ids that refer to users in the document are rewritten to match.
+// Invalid records yield a ValidationError. Records that clash with existing data or
+// reference users, categories or media files that do not exist yield an ImportConflictError.
+// In both cases nothing is imported.
+func (u *backupUsecase) Import(ctx context.Context, doc BackupDocument, preserveIDs bool) (ImportResult, error) {
+	if err := validateBackup(&doc); err != nil {
+		return ImportResult{}, err
+	}
+
+	result := ImportResult{
+		UserIDs:    make(map[int64]int64, len(doc.Users)),
+		ArticleIDs: make(map[int64]int64, len(doc.Articles)),
+	}
+	err := u.tx.WithTx(ctx, func(tx repository.Tx) error {
+		if err := u.checkConflicts(ctx, tx, doc, preserveIDs); err != nil {
+			return err
+		}
+
+		for _, user := range doc.Users {
+			imported, err := tx.Users().Import(ctx, user, preserveIDs)
+			if err != nil {
+				return fmt.Errorf("import user %d: %w", user.ID, err)
+			}
+			result.UserIDs[user.ID] = imported.ID
+		}
+		for _, article := range doc.Articles {
+			if newID, ok := result.UserIDs[article.UserID]; ok {
+				article.UserID = newID
+			}
+			// Public IDs follow IDs: kept with preserveIDs, otherwise issued afresh like new articles get
+			if !preserveIDs || article.PublicID == nil {
+				publicID, err := newPublicID(time.Now())
+				if err != nil {
+					return err
+				}
+				article.PublicID = &publicID
+			}
+			imported, err := tx.Articles().Import(ctx, article.Article, preserveIDs)
+			if err != nil {
+				return fmt.Errorf("import article %d: %w", article.ID, err)
+			}
+			if _, err := setTags(ctx, tx.Tags(), imported.ID, article.Tags); err != nil {
+				return fmt.Errorf("import article %d: %w", article.ID, err)
+			}
+			result.ArticleIDs[article.ID] = imported.ID
+		}
+
+		if !preserveIDs {
+			return nil
+		}
+		if err := tx.Users().SyncIDSequence(ctx); err != nil {
+			return err
+		}
+		return tx.Articles().SyncIDSequence(ctx)
+	})
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	result.Users = len(doc.Users)
+	result.Articles = len(doc.Articles)
+	return result, nil
+}
+
+// validateBackup checks the format version and the fields of every record,
+// normalizing emails and trimming titles as CreateUser and CreateArticle do
+func validateBackup(doc *BackupDocument) error {
+	v := &ValidationError{}
+	if doc.Version != BackupVersion {
+		v.Add("version", fmt.Sprintf("must be %d", BackupVersion))
+	}
+
+	for i := range doc.Users {
+		user := &doc.Users[i]
+		field := fmt.Sprintf("users[%d].", i)
+		email, err := normalizeUserInput(user.Email, user.Name)
+		var ve *ValidationError
+		if errors.As(err, &ve) {
+			for _, f := range ve.Fields {
+				v.Add(field+f.Field, f.Message)
+			}
+		}
+		user.Email = email
+		if !IsValidUserRole(user.Role) {
+			v.Add(field+"role", "invalid role")
+		}
+		if !user.CreatedAt.Valid || !user.UpdatedAt.Valid {
+			v.Add(field+"created_at", "created_at and updated_at are required")
+		}
+	}
+
+	for i := range doc.Articles {
+		article := &doc.Articles[i]
+		field := fmt.Sprintf("articles[%d].", i)
+		in := ArticleInput{UserID: article.UserID, Title: article.Title, Content: article.Content}
+		var ve *ValidationError
+		if errors.As(normalizeArticleInput(&in), &ve) {
+			for _, f := range ve.Fields {
+				v.Add(field+f.Field, f.Message)
+			}
+		}
+		article.Title = in.Title
+		if !IsValidArticleStatus(article.Status) {
+			v.Add(field+"status", "invalid status")
+		}
+		if !article.CreatedAt.Valid || !article.UpdatedAt.Valid {
+			v.Add(field+"created_at", "created_at and updated_at are required")
+		}
+		if article.Version < 1 {
+			v.Add(field+"version", "must be at least 1")
+		}
+	}
+	return v.Err()
+}
+
+// checkConflicts finds every record of doc that clashes with another record or existing data,
+// or that references a user, category or media file that does not exist.
+// It runs before anything is inserted, so an import with conflicts writes nothing.
+func (u *backupUsecase) checkConflicts(ctx context.Context, tx repository.Tx, doc BackupDocument, preserveIDs bool) error {
+	conflicts := &ImportConflictError{}
+
+	userIDs := make(map[int64]bool, len(doc.Users))
+	emails := make(map[string]int64, len(doc.Users))
+	for _, user := range doc.Users {
+		if userIDs[user.ID] {
+			conflicts.add("user", user.ID, "id", "duplicate id in document")
+		}
+		userIDs[user.ID] = true
+		// Emails are unique ignoring case
+		email := strings.ToLower(user.Email)
+		if _, ok := emails[email]; ok {
+			conflicts.add("user", user.ID, "email", "duplicate email in document")
+		}
+		emails[email] = user.ID
+	}
+
+	articleIDs := make(map[int64]bool, len(doc.Articles))
+	slugs := make(map[string]int64, len(doc.Articles))
+	var externalUserIDs, categoryIDs, mediaIDs []int64
+	for _, article := range doc.Articles {
+		if articleIDs[article.ID] {
+			conflicts.add("article", article.ID, "id", "duplicate id in document")
+		}
+		articleIDs[article.ID] = true
+		if article.Slug != nil {
+			if _, ok := slugs[*article.Slug]; ok {
+				conflicts.add("article", article.ID, "slug", "duplicate slug in document")
+			}
+			slugs[*article.Slug] = article.ID
+		}
+		if !userIDs[article.UserID] {
+			externalUserIDs = append(externalUserIDs, article.UserID)
+		}
+		if article.CategoryID != nil {
+			categoryIDs = append(categoryIDs, *article.CategoryID)
+		}
+		if article.FeaturedImageID != nil {
+			mediaIDs = append(mediaIDs, *article.FeaturedImageID)
+		}
+	}
+
+	// Existing rows, including soft-deleted ones, still hold their IDs, emails and slugs
+	if preserveIDs && len(userIDs) > 0 {
+		existing, err := tx.Users().ListByIDs(ctx, slices.Collect(maps.Keys(userIDs)))
+		if err != nil {
+			return err
+		}
+		for _, user := range existing {
+			conflicts.add("user", user.ID, "id", "already exists")
+		}
+	}
+	if len(emails) > 0 {
+		existing, err := tx.Users().ExistingEmails(ctx, slices.Collect(maps.Keys(emails)))
+		if err != nil {
+			return err
+		}
+		for _, email := range existing {
+			conflicts.add("user", emails[email], "email", "already exists")
+		}
+	}
+	if preserveIDs && len(articleIDs) > 0 {
+		existing, err := tx.Articles().ExistingIDs(ctx, slices.Collect(maps.Keys(articleIDs)))
+		if err != nil {
+			return err
+		}
+		for _, id := range existing {
+			conflicts.add("article", id, "id", "already exists")
+		}
+	}
+	if len(slugs) > 0 {
+		existing, err := tx.Articles().ExistingSlugs(ctx, slices.Collect(maps.Keys(slugs)))
+		if err != nil {
+			return err
+		}
+		for _, slug := range existing {
+			conflicts.add("article", slugs[slug], "slug", "already exists")
+		}
+	}
+
+	// Referenced rows outside the document must already exist
+	found, err := u.existingReferences(ctx, tx, externalUserIDs, categoryIDs, mediaIDs)
+	if err != nil {
+		return err
+	}
+	for _, article := range doc.Articles {
+		if !userIDs[article.UserID] && !found.users[article.UserID] {
+			conflicts.add("article", article.ID, "user_id", "user not found")
+		}
+		if article.CategoryID != nil && !found.categories[*article.CategoryID] {
+			conflicts.add("article", article.ID, "category_id", "category not found")
+		}
+		if article.FeaturedImageID != nil && !found.media[*article.FeaturedImageID] {
+			conflicts.add("article", article.ID, "featured_image_id", "media file not found")
+		}
+	}
+
+	if len(conflicts.Conflicts) > 0 {
+		return conflicts
+	}
+	return nil
+}
+
+// foundReferences holds the IDs of the referenced rows that exist
+type foundReferences struct {
+	users, categories, media map[int64]bool
+}
+
+// existingReferences looks up which of the given users, categories and media files exist.
+// Soft-deleted users count as existing, since their articles are kept.
+func (u *backupUsecase) existingReferences(ctx context.Context, tx repository.Tx, userIDs, categoryIDs, mediaIDs []int64) (foundReferences, error) {
+	found := foundReferences{
+		users:      make(map[int64]bool),
+		categories: make(map[int64]bool),
+		media:      make(map[int64]bool),
+	}
+
+	if len(userIDs) > 0 {
+		users, err := tx.Users().ListByIDs(ctx, userIDs)
+		if err != nil {
+			return foundReferences{}, err
+		}
+		for _, user := range users {
+			found.users[user.ID] = true
+		}
+	}
+	if len(categoryIDs) > 0 {
+		categories, err := u.categoryRepo.List(ctx)
+		if err != nil {
+			return foundReferences{}, err
+		}
+		for _, category := range categories {
+			found.categories[category.ID] = true
+		}
+	}
+	if len(mediaIDs) > 0 {
+		media, err := u.mediaRepo.ListByIDs(ctx, mediaIDs)
+		if err != nil {
+			return foundReferences{}, err
+		}
+		for _, m := range media {
+			found.media[m.ID] = true
+		}
+	}
+	return found, nil
+}
@@ -0,0 +1,225 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/para7/nanaket-cms/internal/db"
+	"github.com/para7/nanaket-cms/internal/repository"
+)
+
+// maxCategoryNameLength matches categories.name VARCHAR(100)
+const maxCategoryNameLength = 100
+
+// maxCategoryDepth is the largest number of levels in the category tree
+const maxCategoryDepth = 8
+
+var (
+	// ErrCategoryNotFound is returned when the referenced category does not exist
+	ErrCategoryNotFound = errors.New("category not found")
+	// ErrCategorySlugTaken is returned when the slug is already used by another category
+	ErrCategorySlugTaken = errors.New("category slug already taken")
+	// ErrCategoryInUse is returned when deleting a category that still has subcategories or articles
+	ErrCategoryInUse = errors.New("category in use")
+)
+
+// CategoryUsecase defines the interface for category business logic
+type CategoryUsecase interface {
+	CreateCategory(ctx context.Context, in CategoryInput) (db.Category, error)
+	GetCategory(ctx context.Context, id int64) (db.Category, error)
+	ListCategories(ctx context.Context) ([]db.Category, error)
+	UpdateCategory(ctx context.Context, id int64, in CategoryInput) (db.Category, error)
+	DeleteCategory(ctx context.Context, id int64) error
+}
+
+// CategoryInput holds the writable fields of a category
+type CategoryInput struct {
+	Name string
+	// Slug defaults to the slugified name
+	Slug string
+	// ParentID is nil for a top-level category
+	ParentID *int64
+}
+
+// CategoryRef identifies one category in an article's category path
+type CategoryRef struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// categoryUsecase implements CategoryUsecase interface
+type categoryUsecase struct {
+	repo repository.CategoryRepository
+}
+
+// NewCategoryUsecase creates a new instance of CategoryUsecase
+func NewCategoryUsecase(repo repository.CategoryRepository) CategoryUsecase {
+	return &categoryUsecase{
+		repo: repo,
+	}
+}
+
+// CreateCategory creates a new category.
+// Without an explicit slug, one is derived from the name with a "-2", "-3", ... suffix if needed.
+func (u *categoryUsecase) CreateCategory(ctx context.Context, in CategoryInput) (db.Category, error) {
+	if err := normalizeCategoryInput(&in); err != nil {
+		return db.Category{}, err
+	}
+	if err := u.validateParent(ctx, 0, in.ParentID); err != nil {
+		return db.Category{}, err
+	}
+
+	if in.Slug != "" {
+		category, err := u.repo.Create(ctx, in.Name, Slugify(in.Slug), in.ParentID)
+		if errors.Is(err, repository.ErrDuplicateKey) {
+			return db.Category{}, ErrCategorySlugTaken
+		}
+		return category, err
+	}
+
+	base := Slugify(in.Name)
+	slug := base
+	for n := 2; ; n++ {
+		category, err := u.repo.Create(ctx, in.Name, slug, in.ParentID)
+		if !errors.Is(err, repository.ErrDuplicateKey) {
+			return category, err
+		}
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// GetCategory retrieves a category by ID
+func (u *categoryUsecase) GetCategory(ctx context.Context, id int64) (db.Category, error) {
+	category, err := u.repo.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.Category{}, ErrCategoryNotFound
+	}
+	return category, err
+}
+
+// ListCategories retrieves all categories
+func (u *categoryUsecase) ListCategories(ctx context.Context) ([]db.Category, error) {
+	return u.repo.List(ctx)
+}
+
+// UpdateCategory updates a category.
+// An empty slug keeps the current one.
+func (u *categoryUsecase) UpdateCategory(ctx context.Context, id int64, in CategoryInput) (db.Category, error) {
+	current, err := u.GetCategory(ctx, id)
+	if err != nil {
+		return db.Category{}, err
+	}
+	if err := normalizeCategoryInput(&in); err != nil {
+		return db.Category{}, err
+	}
+	if err := u.validateParent(ctx, id, in.ParentID); err != nil {
+		return db.Category{}, err
+	}
+
+	slug := current.Slug
+	if in.Slug != "" {
+		slug = Slugify(in.Slug)
+	}
+	category, err := u.repo.Update(ctx, id, in.Name, slug, in.ParentID)
+	if errors.Is(err, repository.ErrDuplicateKey) {
+		return db.Category{}, ErrCategorySlugTaken
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.Category{}, ErrCategoryNotFound
+	}
+	return category, err
+}
+
+// DeleteCategory deletes a category.
+// A category that still has subcategories or articles yields ErrCategoryInUse.
+func (u *categoryUsecase) DeleteCategory(ctx context.Context, id int64) error {
+	err := u.repo.Delete(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrCategoryNotFound
+	}
+	if errors.Is(err, repository.ErrStillReferenced) {
+		return ErrCategoryInUse
+	}
+	return err
+}
+
+// normalizeCategoryInput trims the name and slug and validates the name
+func normalizeCategoryInput(in *CategoryInput) error {
+	in.Name = strings.TrimSpace(in.Name)
+	in.Slug = strings.TrimSpace(in.Slug)
+	if in.Name == "" {
+		return invalidField("name", "is required")
+	}
+	if utf8.RuneCountInString(in.Name) > maxCategoryNameLength {
+		return invalidField("name", fmt.Sprintf("must be at most %d characters", maxCategoryNameLength))
+	}
+	return nil
+}
+
+// validateParent walks up from parentID to the root and rejects a parent that does not exist,
+// that is the category itself or one of its descendants (id is 0 for a new category),
+// or that would make the tree deeper than maxCategoryDepth.
+// The subtree below id is not walked, so its depth is not counted.
+func (u *categoryUsecase) validateParent(ctx context.Context, id int64, parentID *int64) error {
+	if parentID == nil {
+		return nil
+	}
+
+	next := parentID
+	for depth := 1; next != nil; depth++ {
+		if *next == id {
+			return invalidField("parent_id", "must not be the category itself or one of its descendants")
+		}
+		if depth >= maxCategoryDepth {
+			return invalidField("parent_id", fmt.Sprintf("must not nest categories more than %d levels deep", maxCategoryDepth))
+		}
+		ancestor, err := u.repo.GetByID(ctx, *next)
+		if errors.Is(err, sql.ErrNoRows) {
+			return invalidField("parent_id", "category not found")
+		}
+		if err != nil {
+			return err
+		}
+		next = ancestor.ParentID
+	}
+	return nil
+}
+
+// categoryPath returns the path from the root down to id in a map of all categories.
+// It stops early rather than looping if the stored tree is inconsistent.
+func categoryPath(categories map[int64]db.Category, id int64) []CategoryRef {
+	var path []CategoryRef
+	next := &id
+	for len(path) < maxCategoryDepth && next != nil {
+		category, ok := categories[*next]
+		if !ok {
+			break
+		}
+		path = append(path, CategoryRef{ID: category.ID, Name: category.Name, Slug: category.Slug})
+		next = category.ParentID
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// categoryDescendants returns id and the IDs of all categories below it
+func categoryDescendants(categories []db.Category, id int64) []int64 {
+	children := make(map[int64][]int64, len(categories))
+	for _, c := range categories {
+		if c.ParentID != nil {
+			children[*c.ParentID] = append(children[*c.ParentID], c.ID)
+		}
+	}
+
+	ids := []int64{id}
+	for i := 0; i < len(ids) && len(ids) <= len(categories); i++ {
+		ids = append(ids, children[ids[i]]...)
+	}
+	return ids
+}
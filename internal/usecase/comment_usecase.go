@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/para7/nanaket-cms/internal/db"
+	"github.com/para7/nanaket-cms/internal/repository"
+)
+
+// maxCommentAuthorNameLength matches comments.temp_user_name VARCHAR(255)
+const maxCommentAuthorNameLength = 255
+
+// CommentUsecase defines the interface for comment business logic
+type CommentUsecase interface {
+	CreateComment(ctx context.Context, in CommentInput) (db.Comment, error)
+	ListComments(ctx context.Context, articleID int64, limit int32, cursor int64) (CommentPage, error)
+}
+
+// CommentInput holds the writable fields of a comment
+type CommentInput struct {
+	ArticleID int64
+	// UserID is the authenticated commenter; nil for guest comments
+	UserID *int64
+	// AuthorName is required for guest comments and ignored otherwise
+	AuthorName string
+	Content    string
+}
+
+// CommentPage represents a single page of comments returned by cursor-based pagination
+type CommentPage struct {
+	Items []db.Comment
+	// NextCursor is the cursor for the following page (0 when there are no more rows)
+	NextCursor int64
+}
+
+// commentUsecase implements CommentUsecase interface
+type commentUsecase struct {
+	repo        repository.CommentRepository
+	articleRepo repository.ArticleRepository
+}
+
+// NewCommentUsecase creates a new instance of CommentUsecase
+func NewCommentUsecase(repo repository.CommentRepository, articleRepo repository.ArticleRepository) CommentUsecase {
+	return &commentUsecase{
+		repo:        repo,
+		articleRepo: articleRepo,
+	}
+}
+
+// CreateComment adds a comment to an existing article
+func (u *commentUsecase) CreateComment(ctx context.Context, in CommentInput) (db.Comment, error) {
+	v := &ValidationError{}
+	content := strings.TrimSpace(in.Content)
+	if content == "" {
+		v.Add("content", "is required")
+	}
+
+	// Exactly one of user_id and temp_user_name is set on a comment
+	var authorName *string
+	if in.UserID == nil {
+		name := strings.TrimSpace(in.AuthorName)
+		if name == "" {
+			v.Add("author_name", "is required")
+		} else if utf8.RuneCountInString(name) > maxCommentAuthorNameLength {
+			v.Add("author_name", "is too long")
+		}
+		authorName = &name
+	}
+	if err := v.Err(); err != nil {
+		return db.Comment{}, err
+	}
+
+	if err := u.ensureArticleExists(ctx, in.ArticleID); err != nil {
+		return db.Comment{}, err
+	}
+
+	return u.repo.Create(ctx, in.ArticleID, in.UserID, authorName, content)
+}
+
+// ListComments retrieves a page of comments on an article, newest first.
+// A cursor of 0 starts from the most recent comment.
+func (u *commentUsecase) ListComments(ctx context.Context, articleID int64, limit int32, cursor int64) (CommentPage, error) {
+	if err := u.ensureArticleExists(ctx, articleID); err != nil {
+		return CommentPage{}, err
+	}
+	if cursor <= 0 {
+		cursor = math.MaxInt64
+	}
+
+	// Fetch one extra row to find out whether another page exists
+	comments, err := u.repo.ListByArticle(ctx, articleID, limit+1, cursor)
+	if err != nil {
+		return CommentPage{}, err
+	}
+
+	var nextCursor int64
+	if len(comments) > int(limit) {
+		comments = comments[:limit]
+		nextCursor = comments[limit-1].ID
+	}
+	return CommentPage{Items: comments, NextCursor: nextCursor}, nil
+}
+
+// ensureArticleExists returns ErrArticleNotFound when no article has the given ID
+func (u *commentUsecase) ensureArticleExists(ctx context.Context, articleID int64) error {
+	_, err := u.articleRepo.GetByID(ctx, articleID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrArticleNotFound
+	}
+	return err
+}
@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrValidation is matched (via errors.Is) by every input validation error
+var ErrValidation = errors.New("validation error")
+
+// FieldError describes invalid input for a single field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError describes invalid input, with one FieldError per problem found.
+// Validators collect every problem with Add and return Err, so clients can fix all fields at once.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// invalidField returns a ValidationError for a single field
+func invalidField(field, message string) *ValidationError {
+	return &ValidationError{Fields: []FieldError{{Field: field, Message: message}}}
+}
+
+// Add records a problem with field
+func (e *ValidationError) Add(field, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: message})
+}
+
+// Err returns e if any problem was recorded and nil otherwise
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Field + ": " + f.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Is makes errors.Is(err, ErrValidation) report true for any ValidationError
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
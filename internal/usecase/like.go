@@ -0,0 +1,12 @@
+package usecase
+
+import "strings"
+
+// likeEscaper escapes LIKE wildcards so user input is matched literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike escapes s for use inside a LIKE/ILIKE pattern
+// (backslash is PostgreSQL's default LIKE escape character)
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
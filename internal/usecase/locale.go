@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"errors"
+	"slices"
+
+	"golang.org/x/text/language"
+)
+
+// DefaultLocale is the default language of articles: the language the title and content
+// of the article itself are written in, served when no translation matches
+const DefaultLocale = "ja"
+
+// maxLocaleLength matches article_translations.locale VARCHAR(35)
+const maxLocaleLength = 35
+
+// maxPreferredLocales bounds how many locales are looked up for one request
+const maxPreferredLocales = 10
+
+// ErrInvalidLocale is returned when a locale is not a valid BCP 47 language tag
+var ErrInvalidLocale = errors.New("invalid locale")
+
+// ParseLocale validates a BCP 47 language tag and returns it in canonical form
+// (e.g. "en-us" becomes "en-US"), which is how locales are stored
+func ParseLocale(s string) (string, error) {
+	if s == "" || len(s) > maxLocaleLength {
+		return "", ErrInvalidLocale
+	}
+	tag, err := language.Parse(s)
+	if err != nil {
+		return "", ErrInvalidLocale
+	}
+	return tag.String(), nil
+}
+
+// fallbackLocales expands the preferred locales, most preferred first, into the translations
+// to look for. Each locale is followed by its base language, so en-US falls back to en.
+// The list ends at defaultLocale: the article itself is written in it, so no less preferred
+// translation is wanted.
+func fallbackLocales(preferred []string, defaultLocale string) []string {
+	var result []string
+	for _, locale := range preferred {
+		candidates := []string{locale}
+		if tag, err := language.Parse(locale); err == nil {
+			if base, confidence := tag.Base(); confidence != language.No {
+				candidates = append(candidates, base.String())
+			}
+		}
+
+		for _, candidate := range candidates {
+			if candidate == defaultLocale {
+				return result
+			}
+			if !slices.Contains(result, candidate) {
+				result = append(result, candidate)
+			}
+			if len(result) == maxPreferredLocales {
+				return result
+			}
+		}
+	}
+	return result
+}
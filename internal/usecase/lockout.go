@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults for LoginLockout
+const (
+	DefaultLockoutThreshold = 5
+	DefaultLockoutCooldown  = 15 * time.Minute
+)
+
+// AccountLockedError is returned when logins for an identifier are locked after repeated failures
+type AccountLockedError struct {
+	// RetryAfter is the time until the lock is lifted
+	RetryAfter time.Duration
+}
+
+func (e *AccountLockedError) Error() string {
+	return fmt.Sprintf("account locked for %s after repeated failed logins", e.RetryAfter.Round(time.Second))
+}
+
+// LoginLockout locks logins for an identifier (such as an email) for a cooldown
+// once it has failed threshold times in a row. Each identifier is tracked separately,
+// so an attack on one account does not lock out others.
+// State is kept in process memory and is not shared between server instances.
+// It is safe for concurrent use.
+type LoginLockout struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	entries   map[string]*lockoutEntry
+	lastSweep time.Time
+}
+
+// lockoutEntry holds the consecutive failures of one identifier
+type lockoutEntry struct {
+	failures int
+	// lockedUntil is set once failures reaches the threshold
+	lockedUntil time.Time
+	lastFailure time.Time
+}
+
+// expired reports whether the entry no longer affects logins at now:
+// any lock has been lifted, or the last failure is a cooldown ago
+func (e *lockoutEntry) expired(now time.Time, cooldown time.Duration) bool {
+	if !e.lockedUntil.IsZero() {
+		return !now.Before(e.lockedUntil)
+	}
+	return now.Sub(e.lastFailure) >= cooldown
+}
+
+// NewLoginLockout creates a LoginLockout that locks an identifier for cooldown after threshold consecutive failures
+func NewLoginLockout(threshold int, cooldown time.Duration) *LoginLockout {
+	return &LoginLockout{
+		threshold: threshold,
+		cooldown:  cooldown,
+		entries:   make(map[string]*lockoutEntry),
+	}
+}
+
+// Check returns an *AccountLockedError if logins for id are locked at now
+func (l *LoginLockout) Check(id string, now time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e, ok := l.entries[lockoutKey(id)]
+	if !ok || !now.Before(e.lockedUntil) {
+		return nil
+	}
+	return &AccountLockedError{RetryAfter: e.lockedUntil.Sub(now)}
+}
+
+// Fail records a failed login for id at now, locking it once the threshold is reached.
+// Failures after an expired lock, or a cooldown after the previous failure, start counting again from zero.
+func (l *LoginLockout) Fail(id string, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Drop identifiers that have gone quiet so the map does not grow without bound
+	if now.Sub(l.lastSweep) > l.cooldown {
+		for k, e := range l.entries {
+			if e.expired(now, l.cooldown) {
+				delete(l.entries, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	key := lockoutKey(id)
+	e, ok := l.entries[key]
+	if !ok || e.expired(now, l.cooldown) {
+		e = &lockoutEntry{}
+		l.entries[key] = e
+	}
+	e.failures++
+	e.lastFailure = now
+	if e.failures >= l.threshold {
+		e.lockedUntil = now.Add(l.cooldown)
+	}
+}
+
+// Reset clears the failures of id after a successful login
+func (l *LoginLockout) Reset(id string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.entries, lockoutKey(id))
+}
+
+// lockoutKey normalizes an identifier so variants of the same email share a counter
+func lockoutKey(id string) string {
+	return strings.ToLower(strings.TrimSpace(id))
+}
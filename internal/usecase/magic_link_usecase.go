@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/para7/nanaket-cms/internal/db"
+	"github.com/para7/nanaket-cms/internal/mail"
+	"github.com/para7/nanaket-cms/internal/repository"
+)
+
+// DefaultMagicLinkTTL is the default lifetime of magic login links
+const DefaultMagicLinkTTL = 15 * time.Minute
+
+// MagicLinkUsecase defines the interface for passwordless login by emailed link,
+// and for confirming a new email address by a link sent to it
+type MagicLinkUsecase interface {
+	RequestLink(ctx context.Context, email string) error
+	Verify(ctx context.Context, token string) (IssuedSession, error)
+	RequestEmailChange(ctx context.Context, userID int64, email string) error
+	ConfirmEmailChange(ctx context.Context, token string) (db.User, error)
+	DeleteExpiredLinks(ctx context.Context) (int64, error)
+}
+
+// magicLinkUsecase implements MagicLinkUsecase interface
+type magicLinkUsecase struct {
+	linkRepo repository.LoginLinkRepository
+	authRepo repository.AuthRepository
+	mailer   mail.Mailer
+	// verifyURL is the verify endpoint; the token is added as the "token" query parameter
+	verifyURL string
+	// confirmEmailURL is the endpoint confirming email changes, used like verifyURL
+	confirmEmailURL string
+	ttl             time.Duration
+}
+
+// NewMagicLinkUsecase creates a new instance of MagicLinkUsecase.
+// Login links point at verifyURL, email change links at confirmEmailURL, and both stay valid for ttl.
+func NewMagicLinkUsecase(linkRepo repository.LoginLinkRepository, authRepo repository.AuthRepository, mailer mail.Mailer, verifyURL, confirmEmailURL string, ttl time.Duration) MagicLinkUsecase {
+	return &magicLinkUsecase{
+		linkRepo:        linkRepo,
+		authRepo:        authRepo,
+		mailer:          mailer,
+		verifyURL:       verifyURL,
+		confirmEmailURL: confirmEmailURL,
+		ttl:             ttl,
+	}
+}
+
+// RequestLink emails a single-use login link to the user with the given email.
+// Unknown emails and deleted users are ignored without an error, so callers cannot
+// tell which emails are registered.
+func (u *magicLinkUsecase) RequestLink(ctx context.Context, email string) error {
+	user, err := u.authRepo.GetUserByEmail(ctx, strings.TrimSpace(email))
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && user.DeletedAt.Valid) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	secret, err := generateToken()
+	if err != nil {
+		return err
+	}
+	err = u.linkRepo.Create(ctx, user.ID, hashToken(secret), nil, pgtype.Timestamp{
+		Time:  time.Now().Add(u.ttl),
+		Valid: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	link := u.verifyURL + "?token=" + url.QueryEscape(secret)
+	return u.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Your Nanaket CMS login link",
+		Body: fmt.Sprintf("Open this link to log in. It works once and expires in %d minutes.\n\n%s\n\n"+
+			"If you did not request it, you can ignore this email.\n", int(u.ttl.Minutes()), link),
+	})
+}
+
+// Verify consumes a login link and issues a stored token to its user.
+// It returns ErrInvalidToken if the link is unknown, expired or already used, or its user was deleted.
+func (u *magicLinkUsecase) Verify(ctx context.Context, token string) (IssuedSession, error) {
+	userID, err := u.linkRepo.Consume(ctx, hashToken(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return IssuedSession{}, ErrInvalidToken
+	}
+	if err != nil {
+		return IssuedSession{}, err
+	}
+
+	user, err := u.authRepo.GetUserByID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return IssuedSession{}, ErrInvalidToken
+	}
+	if err != nil {
+		return IssuedSession{}, err
+	}
+
+	secret, err := generateToken()
+	if err != nil {
+		return IssuedSession{}, err
+	}
+	accessToken, err := u.authRepo.CreateToken(ctx, user.ID, hashToken(secret), pgtype.Timestamp{
+		Time:  time.Now().Add(loginTokenTTL),
+		Valid: true,
+	})
+	if err != nil {
+		return IssuedSession{}, err
+	}
+	return IssuedSession{User: user, Token: IssuedToken{Token: accessToken, Secret: secret}}, nil
+}
+
+// RequestEmailChange emails a single-use link to email that makes it the email of user userID.
+// Until the link is opened the user keeps the old email, so an address nobody has confirmed
+// can never be used to log in. The caller checks that email is valid and free.
+func (u *magicLinkUsecase) RequestEmailChange(ctx context.Context, userID int64, email string) error {
+	secret, err := generateToken()
+	if err != nil {
+		return err
+	}
+	err = u.linkRepo.Create(ctx, userID, hashToken(secret), &email, pgtype.Timestamp{
+		Time:  time.Now().Add(u.ttl),
+		Valid: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	link := u.confirmEmailURL + "?token=" + url.QueryEscape(secret)
+	return u.mailer.Send(ctx, mail.Message{
+		To:      email,
+		Subject: "Confirm your new Nanaket CMS email address",
+		Body: fmt.Sprintf("Open this link to use this address for your Nanaket CMS account. It works once and expires in %d minutes.\n\n%s\n\n"+
+			"If you did not ask for this change, you can ignore this email.\n", int(u.ttl.Minutes()), link),
+	})
+}
+
+// ConfirmEmailChange consumes an email change link and sets the new email on its user.
+// It returns ErrInvalidToken if the link is unknown, expired or already used, or its user
+// was deleted, and ErrEmailTaken if another user has taken the address in the meantime.
+func (u *magicLinkUsecase) ConfirmEmailChange(ctx context.Context, token string) (db.User, error) {
+	userID, email, err := u.linkRepo.ConsumeEmailChange(ctx, hashToken(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.User{}, ErrInvalidToken
+	}
+	if err != nil {
+		return db.User{}, err
+	}
+
+	user, err := u.authRepo.SetEmail(ctx, userID, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.User{}, ErrInvalidToken
+	}
+	if errors.Is(err, repository.ErrDuplicateKey) {
+		return db.User{}, ErrEmailTaken
+	}
+	return user, err
+}
+
+// DeleteExpiredLinks removes expired login and email change links and returns how many were deleted
+func (u *magicLinkUsecase) DeleteExpiredLinks(ctx context.Context) (int64, error) {
+	return u.linkRepo.DeleteExpired(ctx)
+}
@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/para7/nanaket-cms/internal/db"
+	"github.com/para7/nanaket-cms/internal/db/mock"
+	"github.com/para7/nanaket-cms/internal/mail"
+	"github.com/para7/nanaket-cms/internal/repository"
+)
+
+const (
+	testVerifyURL       = "https://cms.example.com/login/verify"
+	testConfirmEmailURL = "https://cms.example.com/email/verify"
+)
+
+// outbox is a mail.Mailer that keeps the messages it is asked to send
+type outbox struct {
+	sent []mail.Message
+}
+
+func (o *outbox) Send(ctx context.Context, msg mail.Message) error {
+	o.sent = append(o.sent, msg)
+	return nil
+}
+
+// linkToken returns the token of the link to base in the body of msg
+func linkToken(t *testing.T, msg mail.Message, base string) string {
+	t.Helper()
+	for _, field := range strings.Fields(msg.Body) {
+		if !strings.HasPrefix(field, base+"?") {
+			continue
+		}
+		link, err := url.Parse(field)
+		if err != nil {
+			t.Fatalf("parse link %q: %v", field, err)
+		}
+		return link.Query().Get("token")
+	}
+	t.Fatalf("no link to %s in %q", base, msg.Body)
+	return ""
+}
+
+// linkTable returns a mock.LoginLinkRepository that keeps links by token hash.
+// Consuming deletes the link and tells login links from email change links by
+// their email, as the login_links queries do.
+func linkTable() *mock.LoginLinkRepository {
+	type link struct {
+		userID int64
+		email  *string
+	}
+	links := map[string]link{}
+	return &mock.LoginLinkRepository{
+		CreateFunc: func(ctx context.Context, userID int64, tokenHash string, email *string, expiresAt pgtype.Timestamp) error {
+			links[tokenHash] = link{userID: userID, email: email}
+			return nil
+		},
+		ConsumeFunc: func(ctx context.Context, tokenHash string) (int64, error) {
+			l, ok := links[tokenHash]
+			if !ok || l.email != nil {
+				return 0, sql.ErrNoRows
+			}
+			delete(links, tokenHash)
+			return l.userID, nil
+		},
+		ConsumeEmailChangeFunc: func(ctx context.Context, tokenHash string) (int64, string, error) {
+			l, ok := links[tokenHash]
+			if !ok || l.email == nil {
+				return 0, "", sql.ErrNoRows
+			}
+			delete(links, tokenHash)
+			return l.userID, *l.email, nil
+		},
+	}
+}
+
+// authRepoWithUser returns a mock.AuthRepository that knows user and issues tokens to them
+func authRepoWithUser(user *db.User) *mock.AuthRepository {
+	return &mock.AuthRepository{
+		GetUserByEmailFunc: func(ctx context.Context, email string) (db.User, error) {
+			if email != user.Email {
+				return db.User{}, sql.ErrNoRows
+			}
+			return *user, nil
+		},
+		GetUserByIDFunc: func(ctx context.Context, id int64) (db.User, error) {
+			if id != user.ID {
+				return db.User{}, sql.ErrNoRows
+			}
+			return *user, nil
+		},
+		CreateTokenFunc: func(ctx context.Context, userID int64, tokenHash string, expiresAt pgtype.Timestamp) (db.AccessToken, error) {
+			return db.AccessToken{ID: 9, UserID: userID, Token: tokenHash, ExpiresAt: expiresAt}, nil
+		},
+		SetEmailFunc: func(ctx context.Context, userID int64, email string) (db.User, error) {
+			if userID != user.ID {
+				return db.User{}, sql.ErrNoRows
+			}
+			user.Email = email
+			return *user, nil
+		},
+	}
+}
+
+func TestMagicLinkLogin(t *testing.T) {
+	user := db.User{ID: 1, Email: "user@example.com", Role: UserRoleEditor}
+	mailer := &outbox{}
+	u := NewMagicLinkUsecase(linkTable(), authRepoWithUser(&user), mailer, testVerifyURL, testConfirmEmailURL, 15*time.Minute)
+
+	// Unknown emails get no link and no error
+	if err := u.RequestLink(context.Background(), "nobody@example.com"); err != nil {
+		t.Fatalf("RequestLink(unknown): %v", err)
+	}
+	if len(mailer.sent) != 0 {
+		t.Fatalf("sent %d messages for an unknown email, want 0", len(mailer.sent))
+	}
+
+	if err := u.RequestLink(context.Background(), " user@example.com "); err != nil {
+		t.Fatalf("RequestLink: %v", err)
+	}
+	if len(mailer.sent) != 1 || mailer.sent[0].To != user.Email {
+		t.Fatalf("sent %+v, want one message to %s", mailer.sent, user.Email)
+	}
+	token := linkToken(t, mailer.sent[0], testVerifyURL)
+
+	session, err := u.Verify(context.Background(), token)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if session.User.ID != user.ID || session.Token.Secret == "" {
+		t.Errorf("session = %+v", session)
+	}
+
+	// The link works once
+	if _, err := u.Verify(context.Background(), token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("second Verify: got %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestEmailChange(t *testing.T) {
+	user := db.User{ID: 1, Email: "user@example.com", Name: "User", Role: UserRoleEditor}
+	mailer := &outbox{}
+	links := NewMagicLinkUsecase(linkTable(), authRepoWithUser(&user), mailer, testVerifyURL, testConfirmEmailURL, 15*time.Minute)
+
+	var updatedEmail string
+	users := NewUserUsecase(&mock.UserRepository{
+		GetByIDFunc: func(ctx context.Context, id int64) (db.User, error) {
+			return user, nil
+		},
+		GetByEmailFunc: func(ctx context.Context, email string) (db.User, error) {
+			return db.User{}, sql.ErrNoRows
+		},
+		UpdateFunc: func(ctx context.Context, id int64, email, name string) (db.User, error) {
+			updatedEmail = email
+			user.Name = name
+			return user, nil
+		},
+	}, links)
+
+	updated, pending, err := users.UpdateUser(context.Background(), user.ID, "new@example.com", "User")
+	if err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+	if pending != "new@example.com" {
+		t.Errorf("pending email = %q, want new@example.com", pending)
+	}
+	// The old address stays until the new one is confirmed
+	if updatedEmail != "user@example.com" || updated.Email != "user@example.com" {
+		t.Errorf("updated email to %q, returned %q, want the old address", updatedEmail, updated.Email)
+	}
+	if len(mailer.sent) != 1 || mailer.sent[0].To != "new@example.com" {
+		t.Fatalf("sent %+v, want one message to new@example.com", mailer.sent)
+	}
+	token := linkToken(t, mailer.sent[0], testConfirmEmailURL)
+
+	// An email change link does not log in
+	if _, err := links.Verify(context.Background(), token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Verify with an email change link: got %v, want ErrInvalidToken", err)
+	}
+
+	confirmed, err := links.ConfirmEmailChange(context.Background(), token)
+	if err != nil {
+		t.Fatalf("ConfirmEmailChange: %v", err)
+	}
+	if confirmed.Email != "new@example.com" {
+		t.Errorf("email = %q, want new@example.com", confirmed.Email)
+	}
+
+	if _, err := links.ConfirmEmailChange(context.Background(), token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("second ConfirmEmailChange: got %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestConfirmEmailChangeTaken(t *testing.T) {
+	user := db.User{ID: 1, Email: "user@example.com"}
+	repo := authRepoWithUser(&user)
+	// Another user took the address after the link was sent
+	repo.SetEmailFunc = func(ctx context.Context, userID int64, email string) (db.User, error) {
+		return db.User{}, repository.ErrDuplicateKey
+	}
+	mailer := &outbox{}
+	u := NewMagicLinkUsecase(linkTable(), repo, mailer, testVerifyURL, testConfirmEmailURL, 15*time.Minute)
+
+	if err := u.RequestEmailChange(context.Background(), user.ID, "taken@example.com"); err != nil {
+		t.Fatalf("RequestEmailChange: %v", err)
+	}
+	token := linkToken(t, mailer.sent[0], testConfirmEmailURL)
+
+	if _, err := u.ConfirmEmailChange(context.Background(), token); !errors.Is(err, ErrEmailTaken) {
+		t.Errorf("got %v, want ErrEmailTaken", err)
+	}
+}
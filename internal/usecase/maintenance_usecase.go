@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/para7/nanaket-cms/internal/repository"
+)
+
+// Maintenance modes
+const (
+	// MaintenanceOff serves every request
+	MaintenanceOff = "off"
+	// MaintenanceReadOnly serves reads and rejects writes
+	MaintenanceReadOnly = "read_only"
+	// MaintenanceDown rejects every request except health checks
+	MaintenanceDown = "down"
+)
+
+// DefaultMaintenanceCacheTTL is how long the maintenance mode read from the database is reused
+const DefaultMaintenanceCacheTTL = 5 * time.Second
+
+// maintenanceModeKey is the settings key holding the maintenance mode
+const maintenanceModeKey = "maintenance_mode"
+
+// MaintenanceUsecase defines the interface for switching the maintenance mode
+type MaintenanceUsecase interface {
+	// Mode returns the stored mode, read from the database
+	Mode(ctx context.Context) (string, error)
+	// CachedMode returns the mode, reusing a value read within the cache TTL.
+	// It never fails: when the database cannot be read, the last known mode is kept.
+	CachedMode(ctx context.Context) string
+	SetMode(ctx context.Context, mode string) (string, error)
+}
+
+// maintenanceUsecase implements MaintenanceUsecase interface.
+// The mode is stored in the settings table so every server instance sees the same value;
+// each instance caches it for ttl, so a change takes up to ttl to reach the others.
+type maintenanceUsecase struct {
+	repo repository.SettingRepository
+	ttl  time.Duration
+
+	mu        sync.Mutex
+	mode      string
+	fetchedAt time.Time
+}
+
+// NewMaintenanceUsecase creates a new instance of MaintenanceUsecase that caches the mode for ttl
+func NewMaintenanceUsecase(repo repository.SettingRepository, ttl time.Duration) MaintenanceUsecase {
+	return &maintenanceUsecase{
+		repo: repo,
+		ttl:  ttl,
+		mode: MaintenanceOff,
+	}
+}
+
+// Mode returns the stored mode; a mode that was never set is off
+func (u *maintenanceUsecase) Mode(ctx context.Context) (string, error) {
+	mode, err := u.repo.Get(ctx, maintenanceModeKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return MaintenanceOff, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return mode, nil
+}
+
+// CachedMode returns the mode, reading it again once the cached value is older than the TTL.
+// A failed read keeps the last known mode (off at startup) until the next TTL passes,
+// so a database outage neither takes the site down nor floods the database with reads.
+func (u *maintenanceUsecase) CachedMode(ctx context.Context) string {
+	now := time.Now()
+	u.mu.Lock()
+	if now.Sub(u.fetchedAt) < u.ttl {
+		mode := u.mode
+		u.mu.Unlock()
+		return mode
+	}
+	// Claim the refresh so concurrent requests keep using the current value meanwhile
+	u.fetchedAt = now
+	mode := u.mode
+	u.mu.Unlock()
+
+	fetched, err := u.Mode(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to read maintenance mode, keeping the last known mode", "mode", mode, "error", err)
+		return mode
+	}
+	u.store(fetched, now)
+	return fetched
+}
+
+// SetMode stores mode and returns it. The cache of this instance is updated at once.
+func (u *maintenanceUsecase) SetMode(ctx context.Context, mode string) (string, error) {
+	switch mode {
+	case MaintenanceOff, MaintenanceReadOnly, MaintenanceDown:
+	default:
+		return "", invalidField("mode", "must be one of off, read_only, down")
+	}
+	if err := u.repo.Set(ctx, maintenanceModeKey, mode); err != nil {
+		return "", err
+	}
+	u.store(mode, time.Now())
+	return mode, nil
+}
+
+// store caches mode as read at fetchedAt, unless a newer value has been cached meanwhile
+func (u *maintenanceUsecase) store(mode string, fetchedAt time.Time) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if fetchedAt.Before(u.fetchedAt) {
+		return
+	}
+	u.mode = mode
+	u.fetchedAt = fetchedAt
+}
@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/para7/nanaket-cms/internal/db"
+	"github.com/para7/nanaket-cms/internal/repository"
+	"github.com/para7/nanaket-cms/internal/storage"
+)
+
+// DefaultMaxMediaBytes is the upload size limit used when none is configured (10MB)
+const DefaultMaxMediaBytes int64 = 10 << 20
+
+// maxMediaFilenameLength matches media_files.filename VARCHAR(255)
+const maxMediaFilenameLength = 255
+
+var (
+	// ErrUnsupportedMediaType is returned when an upload is not an allowed image type
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	// ErrMediaTooLarge is returned when an upload exceeds the size limit
+	ErrMediaTooLarge = errors.New("media too large")
+	// ErrMediaNotFound is returned when the referenced media file does not exist
+	ErrMediaNotFound = errors.New("media not found")
+	// ErrMediaInUse is returned when deleting a media file that an article still uses
+	ErrMediaInUse = errors.New("media in use")
+)
+
+// mediaExtensions maps each allowed content type to the extension of its object key
+var mediaExtensions = map[string]string{
+	"image/gif":  ".gif",
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+// MediaUsecase defines the interface for media business logic
+type MediaUsecase interface {
+	UploadMedia(ctx context.Context, in MediaUpload) (Media, error)
+	ListMedia(ctx context.Context, limit int32, cursor int64) (MediaPage, error)
+	DeleteMedia(ctx context.Context, id int64) error
+}
+
+// Media is a media file together with its public URL, as returned to clients
+type Media struct {
+	db.MediaFile
+	URL string `json:"url"`
+}
+
+// MediaUpload holds an uploaded file
+type MediaUpload struct {
+	// UserID is the uploader; nil if unknown
+	UserID   *int64
+	Filename string
+	Content  io.Reader
+}
+
+// MediaPage represents a single page of media files returned by cursor-based pagination
+type MediaPage struct {
+	Items []Media
+	// NextCursor is the cursor for the following page (0 when there are no more rows)
+	NextCursor int64
+}
+
+// mediaUsecase implements MediaUsecase interface
+type mediaUsecase struct {
+	repo     repository.MediaRepository
+	store    storage.ObjectStore
+	maxBytes int64
+}
+
+// NewMediaUsecase creates a new instance of MediaUsecase.
+// Uploads larger than maxBytes are rejected.
+func NewMediaUsecase(repo repository.MediaRepository, store storage.ObjectStore, maxBytes int64) MediaUsecase {
+	return &mediaUsecase{
+		repo:     repo,
+		store:    store,
+		maxBytes: maxBytes,
+	}
+}
+
+// UploadMedia stores an image and records it.
+// The content type is sniffed from the content rather than trusted from the client.
+func (u *mediaUsecase) UploadMedia(ctx context.Context, in MediaUpload) (Media, error) {
+	data, err := io.ReadAll(io.LimitReader(in.Content, u.maxBytes+1))
+	if err != nil {
+		return Media{}, err
+	}
+	if int64(len(data)) > u.maxBytes {
+		return Media{}, ErrMediaTooLarge
+	}
+	if len(data) == 0 {
+		return Media{}, invalidField("file", "is empty")
+	}
+
+	contentType := http.DetectContentType(data)
+	ext, ok := mediaExtensions[contentType]
+	if !ok {
+		return Media{}, ErrUnsupportedMediaType
+	}
+
+	key, err := generateObjectKey(ext)
+	if err != nil {
+		return Media{}, err
+	}
+	if err := u.store.Put(ctx, key, contentType, bytes.NewReader(data)); err != nil {
+		return Media{}, err
+	}
+
+	media, err := u.repo.Create(ctx, in.UserID, cleanFilename(in.Filename), key, contentType, int64(len(data)))
+	if err != nil {
+		// Do not leave an unreferenced object behind
+		if delErr := u.store.Delete(ctx, key); delErr != nil {
+			slog.WarnContext(ctx, "Failed to remove orphaned media object", "key", key, "error", delErr)
+		}
+		return Media{}, err
+	}
+	return u.withURL(media), nil
+}
+
+// ListMedia retrieves a page of media files, newest first.
+// A cursor of 0 starts from the most recent upload.
+func (u *mediaUsecase) ListMedia(ctx context.Context, limit int32, cursor int64) (MediaPage, error) {
+	if cursor <= 0 {
+		cursor = math.MaxInt64
+	}
+
+	// Fetch one extra row to find out whether another page exists
+	files, err := u.repo.List(ctx, limit+1, cursor)
+	if err != nil {
+		return MediaPage{}, err
+	}
+
+	var nextCursor int64
+	if len(files) > int(limit) {
+		files = files[:limit]
+		nextCursor = files[limit-1].ID
+	}
+
+	items := make([]Media, len(files))
+	for i, f := range files {
+		items[i] = u.withURL(f)
+	}
+	return MediaPage{Items: items, NextCursor: nextCursor}, nil
+}
+
+// DeleteMedia removes a media file's record and its stored object.
+// The record goes first so a failure never leaves a record pointing at a missing object.
+// Media used as an article's featured image (including soft-deleted articles) cannot be
+// deleted and yields ErrMediaInUse.
+func (u *mediaUsecase) DeleteMedia(ctx context.Context, id int64) error {
+	media, err := u.repo.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrMediaNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := u.repo.Delete(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrMediaNotFound
+		}
+		if errors.Is(err, repository.ErrStillReferenced) {
+			return ErrMediaInUse
+		}
+		return err
+	}
+
+	if err := u.store.Delete(ctx, media.ObjectKey); err != nil {
+		slog.WarnContext(ctx, "Failed to remove media object", "key", media.ObjectKey, "error", err)
+	}
+	return nil
+}
+
+// withURL attaches the public URL of a media file
+func (u *mediaUsecase) withURL(media db.MediaFile) Media {
+	return Media{MediaFile: media, URL: u.store.URL(media.ObjectKey)}
+}
+
+// generateObjectKey returns a random object key with the given extension
+func generateObjectKey(ext string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b) + ext, nil
+}
+
+// cleanFilename strips any client-side directory from an uploaded filename and bounds its length
+func cleanFilename(name string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, `\`, "/")))
+	if name == "." || name == "/" {
+		return ""
+	}
+	for utf8.RuneCountInString(name) > maxMediaFilenameLength {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// minPasswordLength is the minimum password length in characters
+	minPasswordLength = 8
+	// maxPasswordBytes is the longest password bcrypt accepts; longer ones would be silently truncated
+	maxPasswordBytes = 72
+)
+
+// ErrInvalidCredentials is returned when an email and password do not match a live user
+var ErrInvalidCredentials = errors.New("invalid email or password")
+
+// dummyPasswordHash is compared against when no user matches an email, so that a login
+// for an unknown email takes as long as one with a wrong password
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+	return hash
+})
+
+// validatePassword checks the length of a new password
+func validatePassword(field, password string) error {
+	if utf8.RuneCountInString(password) < minPasswordLength {
+		return invalidField(field, fmt.Sprintf("must be at least %d characters", minPasswordLength))
+	}
+	if len(password) > maxPasswordBytes {
+		return invalidField(field, fmt.Sprintf("must be at most %d bytes", maxPasswordBytes))
+	}
+	return nil
+}
+
+// hashPassword returns the bcrypt hash of password
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	return string(hash), err
+}
+
+// checkPassword reports whether password matches hash; a nil hash (no password set) never matches
+func checkPassword(hash *string, password string) bool {
+	if hash == nil {
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(password))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(*hash), []byte(password)) == nil
+}
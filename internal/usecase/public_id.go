@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"strings"
+	"time"
+)
+
+// crockford is the Crockford base32 alphabet ULIDs are written in
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulidLength is the length of a ULID in characters
+const ulidLength = 26
+
+// newPublicID returns a new ULID: a 48-bit millisecond timestamp followed by 80 random bits,
+// so public IDs sort by creation time but reveal nothing about how many articles exist.
+// It is generated here rather than in the database so no database extension is needed.
+func newPublicID(now time.Time) (string, error) {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], uint64(now.UnixMilli())<<16)
+	if _, err := rand.Read(b[6:]); err != nil {
+		return "", err
+	}
+
+	// 26 characters of 5 bits hold the 128 bits, with the top 2 bits of the first character zero
+	hi, lo := binary.BigEndian.Uint64(b[:8]), binary.BigEndian.Uint64(b[8:])
+	var out [ulidLength]byte
+	for i := ulidLength - 1; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:]), nil
+}
+
+// IsPublicID reports whether s has the form of a ULID, ignoring case
+func IsPublicID(s string) bool {
+	if len(s) != ulidLength {
+		return false
+	}
+	s = strings.ToUpper(s)
+	// Larger first characters would overflow 128 bits
+	if s[0] > '7' {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(crockford, s[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
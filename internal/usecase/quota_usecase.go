@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/para7/nanaket-cms/internal/repository"
+)
+
+// DefaultUserWriteQuota is the number of write requests a user may make per day when none is configured
+const DefaultUserWriteQuota = 1000
+
+// QuotaStatus is the state of a user's daily quota after a request
+type QuotaStatus struct {
+	// Allowed is false when the quota was already used up and the request was not counted
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is when the quota is renewed: the next midnight UTC
+	Reset time.Time
+}
+
+// QuotaUsecase defines the interface for per-user daily request quotas
+type QuotaUsecase interface {
+	Consume(ctx context.Context, userID int64, now time.Time) (QuotaStatus, error)
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// quotaUsecase implements QuotaUsecase interface.
+// Counts are stored per user and UTC day in the database, so the quota is shared by
+// every server instance and renewed at midnight UTC without any reset job.
+type quotaUsecase struct {
+	repo  repository.QuotaRepository
+	limit int
+}
+
+// NewQuotaUsecase creates a new instance of QuotaUsecase that allows limit requests per user and day
+func NewQuotaUsecase(repo repository.QuotaRepository, limit int) QuotaUsecase {
+	return &quotaUsecase{
+		repo:  repo,
+		limit: limit,
+	}
+}
+
+// Consume counts a request of the user at now against the quota of that UTC day
+func (u *quotaUsecase) Consume(ctx context.Context, userID int64, now time.Time) (QuotaStatus, error) {
+	day := quotaDay(now)
+	used, ok, err := u.repo.Consume(ctx, userID, day, int32(u.limit))
+	if err != nil {
+		return QuotaStatus{}, err
+	}
+	return QuotaStatus{
+		Allowed:   ok,
+		Limit:     u.limit,
+		Remaining: max(u.limit-int(used), 0),
+		Reset:     day.AddDate(0, 0, 1),
+	}, nil
+}
+
+// DeleteExpired removes the counts of days before the UTC day of now and returns how many were removed
+func (u *quotaUsecase) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	return u.repo.DeleteBefore(ctx, quotaDay(now))
+}
+
+// quotaDay returns the start of the UTC day of t
+func quotaDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
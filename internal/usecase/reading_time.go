@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"unicode"
+)
+
+// Reading speeds used to estimate reading times
+const (
+	// ReadingWordsPerMinute is the reading speed for space-separated text such as English
+	ReadingWordsPerMinute = 200
+	// ReadingCJKCharsPerMinute is the reading speed for Chinese and Japanese text, counted in characters
+	ReadingCJKCharsPerMinute = 500
+)
+
+// CountWords counts the words in s. Chinese and Japanese are written without spaces
+// between words, so each Han, Hiragana or Katakana character is counted on its own
+// (as word processors do) and returned separately in cjkChars; words holds the rest.
+// Any other run of characters containing a letter or digit and not broken by
+// whitespace counts as one word, so Markdown markup such as "#" or "-" is not counted.
+func CountWords(s string) (words, cjkChars int) {
+	inWord := false
+	for _, r := range s {
+		switch {
+		case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana):
+			cjkChars++
+			inWord = false
+		case unicode.IsSpace(r):
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				words++
+				inWord = true
+			}
+		}
+	}
+	return words, cjkChars
+}
+
+// ReadingTimeMinutes estimates the minutes needed to read words words and cjkChars
+// Chinese or Japanese characters, rounded up. It is 0 only for text without words.
+func ReadingTimeMinutes(words, cjkChars int) int {
+	// Work in characters per minute of CJK reading to round only once
+	total := words*ReadingCJKCharsPerMinute + cjkChars*ReadingWordsPerMinute
+	perMinute := ReadingWordsPerMinute * ReadingCJKCharsPerMinute
+	return (total + perMinute - 1) / perMinute
+}
+
+// AddReadingTimes fills in WordCount and ReadingTimeMinutes of articles from their content.
+// Call it after LocalizeArticles so the figures match the content returned.
+func AddReadingTimes(articles []Article) {
+	for i := range articles {
+		words, cjkChars := CountWords(articles[i].Content)
+		count := words + cjkChars
+		minutes := ReadingTimeMinutes(words, cjkChars)
+		articles[i].WordCount = &count
+		articles[i].ReadingTimeMinutes = &minutes
+	}
+}
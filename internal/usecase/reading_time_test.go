@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/para7/nanaket-cms/internal/db"
+)
+
+func TestCountWords(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		words    int
+		cjkChars int
+	}{
+		{"empty", "", 0, 0},
+		{"english", "The quick brown fox jumps over the lazy dog.", 9, 0},
+		{"markdown", "# Title\n\n- first item\n- second item", 5, 0},
+		{"japanese", "吾輩は猫である。名前はまだ無い。", 0, 14},
+		{"mixed", "Goで書いたCMSです", 2, 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			words, cjkChars := CountWords(tt.s)
+			if words != tt.words || cjkChars != tt.cjkChars {
+				t.Errorf("CountWords(%q) = %d, %d, want %d, %d", tt.s, words, cjkChars, tt.words, tt.cjkChars)
+			}
+		})
+	}
+}
+
+func TestReadingTimeMinutes(t *testing.T) {
+	tests := []struct {
+		name     string
+		words    int
+		cjkChars int
+		want     int
+	}{
+		{"no words", 0, 0, 0},
+		{"one word", 1, 0, 1},
+		{"one minute of english", ReadingWordsPerMinute, 0, 1},
+		{"just over a minute of english", ReadingWordsPerMinute + 1, 0, 2},
+		{"one minute of japanese", 0, ReadingCJKCharsPerMinute, 1},
+		{"half a minute of each", ReadingWordsPerMinute / 2, ReadingCJKCharsPerMinute / 2, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ReadingTimeMinutes(tt.words, tt.cjkChars); got != tt.want {
+				t.Errorf("ReadingTimeMinutes(%d, %d) = %d, want %d", tt.words, tt.cjkChars, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddReadingTimes(t *testing.T) {
+	articles := []Article{
+		{Article: db.Article{Content: strings.Repeat("word ", 450)}},
+		{Article: db.Article{Content: strings.Repeat("猫", 1200)}},
+	}
+	AddReadingTimes(articles)
+
+	want := []struct{ words, minutes int }{
+		{450, 3},
+		{1200, 3},
+	}
+	for i, w := range want {
+		a := articles[i]
+		if a.WordCount == nil || *a.WordCount != w.words {
+			t.Errorf("article %d: word_count = %v, want %d", i, a.WordCount, w.words)
+		}
+		if a.ReadingTimeMinutes == nil || *a.ReadingTimeMinutes != w.minutes {
+			t.Errorf("article %d: reading_time_minutes = %v, want %d", i, a.ReadingTimeMinutes, w.minutes)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"strings"
+)
+
+// defaultSlug is used when a title contains no ASCII alphanumerics (e.g. Japanese titles)
+const defaultSlug = "article"
+
+// Slugify converts a title into a URL-friendly slug.
+// It lowercases the title, replaces every run of non-alphanumeric characters
+// with a single hyphen and trims hyphens from both ends.
+func Slugify(title string) string {
+	var b strings.Builder
+	pendingHyphen := false
+	for _, r := range strings.ToLower(title) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if pendingHyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingHyphen = false
+			b.WriteRune(r)
+			continue
+		}
+		pendingHyphen = true
+	}
+
+	if b.Len() == 0 {
+		return defaultSlug
+	}
+	return b.String()
+}
@@ -0,0 +1,25 @@
+package usecase
+
+import "testing"
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Hello World", "hello-world"},
+		{"  Hello,   World!  ", "hello-world"},
+		{"Go 1.25 released", "go-1-25-released"},
+		{"--already-slugged--", "already-slugged"},
+		{"CamelCase Title", "camelcase-title"},
+		{"Café au lait", "caf-au-lait"},
+		{"こんにちは世界", defaultSlug},
+		{"!!!", defaultSlug},
+		{"", defaultSlug},
+	}
+	for _, tt := range tests {
+		if got := Slugify(tt.title); got != tt.want {
+			t.Errorf("Slugify(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
@@ -2,35 +2,113 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"net/mail"
+	"slices"
+	"strings"
 
 	"github.com/para7/nanaket-cms/internal/db"
 	"github.com/para7/nanaket-cms/internal/repository"
 )
 
+// User roles, from most to least privileged
+const (
+	UserRoleAdmin  = "admin"
+	UserRoleEditor = "editor"
+	UserRoleViewer = "viewer"
+)
+
+var (
+	// ErrInvalidUserRole is returned when an unknown user role is given
+	ErrInvalidUserRole = errors.New("invalid user role")
+	// ErrEmailTaken is returned when the email is already used by another user
+	ErrEmailTaken = errors.New("email already taken")
+	// ErrUserNotFound is returned when the referenced user does not exist
+	ErrUserNotFound = errors.New("user not found")
+)
+
+// roleRanks maps each role to its privilege level
+var roleRanks = map[string]int{
+	UserRoleViewer: 1,
+	UserRoleEditor: 2,
+	UserRoleAdmin:  3,
+}
+
+// IsValidUserRole reports whether role is a known user role
+func IsValidUserRole(role string) bool {
+	_, ok := roleRanks[role]
+	return ok
+}
+
+// HasRole reports whether a user with role has at least the privileges of required
+func HasRole(role, required string) bool {
+	rank, ok := roleRanks[role]
+	if !ok {
+		return false
+	}
+	return rank >= roleRanks[required]
+}
+
 // UserUsecase defines the interface for user business logic
 type UserUsecase interface {
-	CreateUser(ctx context.Context, email, name string) (db.User, error)
+	CreateUser(ctx context.Context, email, name, role string) (db.User, error)
 	GetUser(ctx context.Context, id int64) (db.User, error)
 	ListUsers(ctx context.Context) ([]db.User, error)
-	UpdateUser(ctx context.Context, id int64, email, name string) (db.User, error)
+	ListUsersByIDs(ctx context.Context, ids []int64) ([]db.User, error)
+	ListUsersPaginated(ctx context.Context, limit, offset int32) ([]db.User, int64, error)
+	SearchUsers(ctx context.Context, query string, wildcards bool, limit, offset int32) ([]db.User, int64, error)
+	UpdateUser(ctx context.Context, id int64, email, name string) (db.User, string, error)
+	UpsertUserByEmail(ctx context.Context, email, name string) (db.User, bool, error)
 	DeleteUser(ctx context.Context, id int64) error
+	RestoreUser(ctx context.Context, id int64) (db.User, error)
+}
+
+// EmailChangeRequester sends a link that confirms a user's new email address.
+// MagicLinkUsecase implements it.
+type EmailChangeRequester interface {
+	RequestEmailChange(ctx context.Context, userID int64, email string) error
 }
 
 // userUsecase implements UserUsecase interface
 type userUsecase struct {
-	repo repository.UserRepository
+	repo         repository.UserRepository
+	emailChanges EmailChangeRequester
 }
 
-// NewUserUsecase creates a new instance of UserUsecase
-func NewUserUsecase(repo repository.UserRepository) UserUsecase {
+// NewUserUsecase creates a new instance of UserUsecase.
+// New emails given to UpdateUser are confirmed through emailChanges.
+func NewUserUsecase(repo repository.UserRepository, emailChanges EmailChangeRequester) UserUsecase {
 	return &userUsecase{
-		repo: repo,
+		repo:         repo,
+		emailChanges: emailChanges,
 	}
 }
 
 // CreateUser creates a new user
-func (u *userUsecase) CreateUser(ctx context.Context, email, name string) (db.User, error) {
-	return u.repo.Create(ctx, email, name)
+// An empty role creates the user as a viewer
+func (u *userUsecase) CreateUser(ctx context.Context, email, name, role string) (db.User, error) {
+	email, err := normalizeUserInput(email, name)
+	if err != nil {
+		return db.User{}, err
+	}
+	if role == "" {
+		role = UserRoleViewer
+	}
+	if !IsValidUserRole(role) {
+		return db.User{}, ErrInvalidUserRole
+	}
+
+	if err := u.ensureEmailAvailable(ctx, email, 0); err != nil {
+		return db.User{}, err
+	}
+
+	user, err := u.repo.Create(ctx, email, name, role)
+	if errors.Is(err, repository.ErrDuplicateKey) {
+		// Lost a race with a concurrent insert after the pre-check
+		return db.User{}, ErrEmailTaken
+	}
+	return user, err
 }
 
 // GetUser retrieves a user by ID
@@ -43,12 +121,168 @@ func (u *userUsecase) ListUsers(ctx context.Context) ([]db.User, error) {
 	return u.repo.List(ctx)
 }
 
-// UpdateUser updates a user
-func (u *userUsecase) UpdateUser(ctx context.Context, id int64, email, name string) (db.User, error) {
-	return u.repo.Update(ctx, id, email, name)
+// ListUsersByIDs retrieves the users with the given IDs; unknown and soft-deleted IDs are skipped
+func (u *userUsecase) ListUsersByIDs(ctx context.Context, ids []int64) ([]db.User, error) {
+	users, err := u.repo.ListByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(users, func(user db.User) bool { return user.DeletedAt.Valid }), nil
+}
+
+// ListUsersPaginated retrieves a page of users along with the total number of users
+func (u *userUsecase) ListUsersPaginated(ctx context.Context, limit, offset int32) ([]db.User, int64, error) {
+	users, err := u.repo.ListPaginated(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := u.repo.Count(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
 }
 
-// DeleteUser deletes a user
+// SearchUsers retrieves a page of users whose name or email contains query, case-insensitively,
+// along with the number of matching users. The query is matched literally unless wildcards
+// is set, in which case % and _ in it act as LIKE wildcards.
+func (u *userUsecase) SearchUsers(ctx context.Context, query string, wildcards bool, limit, offset int32) ([]db.User, int64, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, 0, ErrEmptySearchQuery
+	}
+	if !wildcards {
+		query = escapeLike(query)
+	}
+	pattern := "%" + query + "%"
+
+	users, err := u.repo.Search(ctx, pattern, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := u.repo.CountSearch(ctx, pattern)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// UpdateUser updates a user's name at once. A new email only replaces the current one
+// once it is confirmed by the link sent to it, so it is returned as pendingEmail and the
+// user keeps the old email until then. The new email must already be free.
+func (u *userUsecase) UpdateUser(ctx context.Context, id int64, email, name string) (user db.User, pendingEmail string, err error) {
+	email, err = normalizeUserInput(email, name)
+	if err != nil {
+		return db.User{}, "", err
+	}
+
+	current, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return db.User{}, "", err
+	}
+	if email != current.Email {
+		if err := u.ensureEmailAvailable(ctx, email, id); err != nil {
+			return db.User{}, "", err
+		}
+		pendingEmail = email
+	}
+
+	user, err = u.repo.Update(ctx, id, current.Email, name)
+	if err != nil {
+		return db.User{}, "", err
+	}
+	if pendingEmail != "" {
+		if err := u.emailChanges.RequestEmailChange(ctx, id, pendingEmail); err != nil {
+			return db.User{}, "", err
+		}
+	}
+	return user, pendingEmail, nil
+}
+
+// UpsertUserByEmail creates a viewer with email and name, or updates the name of the
+// user who already has email. It reports whether the user was created.
+func (u *userUsecase) UpsertUserByEmail(ctx context.Context, email, name string) (db.User, bool, error) {
+	email, err := normalizeUserInput(email, name)
+	if err != nil {
+		return db.User{}, false, err
+	}
+	user, created, err := u.repo.UpsertByEmail(ctx, email, name, UserRoleViewer)
+	if errors.Is(err, sql.ErrNoRows) {
+		// The email belongs to a soft-deleted user, who has to be restored instead
+		return db.User{}, false, ErrEmailTaken
+	}
+	return user, created, err
+}
+
+// DeleteUser soft-deletes a user and revokes their tokens; RestoreUser brings them back.
+// Their articles remain published, with the author marked inactive.
 func (u *userUsecase) DeleteUser(ctx context.Context, id int64) error {
-	return u.repo.Delete(ctx, id)
+	err := u.repo.Delete(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUserNotFound
+	}
+	return err
+}
+
+// RestoreUser undoes a soft delete
+func (u *userUsecase) RestoreUser(ctx context.Context, id int64) (db.User, error) {
+	user, err := u.repo.Restore(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.User{}, ErrUserNotFound
+	}
+	return user, err
+}
+
+// ensureEmailAvailable returns ErrEmailTaken if email belongs to a user other than exceptID
+func (u *userUsecase) ensureEmailAvailable(ctx context.Context, email string, exceptID int64) error {
+	existing, err := u.repo.GetByEmail(ctx, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.ID != exceptID {
+		return ErrEmailTaken
+	}
+	return nil
+}
+
+// normalizeUserInput validates email and name together and returns the normalized email
+func normalizeUserInput(email, name string) (string, error) {
+	v := &ValidationError{}
+	if strings.TrimSpace(name) == "" {
+		v.Add("name", "is required")
+	}
+	email, ok := normalizeEmail(email)
+	if email == "" {
+		v.Add("email", "is required")
+	} else if !ok {
+		v.Add("email", "invalid email format")
+	}
+	return email, v.Err()
+}
+
+// normalizeEmail trims and lowercases an email address so that addresses differing
+// only in case map to the same user, and reports whether it is valid
+func normalizeEmail(email string) (string, bool) {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	// Reject display names ("Foo <foo@x.com>") and anything ParseAddress rewrites
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return email, false
+	}
+
+	// Require a dotted domain to reject addresses like "foo@localhost"
+	domain := email[strings.LastIndex(email, "@")+1:]
+	if !strings.Contains(domain, ".") || strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".") {
+		return email, false
+	}
+
+	return email, true
 }
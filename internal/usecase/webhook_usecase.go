@@ -0,0 +1,225 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/para7/nanaket-cms/internal/db"
+	"github.com/para7/nanaket-cms/internal/repository"
+	"github.com/para7/nanaket-cms/internal/webhook"
+)
+
+// Webhook events
+const (
+	// EventArticleCreated carries the created article
+	EventArticleCreated = "article.created"
+	// EventArticleUpdated carries the updated article, including scheduled publishing
+	EventArticleUpdated = "article.updated"
+	// EventArticleDeleted carries the ID of the deleted article (see DeletedArticle)
+	EventArticleDeleted = "article.deleted"
+)
+
+// webhookEvents lists every event a webhook can subscribe to
+var webhookEvents = []string{EventArticleCreated, EventArticleUpdated, EventArticleDeleted}
+
+// DefaultWebhookTimeout is the default bound on delivering one event to all of its subscribers
+const DefaultWebhookTimeout = 10 * time.Second
+
+// maxWebhookURLLength matches webhooks.url VARCHAR(2048)
+const maxWebhookURLLength = 2048
+
+// minWebhookSecretLength is the shortest secret accepted from clients
+const minWebhookSecretLength = 16
+
+// maxWebhookSecretLength matches webhooks.secret VARCHAR(255)
+const maxWebhookSecretLength = 255
+
+// ErrWebhookNotFound is returned when the referenced webhook does not exist
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+// WebhookUsecase defines the interface for webhook subscriptions and their delivery
+type WebhookUsecase interface {
+	CreateWebhook(ctx context.Context, in WebhookInput) (CreatedWebhook, error)
+	GetWebhook(ctx context.Context, id int64) (db.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]db.Webhook, error)
+	UpdateWebhook(ctx context.Context, id int64, in WebhookInput) (db.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+	Dispatch(ctx context.Context, event string, data any)
+}
+
+// WebhookInput holds the writable fields of a webhook
+type WebhookInput struct {
+	URL string
+	// Secret is generated on create and kept on update when empty
+	Secret string
+	Events []string
+}
+
+// CreatedWebhook is a newly registered webhook together with its secret,
+// which is only ever returned here
+type CreatedWebhook struct {
+	db.Webhook
+	Secret string `json:"secret"`
+}
+
+// WebhookPayload is the JSON body posted to webhooks
+type WebhookPayload struct {
+	Event      string    `json:"event"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+// DeletedArticle is the data of EventArticleDeleted
+type DeletedArticle struct {
+	ID int64 `json:"id"`
+}
+
+// webhookUsecase implements WebhookUsecase interface
+type webhookUsecase struct {
+	repo   repository.WebhookRepository
+	sender webhook.Sender
+	// timeout bounds Dispatch
+	timeout time.Duration
+}
+
+// NewWebhookUsecase creates a new instance of WebhookUsecase.
+// Dispatch delivers through sender and gives up after timeout.
+func NewWebhookUsecase(repo repository.WebhookRepository, sender webhook.Sender, timeout time.Duration) WebhookUsecase {
+	return &webhookUsecase{
+		repo:    repo,
+		sender:  sender,
+		timeout: timeout,
+	}
+}
+
+// CreateWebhook registers a webhook. Without a secret, a random one is generated.
+func (u *webhookUsecase) CreateWebhook(ctx context.Context, in WebhookInput) (CreatedWebhook, error) {
+	if err := normalizeWebhookInput(&in); err != nil {
+		return CreatedWebhook{}, err
+	}
+	if in.Secret == "" {
+		secret, err := generateToken()
+		if err != nil {
+			return CreatedWebhook{}, err
+		}
+		in.Secret = secret
+	}
+
+	hook, err := u.repo.Create(ctx, in.URL, in.Secret, in.Events)
+	if err != nil {
+		return CreatedWebhook{}, err
+	}
+	return CreatedWebhook{Webhook: hook, Secret: hook.Secret}, nil
+}
+
+// GetWebhook retrieves a webhook by ID
+func (u *webhookUsecase) GetWebhook(ctx context.Context, id int64) (db.Webhook, error) {
+	hook, err := u.repo.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.Webhook{}, ErrWebhookNotFound
+	}
+	return hook, err
+}
+
+// ListWebhooks retrieves all webhooks
+func (u *webhookUsecase) ListWebhooks(ctx context.Context) ([]db.Webhook, error) {
+	return u.repo.List(ctx)
+}
+
+// UpdateWebhook replaces the URL and events of a webhook, and its secret if one is given
+func (u *webhookUsecase) UpdateWebhook(ctx context.Context, id int64, in WebhookInput) (db.Webhook, error) {
+	if err := normalizeWebhookInput(&in); err != nil {
+		return db.Webhook{}, err
+	}
+	var secret *string
+	if in.Secret != "" {
+		secret = &in.Secret
+	}
+
+	hook, err := u.repo.Update(ctx, id, in.URL, secret, in.Events)
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.Webhook{}, ErrWebhookNotFound
+	}
+	return hook, err
+}
+
+// DeleteWebhook deletes a webhook
+func (u *webhookUsecase) DeleteWebhook(ctx context.Context, id int64) error {
+	err := u.repo.Delete(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrWebhookNotFound
+	}
+	return err
+}
+
+// Dispatch posts event with data to every webhook subscribed to it, all at once, and returns
+// when every delivery has finished or the timeout has passed.
+// Delivery is best-effort: failures are logged and not retried.
+func (u *webhookUsecase) Dispatch(ctx context.Context, event string, data any) {
+	ctx, cancel := context.WithTimeout(ctx, u.timeout)
+	defer cancel()
+
+	hooks, err := u.repo.ListByEvent(ctx, event)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to load webhooks", "event", event, "error", err)
+		return
+	}
+	if len(hooks) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(WebhookPayload{Event: event, OccurredAt: time.Now().UTC(), Data: data})
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to encode webhook payload", "event", event, "error", err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, hook := range hooks {
+		wg.Go(func() {
+			err := u.sender.Send(ctx, webhook.Delivery{URL: hook.Url, Secret: hook.Secret, Event: event, Body: body})
+			if err != nil {
+				slog.WarnContext(ctx, "Failed to deliver webhook", "webhook_id", hook.ID, "event", event, "error", err)
+			}
+		})
+	}
+	wg.Wait()
+}
+
+// normalizeWebhookInput trims and validates the URL, secret and events, and removes duplicate events
+func normalizeWebhookInput(in *WebhookInput) error {
+	v := &ValidationError{}
+	in.URL = strings.TrimSpace(in.URL)
+	if in.URL == "" {
+		v.Add("url", "is required")
+	} else if u, err := url.Parse(in.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		v.Add("url", "must be an absolute http or https URL")
+	} else if len(in.URL) > maxWebhookURLLength {
+		v.Add("url", "is too long")
+	}
+
+	if in.Secret != "" && (len(in.Secret) < minWebhookSecretLength || len(in.Secret) > maxWebhookSecretLength) {
+		v.Add("secret", fmt.Sprintf("must be between %d and %d bytes", minWebhookSecretLength, maxWebhookSecretLength))
+	}
+
+	if len(in.Events) == 0 {
+		v.Add("events", "is required")
+	}
+	for _, event := range in.Events {
+		if !slices.Contains(webhookEvents, event) {
+			v.Add("events", fmt.Sprintf("must only contain %s", strings.Join(webhookEvents, ", ")))
+			break
+		}
+	}
+	in.Events = slices.Compact(slices.Sorted(slices.Values(in.Events)))
+	return v.Err()
+}
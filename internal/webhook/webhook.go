@@ -0,0 +1,88 @@
+// Package webhook delivers signed event payloads to subscriber URLs over HTTP.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	// SignatureHeader carries the signature of the request body (see Sign)
+	SignatureHeader = "X-Signature"
+	// EventHeader carries the event name, which is also in the body
+	EventHeader = "X-Webhook-Event"
+)
+
+// maxResponseBytes is how much of a subscriber's response body is read before the connection is reused
+const maxResponseBytes = 64 << 10
+
+// Delivery is one payload to post to one subscriber
+type Delivery struct {
+	URL    string
+	Secret string
+	Event  string
+	Body   []byte
+}
+
+// Sender delivers payloads.
+// Implementations must be safe for concurrent use.
+type Sender interface {
+	// Send posts d, returning an error unless the subscriber accepted it
+	Send(ctx context.Context, d Delivery) error
+}
+
+// Sign returns the signature sent in SignatureHeader: "sha256=" followed by the hex-encoded
+// HMAC-SHA256 of body keyed with secret. Subscribers recompute it to verify the sender.
+func Sign(secret string, body []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write(body)
+	return "sha256=" + hex.EncodeToString(h.Sum(nil))
+}
+
+// HTTPSender is a Sender that POSTs JSON payloads
+type HTTPSender struct {
+	client *http.Client
+}
+
+// NewHTTPSender returns an HTTPSender whose requests each take at most timeout.
+// Redirects are not followed, so a subscriber cannot bounce a delivery to another host.
+func NewHTTPSender(timeout time.Duration) *HTTPSender {
+	return &HTTPSender{
+		client: &http.Client{
+			Timeout: timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Send posts d.Body to d.URL with its signature. Any status other than 2xx is an error.
+func (s *HTTPSender) Send(ctx context.Context, d Delivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Body))
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, d.Event)
+	req.Header.Set(SignatureHeader, Sign(d.Secret, d.Body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBytes))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook: %s answered %s", d.URL, resp.Status)
+	}
+	return nil
+}